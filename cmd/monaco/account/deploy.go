@@ -61,7 +61,7 @@ func deployCommand(fs afero.Fs) *cobra.Command {
 
 			opts.workingDir = filepath.Dir(opts.manifestName)
 
-			return deploy(fs, opts)
+			return deploy(cmd.Context(), fs, opts)
 		},
 	}
 
@@ -73,7 +73,7 @@ func deployCommand(fs afero.Fs) *cobra.Command {
 	return command
 }
 
-func deploy(fs afero.Fs, opts deployOpts) error {
+func deploy(ctx context.Context, fs afero.Fs, opts deployOpts) error {
 
 	mani, errs := manifestloader.Load(&manifestloader.Context{
 		Fs:           fs,
@@ -125,7 +125,7 @@ func deploy(fs afero.Fs, opts deployOpts) error {
 		return fmt.Errorf("failed to create account clients: %w", err)
 	}
 
-	supportedPermissions, err := deployer.FetchAvailablePermissionIDs(context.Background(), &http.Client{}, "https://api.dynatrace.com/spec-json")
+	supportedPermissions, err := deployer.FetchAvailablePermissionIDs(ctx, &http.Client{}, "https://api.dynatrace.com/spec-json")
 	if err != nil {
 		return fmt.Errorf("failed to fetch supportedPermissions: %w", err)
 	}
@@ -133,16 +133,24 @@ func deploy(fs afero.Fs, opts deployOpts) error {
 	maxConcurrentDeploys := environment.GetEnvValueInt(environment.ConcurrentRequestsEnvKey)
 
 	for accInfo, accClient := range accountClients {
+		if ctx.Err() != nil {
+			log.Warn("Skipping deployment to account %q, as the deployment was cancelled", accInfo.Name)
+			continue
+		}
+
 		logger := log.WithFields(field.F("account", accInfo.Name))
 		accountDeployer := deployer.NewAccountDeployer(deployer.NewClient(accInfo, accClient, supportedPermissions), deployer.WithMaxConcurrentDeploys(maxConcurrentDeploys))
 		logger.Info("Deploying configuration for account: %s", accInfo.Name)
 		logger.Info("Number of users to deploy: %d", len(resources.Users))
 		logger.Info("Number of groups to deploy: %d", len(resources.Groups))
 		logger.Info("Number of policies to deploy: %d", len(resources.Policies))
-		if err = accountDeployer.Deploy(resources); err != nil {
+		if err = accountDeployer.Deploy(ctx, resources); err != nil {
 			return err
 		}
 	}
 
+	if ctx.Err() != nil {
+		return fmt.Errorf("deployment cancelled: %w", ctx.Err())
+	}
 	return nil
 }
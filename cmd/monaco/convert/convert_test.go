@@ -19,6 +19,7 @@
 package convert
 
 import (
+	"context"
 	"fmt"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/featureflags"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/json"
@@ -148,7 +149,7 @@ profile:
 
 	cfg := cfgs[0]
 
-	props, errs := cfg.ResolveParameterValues(emptyEntityLookup{})
+	props, errs := cfg.ResolveParameterValues(context.TODO(), emptyEntityLookup{})
 	assert.Empty(t, errs)
 	render, err := cfg.Render(props)
 	assert.NoError(t, err)
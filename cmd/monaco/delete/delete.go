@@ -29,16 +29,22 @@ import (
 // Delete removes configurations from multiple Dynatrace environments based on the specified deletion entries.
 //
 // Parameters:
+//   - ctx: Once ctx is cancelled, no further environments and types of configurations are deleted.
 //   - environments: A list of Dynatrace environments to perform the deletion on.
 //   - entriesToDelete: Deletion entries specifying what configurations to remove.
 //
 // Returns:
 //   - error: If an error occurs during the deletion process, an error is returned, describing the issue.
 //     If no errors occur, nil is returned.
-func Delete(environments manifest.Environments, entriesToDelete delete.DeleteEntries) error {
-	var envsWithDeleteErrs []string
+func Delete(ctx context.Context, environments manifest.Environments, entriesToDelete delete.DeleteEntries) error {
+	var envsWithDeleteErrs, cancelledEnvs []string
 	for _, env := range environments {
-		ctx := context.WithValue(context.TODO(), log.CtxKeyEnv{}, log.CtxValEnv{Name: env.Name, Group: env.Group})
+		if ctx.Err() != nil {
+			cancelledEnvs = append(cancelledEnvs, env.Name)
+			continue
+		}
+
+		ctx := context.WithValue(ctx, log.CtxKeyEnv{}, log.CtxValEnv{Name: env.Name, Group: env.Group})
 		if containsPlatformTypes(entriesToDelete) && env.Auth.OAuth == nil {
 			log.WithCtxFields(ctx).Warn("Delete file contains Dynatrace Platform specific types, but no oAuth credentials are defined for environment %q - Dynatrace Platform configurations won't be deleted.", env.Name)
 		}
@@ -70,6 +76,13 @@ func Delete(environments manifest.Environments, entriesToDelete delete.DeleteEnt
 		}
 	}
 
+	if ctx.Err() != nil {
+		if len(cancelledEnvs) > 0 {
+			log.Warn("Deletion was cancelled - skipped environments: %s", strings.Join(cancelledEnvs, ", "))
+		}
+		return fmt.Errorf("deletion cancelled: %w", ctx.Err())
+	}
+
 	if len(envsWithDeleteErrs) > 0 {
 		return fmt.Errorf("encountered deletion errors for the following environments: %v", strings.Join(envsWithDeleteErrs, ", "))
 	}
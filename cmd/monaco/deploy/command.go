@@ -20,16 +20,24 @@ import (
 	"fmt"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/cmd/monaco/cmdutils"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/cmd/monaco/completion"
+	environmentvars "github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/environment"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/files"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/log"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/coordinate"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/deploy"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/deploy/report"
 	"github.com/spf13/afero"
 	"github.com/spf13/cobra"
+	"slices"
+	"time"
 )
 
 func GetDeployCommand(fs afero.Fs) (deployCmd *cobra.Command) {
-	var dryRun, continueOnError bool
-	var manifestName string
-	var environment, project, groups []string
+	var dryRun, continueOnError, plan, prune, skipUnchanged, withDependents bool
+	var manifestName, reportFile, reportFormat, checkpointFile, resumeFile string
+	var environment, project, groups, configs []string
+	var concurrentEnvironments, concurrentConfigs, retries int
+	var retryBackoff time.Duration
 
 	deployCmd = &cobra.Command{
 		Use:               "deploy <manifest.yaml>",
@@ -47,7 +55,53 @@ func GetDeployCommand(fs afero.Fs) (deployCmd *cobra.Command) {
 				return err
 			}
 
-			return deployConfigs(fs, manifestName, groups, environment, project, continueOnError, dryRun)
+			if !slices.Contains(report.Formats, report.Format(reportFormat)) {
+				return fmt.Errorf("unknown report format %q, expected one of %v", reportFormat, report.Formats)
+			}
+
+			var configPatterns []coordinate.Pattern
+			for _, c := range configs {
+				p, err := coordinate.ParsePattern(c)
+				if err != nil {
+					return err
+				}
+				configPatterns = append(configPatterns, p)
+			}
+
+			if !cmd.Flags().Changed("concurrent-environments") {
+				concurrentEnvironments = environmentvars.GetEnvValueIntLog(environmentvars.ConcurrentEnvironmentsEnvKey)
+			}
+			if !cmd.Flags().Changed("concurrent-configs") {
+				concurrentConfigs = environmentvars.GetEnvValueIntLog(environmentvars.ConcurrentConfigsEnvKey)
+			}
+			if retries < 0 {
+				return fmt.Errorf("the number of retries must not be negative, but is %d", retries)
+			}
+			if concurrentConfigs < 1 {
+				return fmt.Errorf("at least one configuration must be deployed at the same time, but the limit is %d", concurrentConfigs)
+			}
+
+			return deployConfigs(cmd.Context(), fs, deployOptions{
+				manifestPath:           manifestName,
+				environmentGroups:      groups,
+				specificEnvironments:   environment,
+				specificProjects:       project,
+				specificConfigs:        configPatterns,
+				withDependents:         withDependents,
+				continueOnErr:          continueOnError,
+				dryRun:                 dryRun,
+				plan:                   plan,
+				prune:                  prune,
+				skipUnchanged:          skipUnchanged,
+				concurrentEnvironments: concurrentEnvironments,
+				concurrentConfigs:      concurrentConfigs,
+				retries:                retries,
+				retryBackoff:           retryBackoff,
+				reportFile:             reportFile,
+				checkpointFile:         checkpointFile,
+				resumeFile:             resumeFile,
+				reportFormat:           report.Format(reportFormat),
+			})
 		},
 	}
 
@@ -61,8 +115,22 @@ func GetDeployCommand(fs afero.Fs) (deployCmd *cobra.Command) {
 			"If this flag is specified, all environments within this group will be used for deployment. "+
 			"This flag is mutually exclusive with '--environment'")
 	deployCmd.Flags().StringSliceVarP(&project, "project", "p", make([]string, 0), "Project configuration to deploy (also deploys any dependent configurations)")
+	deployCmd.Flags().StringArrayVar(&configs, "config", []string{}, "Configuration to deploy, given as 'project:type:configId'. The configId may be a glob pattern, e.g. 'my-project:dashboard:team-*'. All configurations it depends on are deployed as well. Repeat this flag to deploy multiple configurations.")
+	deployCmd.Flags().BoolVar(&withDependents, "with-dependents", false, "Deploy all configurations depending on the configurations selected by '--config' as well.")
 	deployCmd.Flags().BoolVarP(&dryRun, "dry-run", "d", false, "Validate the structure of your manifest, projects and configurations. Dry-run will resolve all configuration parameters and render JSON templates, but can not validate the content of JSON payloads. After a successful dry-run, deployments may still fail with Dynatrace API errors if the content of JSONs is not valid.")
 	deployCmd.Flags().BoolVarP(&continueOnError, "continue-on-error", "c", false, "Proceed deployment even if individual configuration deployments fail.")
+	deployCmd.Flags().IntVar(&concurrentEnvironments, "concurrent-environments", 1, fmt.Sprintf("Maximum number of environments deployed at the same time. A value of 0 deploys all environments at once. If not set, the value of the %q environment variable is used, or 1 if that is not set either.", environmentvars.ConcurrentEnvironmentsEnvKey))
+	deployCmd.Flags().IntVar(&concurrentConfigs, "concurrent-configs", deploy.DefaultMaxConcurrentConfigs, fmt.Sprintf("Maximum number of configurations deployed at the same time to a single environment. A configuration is deployed as soon as all configurations it depends on are deployed. If not set, the value of the %q environment variable is used, or %d if that is not set either.", environmentvars.ConcurrentConfigsEnvKey, deploy.DefaultMaxConcurrentConfigs))
+	deployCmd.Flags().IntVar(&retries, "retries", 1, "Number of times configurations that failed to deploy, and all configurations skipped because of them, are deployed again after all other configurations of their environment were deployed. A value of 0 disables retries.")
+	deployCmd.Flags().DurationVar(&retryBackoff, "retry-backoff", 10*time.Second, "Time to wait before retrying failed configurations. It doubles with each further retry.")
+	deployCmd.Flags().BoolVar(&plan, "plan", false, "Compare the rendered configurations with the objects currently present in the environments and report which would be created, updated or are unchanged, including a diff of changed values. Nothing is deployed when planning.")
+
+	deployCmd.Flags().BoolVar(&prune, "prune", false, "After a successful deployment, delete all objects previously deployed from the loaded projects for which no configuration exists anymore. Settings objects are identified by their monaco externalId, all other objects require a deployment state file to be defined in the manifest. Combined with '--dry-run', objects to be deleted are only reported.")
+	deployCmd.Flags().BoolVar(&skipUnchanged, "skip-unchanged", false, "Compare each configuration with the object it is deployed to, and skip writing it if the object is equal to it. This requires reading each object before deploying it, but avoids needless changes of unchanged objects.")
+	deployCmd.Flags().StringVar(&checkpointFile, "checkpoint", "", "Write a checkpoint listing all successfully deployed configurations to this file if the deployment fails or is cancelled. The file is removed once a deployment succeeds.")
+	deployCmd.Flags().StringVar(&resumeFile, "resume", "", "Resume a deployment from the given checkpoint file. Configurations listed in the checkpoint are not deployed again, unless their payload changed. Unless '--checkpoint' is set, the checkpoint is updated in place.")
+	deployCmd.Flags().StringVar(&reportFile, "report-file", "", "Write a report containing the result of deploying each configuration to this file. The report is written even if the deployment fails.")
+	deployCmd.Flags().StringVar(&reportFormat, "report-format", string(report.FormatJSON), fmt.Sprintf("Format of the report written to '--report-file', one of %v. JUnit XML reports contain a test suite per environment and a test case per configuration.", report.Formats))
 
 	err := deployCmd.RegisterFlagCompletionFunc("environment", completion.EnvironmentByManifestFlag)
 	if err != nil {
@@ -75,6 +143,15 @@ func GetDeployCommand(fs afero.Fs) (deployCmd *cobra.Command) {
 	}
 
 	deployCmd.MarkFlagsMutuallyExclusive("environment", "group")
+	deployCmd.MarkFlagsMutuallyExclusive("plan", "dry-run")
+	deployCmd.MarkFlagsMutuallyExclusive("plan", "prune")
+	deployCmd.MarkFlagsMutuallyExclusive("plan", "report-file")
+	deployCmd.MarkFlagsMutuallyExclusive("plan", "skip-unchanged")
+	deployCmd.MarkFlagsMutuallyExclusive("plan", "config")
+	deployCmd.MarkFlagsMutuallyExclusive("plan", "checkpoint")
+	deployCmd.MarkFlagsMutuallyExclusive("plan", "resume")
+	deployCmd.MarkFlagsMutuallyExclusive("dry-run", "checkpoint")
+	deployCmd.MarkFlagsMutuallyExclusive("dry-run", "resume")
 
 	return deployCmd
 }
@@ -15,6 +15,7 @@
 package deploy
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/cmd/monaco/deploy/internal/logging"
@@ -22,33 +23,74 @@ import (
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/errutils"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/log"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/log/field"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/checkpoint"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/deploy"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/deploy/report"
 	manifestloader "github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/manifest/loader"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/state"
+	"os"
 	"path/filepath"
+	"strings"
+	"time"
 
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/api"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/coordinate"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/parameter/secret"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/manifest"
 	project "github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/project/v2"
 	"github.com/spf13/afero"
 )
 
-func deployConfigs(fs afero.Fs, manifestPath string, environmentGroups []string, specificEnvironments []string, specificProjects []string, continueOnErr bool, dryRun bool) error {
-	absManifestPath, err := absPath(manifestPath)
+type deployOptions struct {
+	manifestPath         string
+	environmentGroups    []string
+	specificEnvironments []string
+	specificProjects     []string
+	// specificConfigs limits the deployment to the matching configurations and their dependencies
+	specificConfigs []coordinate.Pattern
+	// withDependents deploys all configurations depending on specificConfigs as well
+	withDependents bool
+	continueOnErr  bool
+	dryRun         bool
+	plan           bool
+	// prune deletes objects previously deployed by monaco that have no configuration anymore
+	prune bool
+	// skipUnchanged skips writing configurations equal to the objects they are deployed to
+	skipUnchanged bool
+	// concurrentEnvironments is the maximum number of environments deployed at the same time
+	concurrentEnvironments int
+	// concurrentConfigs is the maximum number of configurations deployed at the same time to a single environment
+	concurrentConfigs int
+	// retries is the number of times failed configurations are deployed again, waiting retryBackoff before the first retry
+	retries      int
+	retryBackoff time.Duration
+	// reportFile is the path the deployment report is written to. No report is written if it is empty.
+	reportFile   string
+	reportFormat report.Format
+	// checkpointFile is the path a checkpoint is written to if the deployment fails. No checkpoint is written if both
+	// checkpointFile and resumeFile are empty.
+	checkpointFile string
+	// resumeFile is the path of the checkpoint the deployment is resumed from
+	resumeFile string
+}
+
+func deployConfigs(ctx context.Context, fs afero.Fs, opts deployOptions) error {
+	absManifestPath, err := absPath(opts.manifestPath)
 	if err != nil {
-		return fmt.Errorf("error while finding absolute path for `%s`: %w", manifestPath, err)
+		return fmt.Errorf("error while finding absolute path for `%s`: %w", opts.manifestPath, err)
 	}
-	loadedManifest, err := loadManifest(fs, absManifestPath, environmentGroups, specificEnvironments)
+	loadedManifest, err := loadManifest(fs, absManifestPath, opts.environmentGroups, opts.specificEnvironments)
 	if err != nil {
 		return err
 	}
 
-	ok := verifyEnvironmentGen(loadedManifest.Environments, dryRun)
+	ok := verifyEnvironmentGen(loadedManifest.Environments, opts.dryRun)
 	if !ok {
 		return fmt.Errorf("unable to verify Dynatrace environment generation")
 	}
 
-	loadedProjects, err := loadProjects(fs, absManifestPath, loadedManifest, specificProjects)
+	loadedProjects, err := loadProjects(fs, absManifestPath, loadedManifest, opts.specificProjects)
 	if err != nil {
 		return err
 	}
@@ -65,12 +107,153 @@ func deployConfigs(fs afero.Fs, manifestPath string, environmentGroups []string,
 		return fmt.Errorf("failed to create API clients: %w", err)
 	}
 
-	err = deploy.Deploy(loadedProjects, clientSets, deploy.DeployConfigsOptions{ContinueOnErr: continueOnErr, DryRun: dryRun})
+	var deploymentState *state.State
+	var statePath string
+	if loadedManifest.State.Enabled() {
+		statePath = state.ResolvePath(absManifestPath, loadedManifest.State.Path)
+		if deploymentState, err = state.Load(fs, statePath); err != nil {
+			return fmt.Errorf("failed to load deployment state: %w", err)
+		}
+	}
+
+	if opts.plan {
+		changes, err := deploy.Plan(ctx, loadedProjects, clientSets, deploymentState)
+		logging.LogPlan(changes)
+		if err != nil {
+			return fmt.Errorf("planning failed - check logs for details: %w", err)
+		}
+
+		log.Info("Planning finished without errors")
+		return nil
+	}
+
+	deploymentCheckpoint, checkpointPath, err := loadCheckpoint(fs, opts)
+	if err != nil {
+		return err
+	}
+	if deploymentCheckpoint != nil && !opts.dryRun {
+		// the checkpoint is written while deploying, so that the progress is kept even if monaco is killed
+		deploymentCheckpoint.Autosave(fs, checkpointPath, checkpointInterval)
+	}
+
+	// the report is always recorded, to print a summary even if the deployment fails or is cancelled
+	deploymentReport := report.NewRecorder()
+
+	stages := rolloutStages(loadedManifest.Environments, loadedManifest.Rollouts)
+	// all stages share the cache of resolved secrets, so that each secret is loaded only once per deployment
+	err = deployInStages(secret.WithCache(ctx), stages, filepath.Dir(absManifestPath), opts.dryRun, opts.continueOnErr, func(ctx context.Context, envs manifest.Environments) error {
+		stageClients := make(dynatrace.EnvironmentClients, len(envs))
+		for env, clients := range clientSets {
+			if _, found := envs[env.Name]; found {
+				stageClients[env] = clients
+			}
+		}
+
+		return deploy.Deploy(ctx, loadedProjects, stageClients, deploy.DeployConfigsOptions{
+			ContinueOnErr:             opts.continueOnErr,
+			DryRun:                    opts.dryRun,
+			MaxConcurrentEnvironments: opts.concurrentEnvironments,
+			MaxConcurrentConfigs:      opts.concurrentConfigs,
+			Retries:                   opts.retries,
+			RetryBackoff:              opts.retryBackoff,
+			SkipUnchanged:             opts.skipUnchanged,
+			Configs:                   opts.specificConfigs,
+			WithDependents:            opts.withDependents,
+			State:                     deploymentState,
+			Checkpoint:                deploymentCheckpoint,
+			Report:                    deploymentReport,
+		})
+	})
+
+	var pruneErr error
+	if opts.prune {
+		if err != nil || ctx.Err() != nil {
+			log.Warn("Skipping pruning, as the %s failed", strings.ToLower(logging.GetOperationNounForLogging(opts.dryRun)))
+		} else {
+			_, pruneErr = deploy.Prune(ctx, loadedProjects, clientSets, deploy.PruneOptions{
+				DryRun: opts.dryRun,
+				State:  deploymentState,
+			})
+		}
+	}
+
+	// the state is written even if the deployment failed, to remember all configurations that were deployed successfully
+	if deploymentState != nil && !opts.dryRun {
+		if writeErr := deploymentState.Write(fs, statePath); writeErr != nil {
+			return fmt.Errorf("failed to write deployment state: %w", errors.Join(writeErr, err, pruneErr))
+		}
+		log.WithFields(field.F("statePath", statePath)).Debug("Wrote deployment state to %q", statePath)
+	}
+
+	if deploymentCheckpoint != nil {
+		if checkpointErr := updateCheckpoint(fs, deploymentCheckpoint, checkpointPath, err != nil || ctx.Err() != nil); checkpointErr != nil {
+			return errors.Join(checkpointErr, err, pruneErr)
+		}
+	}
+
+	if !opts.dryRun {
+		logging.LogDeploymentSummary(deploymentReport.Records())
+	}
+
+	// the report is written even if the deployment failed, as it contains the details of failed deployments
+	if opts.reportFile != "" {
+		if writeErr := deploymentReport.Write(fs, opts.reportFile, opts.reportFormat); writeErr != nil {
+			return fmt.Errorf("failed to write deployment report: %w", errors.Join(writeErr, err, pruneErr))
+		}
+		log.WithFields(field.F("reportFile", opts.reportFile)).Info("Deployment report written to %q", opts.reportFile)
+	}
+
+	if err != nil {
+		return fmt.Errorf("%v failed - check logs for details: %w", logging.GetOperationNounForLogging(opts.dryRun), err)
+	}
+	if pruneErr != nil {
+		return fmt.Errorf("pruning failed - check logs for details: %w", pruneErr)
+	}
+
+	log.Info("%s finished without errors", logging.GetOperationNounForLogging(opts.dryRun))
+	return nil
+}
+
+// checkpointInterval is the minimum time between two writes of the checkpoint while deploying
+const checkpointInterval = 5 * time.Second
+
+// loadCheckpoint returns the checkpoint the deployment is resumed from, or a new one if a checkpoint shall be written,
+// and the path the checkpoint is written to. The returned checkpoint is nil if no checkpoint is used.
+func loadCheckpoint(fs afero.Fs, opts deployOptions) (*checkpoint.Checkpoint, string, error) {
+	path := opts.checkpointFile
+	if opts.resumeFile == "" {
+		if path == "" {
+			return nil, "", nil
+		}
+		return checkpoint.New(), path, nil
+	}
+
+	c, err := checkpoint.Load(fs, opts.resumeFile)
 	if err != nil {
-		return fmt.Errorf("%v failed - check logs for details: %w", logging.GetOperationNounForLogging(dryRun), err)
+		return nil, "", fmt.Errorf("failed to load checkpoint to resume from: %w", err)
 	}
+	log.WithFields(field.F("checkpointFile", opts.resumeFile)).Info("Resuming deployment from checkpoint %q", opts.resumeFile)
 
-	log.Info("%s finished without errors", logging.GetOperationNounForLogging(dryRun))
+	if path == "" {
+		path = opts.resumeFile
+	}
+	return c, path, nil
+}
+
+// updateCheckpoint writes the checkpoint to the given path if the deployment is incomplete, and removes the checkpoint
+// file at the path once the deployment succeeded
+func updateCheckpoint(fs afero.Fs, c *checkpoint.Checkpoint, path string, incomplete bool) error {
+	if incomplete {
+		if err := c.Write(fs, path); err != nil {
+			return fmt.Errorf("failed to write deployment checkpoint: %w", err)
+		}
+		log.WithFields(field.F("checkpointFile", path)).Info("Deployment checkpoint written to %q - use '--resume %s' to continue the deployment", path, path)
+		return nil
+	}
+
+	if err := fs.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to remove deployment checkpoint %q: %w", path, err)
+	}
 	return nil
 }
 
@@ -17,6 +17,7 @@
 package deploy
 
 import (
+	"context"
 	"github.com/spf13/afero"
 	"github.com/stretchr/testify/assert"
 	"path/filepath"
@@ -47,7 +48,7 @@ func Test_DoDeploy_InvalidManifest(t *testing.T) {
 	manifestPath, _ := filepath.Abs("manifest.yaml")
 	_ = afero.WriteFile(testFs, manifestPath, []byte(manifestYaml), 0644)
 
-	err := deployConfigs(testFs, manifestPath, []string{}, []string{}, []string{}, true, true)
+	err := deployConfigs(context.TODO(), testFs, deployOptions{manifestPath: manifestPath, continueOnErr: true, dryRun: true})
 	assert.Error(t, err)
 }
 
@@ -88,27 +89,62 @@ environmentGroups:
 	_ = afero.WriteFile(testFs, manifestPath, []byte(manifestYaml), 0644)
 
 	t.Run("Wrong environment group", func(t *testing.T) {
-		err := deployConfigs(testFs, manifestPath, []string{"NOT_EXISTING_GROUP"}, []string{}, []string{}, true, true)
+		err := deployConfigs(context.TODO(), testFs, deployOptions{manifestPath: manifestPath, environmentGroups: []string{"NOT_EXISTING_GROUP"}, continueOnErr: true, dryRun: true})
 		assert.Error(t, err)
 	})
 	t.Run("Wrong environment name", func(t *testing.T) {
-		err := deployConfigs(testFs, manifestPath, []string{"default"}, []string{"NOT_EXISTING_ENV"}, []string{}, true, true)
+		err := deployConfigs(context.TODO(), testFs, deployOptions{manifestPath: manifestPath, environmentGroups: []string{"default"}, specificEnvironments: []string{"NOT_EXISTING_ENV"}, continueOnErr: true, dryRun: true})
 		assert.Error(t, err)
 	})
 
 	t.Run("Wrong project name", func(t *testing.T) {
-		err := deployConfigs(testFs, manifestPath, []string{"default"}, []string{"project"}, []string{"NON_EXISTING_PROJECT"}, true, true)
+		err := deployConfigs(context.TODO(), testFs, deployOptions{manifestPath: manifestPath, environmentGroups: []string{"default"}, specificEnvironments: []string{"project"}, specificProjects: []string{"NON_EXISTING_PROJECT"}, continueOnErr: true, dryRun: true})
 		assert.Error(t, err)
 	})
 
 	t.Run("no parameters", func(t *testing.T) {
-		err := deployConfigs(testFs, manifestPath, []string{}, []string{}, []string{}, true, true)
+		err := deployConfigs(context.TODO(), testFs, deployOptions{manifestPath: manifestPath, continueOnErr: true, dryRun: true})
 		assert.NoError(t, err)
 	})
 
 	t.Run("correct parameters", func(t *testing.T) {
-		err := deployConfigs(testFs, manifestPath, []string{"default"}, []string{"project"}, []string{"project"}, true, true)
+		err := deployConfigs(context.TODO(), testFs, deployOptions{manifestPath: manifestPath, environmentGroups: []string{"default"}, specificEnvironments: []string{"project"}, specificProjects: []string{"project"}, continueOnErr: true, dryRun: true})
 		assert.NoError(t, err)
 	})
 
 }
+
+func TestLoadAndUpdateCheckpoint(t *testing.T) {
+	t.Run("no checkpoint is used by default", func(t *testing.T) {
+		c, path, err := loadCheckpoint(afero.NewMemMapFs(), deployOptions{})
+		assert.NoError(t, err)
+		assert.Nil(t, c)
+		assert.Empty(t, path)
+	})
+
+	t.Run("checkpoint is written if the deployment is incomplete, and removed once it succeeded", func(t *testing.T) {
+		fs := afero.NewMemMapFs()
+		c, path, err := loadCheckpoint(fs, deployOptions{checkpointFile: "checkpoint.json"})
+		assert.NoError(t, err)
+		assert.NotNil(t, c)
+		assert.Equal(t, "checkpoint.json", path)
+
+		assert.NoError(t, updateCheckpoint(fs, c, path, true))
+		exists, _ := afero.Exists(fs, path)
+		assert.True(t, exists)
+
+		resumed, resumedPath, err := loadCheckpoint(fs, deployOptions{resumeFile: path})
+		assert.NoError(t, err)
+		assert.NotNil(t, resumed)
+		assert.Equal(t, path, resumedPath, "the checkpoint resumed from must be updated by default")
+
+		assert.NoError(t, updateCheckpoint(fs, resumed, resumedPath, false))
+		exists, _ = afero.Exists(fs, path)
+		assert.False(t, exists)
+	})
+
+	t.Run("resuming fails if the checkpoint does not exist", func(t *testing.T) {
+		_, _, err := loadCheckpoint(afero.NewMemMapFs(), deployOptions{resumeFile: "checkpoint.json"})
+		assert.ErrorContains(t, err, "failed to load checkpoint to resume from")
+	})
+}
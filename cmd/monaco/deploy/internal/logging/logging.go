@@ -19,8 +19,12 @@ package logging
 import (
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/log"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/loggers"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/deploy"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/deploy/report"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/manifest"
 	project "github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/project/v2"
+	"golang.org/x/exp/maps"
+	"slices"
 )
 
 func LogProjectsInfo(projects []project.Project) {
@@ -68,3 +72,50 @@ func GetOperationNounForLogging(dryRun bool) string {
 	}
 	return "Deployment"
 }
+
+// LogPlan prints a summary of the planned changes per environment
+func LogPlan(changes []deploy.PlannedChange) {
+	actionsPerEnv := make(map[string]map[deploy.PlanAction]int)
+	for _, c := range changes {
+		if actionsPerEnv[c.Environment] == nil {
+			actionsPerEnv[c.Environment] = make(map[deploy.PlanAction]int)
+		}
+		actionsPerEnv[c.Environment][c.Action]++
+	}
+
+	log.Info("Planned changes per environment:")
+	envs := maps.Keys(actionsPerEnv)
+	slices.Sort(envs)
+	for _, env := range envs {
+		a := actionsPerEnv[env]
+		log.Info("  - %s:\t%d to create, %d to update, %d unchanged, %d skipped", env, a[deploy.PlanActionCreate], a[deploy.PlanActionUpdate], a[deploy.PlanActionUnchanged], a[deploy.PlanActionSkip])
+	}
+}
+
+// LogDeploymentSummary prints how many configurations were deployed, unchanged, failed, skipped or cancelled per
+// environment
+func LogDeploymentSummary(records []report.Record) {
+	if len(records) == 0 {
+		return
+	}
+
+	statusPerEnv := make(map[string]map[report.Status]int)
+	succeededOnRetryPerEnv := make(map[string]int)
+	for _, r := range records {
+		if statusPerEnv[r.Environment] == nil {
+			statusPerEnv[r.Environment] = make(map[report.Status]int)
+		}
+		statusPerEnv[r.Environment][r.Status]++
+		if r.Retries > 0 && (r.Status == report.StatusDeployed || r.Status == report.StatusUnchanged || r.Status == report.StatusRestored) {
+			succeededOnRetryPerEnv[r.Environment]++
+		}
+	}
+
+	log.Info("Deployment summary per environment:")
+	envs := maps.Keys(statusPerEnv)
+	slices.Sort(envs)
+	for _, env := range envs {
+		s := statusPerEnv[env]
+		log.Info("  - %s:\t%d deployed, %d unchanged, %d restored from checkpoint, %d failed, %d skipped, %d cancelled, %d succeeded on retry", env, s[report.StatusDeployed], s[report.StatusUnchanged], s[report.StatusRestored], s[report.StatusFailed], s[report.StatusSkipped], s[report.StatusCancelled], succeededOnRetryPerEnv[env])
+	}
+}
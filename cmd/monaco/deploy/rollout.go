@@ -0,0 +1,170 @@
+// @license
+// Copyright 2024 Dynatrace LLC
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deploy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/log"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/manifest"
+	"golang.org/x/exp/maps"
+	"os"
+	"os/exec"
+	"runtime"
+	"slices"
+	"strings"
+	"time"
+)
+
+// stage is a set of environments that are deployed at the same time
+type stage struct {
+	// group the stage belongs to. It is empty for the stage of all environments whose group has no rollout.
+	group string
+	name  string
+	// environments deployed in the stage. Only environments that are deployed at all are contained.
+	environments manifest.Environments
+	// gate that must pass before the next stage is deployed
+	gate manifest.Gate
+}
+
+func (s stage) String() string {
+	if s.group == "" {
+		return "environments without rollout"
+	}
+	return fmt.Sprintf("stage %q of group %q", s.name, s.group)
+}
+
+// rolloutStages splits the given environments into the stages they are deployed in. All environments whose group has
+// no rollout are deployed in a first stage. They are followed by the stages of each rollout, ordered by group name.
+// Stages without any environment to deploy are left out.
+func rolloutStages(environments manifest.Environments, rollouts map[string]manifest.Rollout) []stage {
+	withoutRollout := stage{environments: manifest.Environments{}}
+	for name, env := range environments {
+		if _, found := rollouts[env.Group]; !found {
+			withoutRollout.environments[name] = env
+		}
+	}
+
+	var stages []stage
+	if len(withoutRollout.environments) > 0 {
+		stages = append(stages, withoutRollout)
+	}
+
+	groups := maps.Keys(rollouts)
+	slices.Sort(groups)
+	for _, g := range groups {
+		for _, s := range rollouts[g].Stages {
+			st := stage{group: g, name: s.Name, environments: manifest.Environments{}, gate: s.Gate}
+			for _, name := range s.Environments {
+				if env, found := environments[name]; found {
+					st.environments[name] = env
+				}
+			}
+			if len(st.environments) > 0 {
+				stages = append(stages, st)
+			}
+		}
+	}
+	return stages
+}
+
+// deployInStages deploys the given stages one after another using deployStage. The next stage is only deployed if the
+// previous one succeeded and its gate passed. In dry-runs and if continueOnErr is set, all stages are deployed even if
+// one of them fails, and the errors of all stages are returned. Gates are not checked in dry-runs.
+func deployInStages(ctx context.Context, stages []stage, workingDir string, dryRun bool, continueOnErr bool, deployStage func(context.Context, manifest.Environments) error) error {
+	var errs []error
+	for i, s := range stages {
+		if len(stages) > 1 {
+			log.Info("Deploying %s (%d/%d) to environments %q", s, i+1, len(stages), s.environments.Names())
+		}
+
+		if err := deployStage(ctx, s.environments); err != nil {
+			if !dryRun && !continueOnErr {
+				if i < len(stages)-1 {
+					log.Error("Not deploying remaining %d stages, as %s failed", len(stages)-i-1, s)
+				}
+				return err
+			}
+			errs = append(errs, err)
+		}
+		if ctx.Err() != nil {
+			break
+		}
+
+		if i == len(stages)-1 || !s.gate.Defined() {
+			continue
+		}
+		if dryRun {
+			log.Info("Skipping gate of %s in dry-run", s)
+			continue
+		}
+		if err := passGate(ctx, workingDir, s); err != nil {
+			log.Error("Not deploying remaining %d stages, as the gate of %s did not pass: %v", len(stages)-i-1, s, err)
+			return errors.Join(append(errs, fmt.Errorf("gate of %s did not pass: %w", s, err))...)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// passGate waits the time defined by the gate of the given stage, and runs its command afterward. The command is run
+// in workingDir, with the environment variables MONACO_ROLLOUT_GROUP, MONACO_ROLLOUT_STAGE and
+// MONACO_ROLLOUT_ENVIRONMENTS describing the stage that was deployed.
+func passGate(ctx context.Context, workingDir string, s stage) error {
+	if s.gate.Wait > 0 {
+		log.Info("Waiting %v before proceeding after %s", s.gate.Wait, s)
+
+		timer := time.NewTimer(s.gate.Wait)
+		defer timer.Stop()
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+
+	if s.gate.Command == "" {
+		return nil
+	}
+
+	log.Info("Running gate command %q of %s", s.gate.Command, s)
+
+	envNames := s.environments.Names()
+	slices.Sort(envNames)
+
+	cmd := shellCommand(ctx, s.gate.Command)
+	cmd.Dir = workingDir
+	cmd.Env = append(os.Environ(),
+		"MONACO_ROLLOUT_GROUP="+s.group,
+		"MONACO_ROLLOUT_STAGE="+s.name,
+		"MONACO_ROLLOUT_ENVIRONMENTS="+strings.Join(envNames, ","),
+	)
+
+	out, err := cmd.CombinedOutput()
+	if len(out) > 0 {
+		log.Info("Output of gate command %q:\n%s", s.gate.Command, strings.TrimSpace(string(out)))
+	}
+	if err != nil {
+		return fmt.Errorf("gate command %q failed: %w", s.gate.Command, err)
+	}
+	return nil
+}
+
+func shellCommand(ctx context.Context, command string) *exec.Cmd {
+	if runtime.GOOS == "windows" {
+		return exec.CommandContext(ctx, "cmd", "/C", command)
+	}
+	return exec.CommandContext(ctx, "sh", "-c", command)
+}
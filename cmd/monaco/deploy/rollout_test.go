@@ -0,0 +1,181 @@
+//go:build unit
+
+// @license
+// Copyright 2024 Dynatrace LLC
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deploy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/manifest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+var rolloutEnvironments = manifest.Environments{
+	"dev":    {Name: "dev", Group: "dev"},
+	"canary": {Name: "canary", Group: "prod"},
+	"eu":     {Name: "eu", Group: "prod"},
+	"us":     {Name: "us", Group: "prod"},
+}
+
+var prodRollout = map[string]manifest.Rollout{
+	"prod": {
+		Group: "prod",
+		Stages: []manifest.RolloutStage{
+			{Name: "canary", Environments: []string{"canary"}, Gate: manifest.Gate{Command: "exit 0"}},
+			{Name: "rest", Environments: []string{"eu", "us"}},
+		},
+	},
+}
+
+func TestRolloutStages(t *testing.T) {
+	t.Run("all environments are deployed at once without rollout", func(t *testing.T) {
+		stages := rolloutStages(rolloutEnvironments, nil)
+		require.Len(t, stages, 1)
+		assert.Equal(t, rolloutEnvironments, stages[0].environments)
+	})
+
+	t.Run("environments without rollout are deployed first", func(t *testing.T) {
+		stages := rolloutStages(rolloutEnvironments, prodRollout)
+		require.Len(t, stages, 3)
+
+		assert.Equal(t, []string{"dev"}, stages[0].environments.Names())
+		assert.Equal(t, "", stages[0].group)
+
+		assert.Equal(t, "canary", stages[1].name)
+		assert.Equal(t, []string{"canary"}, stages[1].environments.Names())
+		assert.Equal(t, manifest.Gate{Command: "exit 0"}, stages[1].gate)
+
+		assert.Equal(t, "rest", stages[2].name)
+		assert.ElementsMatch(t, []string{"eu", "us"}, stages[2].environments.Names())
+	})
+
+	t.Run("stages without deployed environments are left out", func(t *testing.T) {
+		stages := rolloutStages(manifest.Environments{"eu": rolloutEnvironments["eu"]}, prodRollout)
+		require.Len(t, stages, 1)
+		assert.Equal(t, "rest", stages[0].name)
+	})
+}
+
+func TestDeployInStages(t *testing.T) {
+	stages := func(gate manifest.Gate) []stage {
+		return []stage{
+			{group: "prod", name: "canary", environments: manifest.Environments{"canary": rolloutEnvironments["canary"]}, gate: gate},
+			{group: "prod", name: "rest", environments: manifest.Environments{"eu": rolloutEnvironments["eu"]}},
+		}
+	}
+
+	deployRecorder := func(failing string) (*[]string, func(context.Context, manifest.Environments) error) {
+		var deployed []string
+		return &deployed, func(_ context.Context, envs manifest.Environments) error {
+			for name := range envs {
+				deployed = append(deployed, name)
+				if name == failing {
+					return errors.New("deployment failed")
+				}
+			}
+			return nil
+		}
+	}
+
+	t.Run("all stages are deployed if gates pass", func(t *testing.T) {
+		deployed, deployStage := deployRecorder("")
+		err := deployInStages(context.TODO(), stages(manifest.Gate{Command: "exit 0"}), t.TempDir(), false, false, deployStage)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"canary", "eu"}, *deployed)
+	})
+
+	t.Run("remaining stages are not deployed if a stage fails", func(t *testing.T) {
+		deployed, deployStage := deployRecorder("canary")
+		err := deployInStages(context.TODO(), stages(manifest.Gate{}), t.TempDir(), false, false, deployStage)
+		assert.ErrorContains(t, err, "deployment failed")
+		assert.Equal(t, []string{"canary"}, *deployed)
+	})
+
+	t.Run("remaining stages are not deployed if a gate fails", func(t *testing.T) {
+		deployed, deployStage := deployRecorder("")
+		err := deployInStages(context.TODO(), stages(manifest.Gate{Command: "exit 1"}), t.TempDir(), false, false, deployStage)
+		assert.ErrorContains(t, err, `gate of stage "canary" of group "prod" did not pass`)
+		assert.Equal(t, []string{"canary"}, *deployed)
+	})
+
+	t.Run("gates are not checked in dry-run", func(t *testing.T) {
+		deployed, deployStage := deployRecorder("")
+		err := deployInStages(context.TODO(), stages(manifest.Gate{Command: "exit 1"}), t.TempDir(), true, false, deployStage)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"canary", "eu"}, *deployed)
+	})
+
+	t.Run("all stages are deployed in dry-run even if a stage fails", func(t *testing.T) {
+		deployed, deployStage := deployRecorder("canary")
+		err := deployInStages(context.TODO(), stages(manifest.Gate{}), t.TempDir(), true, false, deployStage)
+		assert.ErrorContains(t, err, "deployment failed")
+		assert.Equal(t, []string{"canary", "eu"}, *deployed)
+	})
+
+	t.Run("all stages are deployed with continue-on-error even if a stage fails", func(t *testing.T) {
+		deployed, deployStage := deployRecorder("canary")
+		err := deployInStages(context.TODO(), stages(manifest.Gate{}), t.TempDir(), false, true, deployStage)
+		assert.ErrorContains(t, err, "deployment failed")
+		assert.Equal(t, []string{"canary", "eu"}, *deployed)
+	})
+
+	t.Run("errors of all stages are returned", func(t *testing.T) {
+		var deployed []string
+		err := deployInStages(context.TODO(), stages(manifest.Gate{}), t.TempDir(), true, false, func(_ context.Context, envs manifest.Environments) error {
+			for name := range envs {
+				deployed = append(deployed, name)
+				return fmt.Errorf("deployment of %s failed", name)
+			}
+			return nil
+		})
+		assert.ErrorContains(t, err, "deployment of canary failed")
+		assert.ErrorContains(t, err, "deployment of eu failed")
+		assert.Equal(t, []string{"canary", "eu"}, deployed)
+	})
+}
+
+func TestPassGate(t *testing.T) {
+	s := stage{group: "prod", name: "canary", environments: manifest.Environments{"canary": rolloutEnvironments["canary"]}}
+
+	t.Run("command runs in working directory", func(t *testing.T) {
+		dir := t.TempDir()
+		s := s
+		s.gate = manifest.Gate{Command: "echo passed> gate.txt"}
+
+		require.NoError(t, passGate(context.TODO(), dir, s))
+		_, err := os.Stat(filepath.Join(dir, "gate.txt"))
+		assert.NoError(t, err)
+	})
+
+	t.Run("wait is aborted once cancelled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.TODO())
+		cancel()
+
+		s := s
+		s.gate = manifest.Gate{Wait: time.Hour, Command: "echo must not run> gate.txt"}
+
+		dir := t.TempDir()
+		assert.ErrorIs(t, passGate(ctx, dir, s), context.Canceled)
+		_, err := os.Stat(filepath.Join(dir, "gate.txt"))
+		assert.ErrorIs(t, err, os.ErrNotExist)
+	})
+}
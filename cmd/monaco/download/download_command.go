@@ -54,9 +54,9 @@ func GetDownloadCommand(fs afero.Fs, command Command) (cmd *cobra.Command) {
 
 			if f.environmentURL != "" {
 				f.manifestFile = ""
-				return command.DownloadConfigs(fs, f)
+				return command.DownloadConfigs(cmd.Context(), fs, f)
 			}
-			return command.DownloadConfigsBasedOnManifest(fs, f)
+			return command.DownloadConfigsBasedOnManifest(cmd.Context(), fs, f)
 		},
 	}
 
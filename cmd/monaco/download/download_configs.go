@@ -15,6 +15,7 @@
 package download
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	automationClient "github.com/dynatrace/dynatrace-configuration-as-code-core/clients/automation"
@@ -93,7 +94,7 @@ func readEnvVariable(envVar string) (manifest.AuthSecret, error) {
 	return manifest.AuthSecret{Name: envVar, Value: secret.MaskedString(content)}, nil
 }
 
-func (d DefaultCommand) DownloadConfigsBasedOnManifest(fs afero.Fs, cmdOptions downloadCmdOptions) error {
+func (d DefaultCommand) DownloadConfigsBasedOnManifest(ctx context.Context, fs afero.Fs, cmdOptions downloadCmdOptions) error {
 
 	m, errs := manifestloader.Load(&manifestloader.Context{
 		Fs:           fs,
@@ -147,10 +148,10 @@ func (d DefaultCommand) DownloadConfigsBasedOnManifest(fs afero.Fs, cmdOptions d
 		return err
 	}
 
-	return doDownloadConfigs(fs, clientSet, prepareAPIs(api.NewAPIs(), options), options)
+	return doDownloadConfigs(ctx, fs, clientSet, prepareAPIs(api.NewAPIs(), options), options)
 }
 
-func (d DefaultCommand) DownloadConfigs(fs afero.Fs, cmdOptions downloadCmdOptions) error {
+func (d DefaultCommand) DownloadConfigs(ctx context.Context, fs afero.Fs, cmdOptions downloadCmdOptions) error {
 	a, errs := cmdOptions.auth.mapToAuth()
 	errs = append(errs, validateParameters(cmdOptions.environmentURL, cmdOptions.projectName)...)
 
@@ -183,17 +184,17 @@ func (d DefaultCommand) DownloadConfigs(fs afero.Fs, cmdOptions downloadCmdOptio
 		return err
 	}
 
-	return doDownloadConfigs(fs, clientSet, prepareAPIs(api.NewAPIs(), options), options)
+	return doDownloadConfigs(ctx, fs, clientSet, prepareAPIs(api.NewAPIs(), options), options)
 }
 
-func doDownloadConfigs(fs afero.Fs, clientSet *client.ClientSet, apisToDownload api.APIs, opts downloadConfigsOptions) error {
+func doDownloadConfigs(ctx context.Context, fs afero.Fs, clientSet *client.ClientSet, apisToDownload api.APIs, opts downloadConfigsOptions) error {
 	err := preDownloadValidations(fs, opts.downloadOptionsShared)
 	if err != nil {
 		return err
 	}
 
 	log.Info("Downloading from environment '%v' into project '%v'", opts.environmentURL, opts.projectName)
-	downloadedConfigs, err := downloadConfigs(clientSet, apisToDownload, opts, defaultDownloadFn)
+	downloadedConfigs, err := downloadConfigs(ctx, clientSet, apisToDownload, opts, defaultDownloadFn)
 	if err != nil {
 		return err
 	}
@@ -233,9 +234,15 @@ var defaultDownloadFn = downloadFn{
 	bucketDownload:     bucket.Download,
 }
 
-func downloadConfigs(clientSet *client.ClientSet, apisToDownload api.APIs, opts downloadConfigsOptions, fn downloadFn) (project.ConfigsPerType, error) {
+// downloadConfigs downloads all requested configurations. Once ctx is cancelled, the current type of configurations is
+// downloaded completely, but no further types are started, and an error is returned.
+func downloadConfigs(ctx context.Context, clientSet *client.ClientSet, apisToDownload api.APIs, opts downloadConfigsOptions, fn downloadFn) (project.ConfigsPerType, error) {
 	configs := make(project.ConfigsPerType)
 
+	if err := checkCancelled(ctx); err != nil {
+		return nil, err
+	}
+
 	if shouldDownloadConfigs(opts) {
 		classicCfgs, err := fn.classicDownload(clientSet.Classic(), opts.projectName, prepareAPIs(apisToDownload, opts), classic.ApiContentFilters)
 		if err != nil {
@@ -244,6 +251,10 @@ func downloadConfigs(clientSet *client.ClientSet, apisToDownload api.APIs, opts
 		copyConfigs(configs, classicCfgs)
 	}
 
+	if err := checkCancelled(ctx); err != nil {
+		return nil, err
+	}
+
 	if shouldDownloadSettings(opts) {
 		log.Info("Downloading settings objects")
 		settingCfgs, err := fn.settingsDownload(clientSet.Settings(), opts.projectName, settings.DefaultSettingsFilters, makeSettingTypes(opts.specificSchemas)...)
@@ -253,6 +264,10 @@ func downloadConfigs(clientSet *client.ClientSet, apisToDownload api.APIs, opts
 		copyConfigs(configs, settingCfgs)
 	}
 
+	if err := checkCancelled(ctx); err != nil {
+		return nil, err
+	}
+
 	if shouldDownloadAutomationResources(opts) {
 		if opts.auth.OAuth != nil {
 			log.Info("Downloading automation resources")
@@ -266,6 +281,10 @@ func downloadConfigs(clientSet *client.ClientSet, apisToDownload api.APIs, opts
 		}
 	}
 
+	if err := checkCancelled(ctx); err != nil {
+		return nil, err
+	}
+
 	if shouldDownloadBuckets(opts) && opts.auth.OAuth != nil {
 		log.Info("Downloading Grail buckets")
 		bucketCfgs, err := fn.bucketDownload(clientSet.Bucket(), opts.projectName)
@@ -278,6 +297,16 @@ func downloadConfigs(clientSet *client.ClientSet, apisToDownload api.APIs, opts
 	return configs, nil
 }
 
+// checkCancelled returns an error if ctx was cancelled. No project is written for a cancelled download, as it would be
+// incomplete.
+func checkCancelled(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		log.Warn("Download was cancelled - no project is written")
+		return fmt.Errorf("download cancelled: %w", err)
+	}
+	return nil
+}
+
 func makeSettingTypes(specificSchemas []string) []config.SettingsType {
 	var settingTypes []config.SettingsType
 	for _, schema := range specificSchemas {
@@ -19,6 +19,7 @@
 package download
 
 import (
+	"context"
 	"errors"
 	automation0 "github.com/dynatrace/dynatrace-configuration-as-code-core/clients/automation"
 	"github.com/dynatrace/dynatrace-configuration-as-code-core/clients/buckets"
@@ -154,7 +155,7 @@ func TestDownloadConfigsBehaviour(t *testing.T) {
 
 			tt.expectedBehaviour(c)
 
-			_, err := downloadConfigs(&client.ClientSet{DTClient: c}, api.NewAPIs(), tt.givenOpts, defaultDownloadFn)
+			_, err := downloadConfigs(context.TODO(), &client.ClientSet{DTClient: c}, api.NewAPIs(), tt.givenOpts, defaultDownloadFn)
 			assert.NoError(t, err)
 		})
 	}
@@ -249,12 +250,31 @@ func TestDownload_Options(t *testing.T) {
 				},
 			}
 
-			_, err := downloadConfigs(&client.ClientSet{DTClient: dtclient.NewMockClient(gomock.NewController(t))}, api.NewAPIs(), tt.given, fn)
+			_, err := downloadConfigs(context.TODO(), &client.ClientSet{DTClient: dtclient.NewMockClient(gomock.NewController(t))}, api.NewAPIs(), tt.given, fn)
 			assert.NoError(t, err)
 		})
 	}
 }
 
+func TestDownloadConfigs_StopsOnceCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.TODO())
+	defer cancel()
+
+	fn := downloadFn{
+		classicDownload: func(dtclient.Client, string, api.APIs, classic.ContentFilters) (projectv2.ConfigsPerType, error) {
+			cancel()
+			return nil, nil
+		},
+		settingsDownload: func(dtclient.SettingsClient, string, settings.Filters, ...config.SettingsType) (projectv2.ConfigsPerType, error) {
+			t.Fatalf("settings download was not meant to be called after the download was cancelled")
+			return nil, nil
+		},
+	}
+
+	_, err := downloadConfigs(ctx, &client.ClientSet{DTClient: dtclient.NewMockClient(gomock.NewController(t))}, api.NewAPIs(), downloadConfigsOptions{specificAPIs: []string{"alerting-profile"}, specificSchemas: []string{"some:schema"}}, fn)
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
 func Test_shouldDownloadSettings(t *testing.T) {
 	tests := []struct {
 		name  string
@@ -357,7 +377,7 @@ func TestDownloadConfigsExitsEarlyForUnknownSettingsSchema(t *testing.T) {
 
 	c.EXPECT().ListSchemas().Return(dtclient.SchemaList{{"builtin:some.schema"}}, nil)
 
-	err := doDownloadConfigs(afero.NewMemMapFs(), &client.ClientSet{DTClient: c}, nil, givenOpts)
+	err := doDownloadConfigs(context.TODO(), afero.NewMemMapFs(), &client.ClientSet{DTClient: c}, nil, givenOpts)
 	assert.ErrorContains(t, err, "not known", "expected download to fail for unkown Settings Schema")
 	c.EXPECT().ListSettings(gomock.Any(), gomock.Any(), gomock.Any()).Times(0) // no downloads should even be attempted for unknown schema
 }
@@ -423,7 +443,7 @@ func TestDownloadConfigs_OnlyAutomationWithoutAutomationCredentials(t *testing.T
 		onlyAutomation: true,
 	}
 
-	err := doDownloadConfigs(testutils.CreateTestFileSystem(), &client.ClientSet{}, nil, opts)
+	err := doDownloadConfigs(context.TODO(), testutils.CreateTestFileSystem(), &client.ClientSet{}, nil, opts)
 	assert.ErrorContains(t, err, "no OAuth credentials configured")
 }
 
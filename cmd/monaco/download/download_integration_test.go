@@ -17,6 +17,7 @@
 package download
 
 import (
+	"context"
 	"encoding/json"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/environment"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/featureflags"
@@ -86,7 +87,7 @@ func TestDownloadIntegrationSimple(t *testing.T) {
 	dtClient, _ := dtclient.NewDynatraceClientForTesting(server.URL, server.Client())
 
 	// WHEN we download everything
-	err := doDownloadConfigs(fs, &client.ClientSet{DTClient: dtClient}, apiMap, setupTestingDownloadOptions(t, server, projectName))
+	err := doDownloadConfigs(context.TODO(), fs, &client.ClientSet{DTClient: dtClient}, apiMap, setupTestingDownloadOptions(t, server, projectName))
 
 	assert.NoError(t, err)
 
@@ -157,7 +158,7 @@ func TestDownloadIntegrationWithReference(t *testing.T) {
 	dtClient, _ := dtclient.NewDynatraceClientForTesting(server.URL, server.Client())
 
 	// WHEN we download everything
-	err := doDownloadConfigs(fs, &client.ClientSet{DTClient: dtClient}, apiMap, setupTestingDownloadOptions(t, server, projectName))
+	err := doDownloadConfigs(context.TODO(), fs, &client.ClientSet{DTClient: dtClient}, apiMap, setupTestingDownloadOptions(t, server, projectName))
 
 	assert.NoError(t, err)
 
@@ -248,7 +249,7 @@ func TestDownloadIntegrationWithMultipleApisAndReferences(t *testing.T) {
 	dtClient, _ := dtclient.NewDynatraceClientForTesting(server.URL, server.Client())
 
 	// WHEN we download everything
-	err := doDownloadConfigs(fs, &client.ClientSet{DTClient: dtClient}, apiMap, setupTestingDownloadOptions(t, server, projectName))
+	err := doDownloadConfigs(context.TODO(), fs, &client.ClientSet{DTClient: dtClient}, apiMap, setupTestingDownloadOptions(t, server, projectName))
 
 	assert.NoError(t, err)
 
@@ -366,7 +367,7 @@ func TestDownloadIntegrationSingletonConfig(t *testing.T) {
 	dtClient, _ := dtclient.NewDynatraceClientForTesting(server.URL, server.Client())
 
 	// WHEN we download everything
-	err := doDownloadConfigs(fs, &client.ClientSet{DTClient: dtClient}, apiMap, setupTestingDownloadOptions(t, server, projectName))
+	err := doDownloadConfigs(context.TODO(), fs, &client.ClientSet{DTClient: dtClient}, apiMap, setupTestingDownloadOptions(t, server, projectName))
 
 	assert.NoError(t, err)
 
@@ -434,7 +435,7 @@ func TestDownloadIntegrationSyntheticLocations(t *testing.T) {
 	dtClient, _ := dtclient.NewDynatraceClientForTesting(server.URL, server.Client())
 
 	// WHEN we download everything
-	err := doDownloadConfigs(fs, &client.ClientSet{DTClient: dtClient}, apiMap, setupTestingDownloadOptions(t, server, projectName))
+	err := doDownloadConfigs(context.TODO(), fs, &client.ClientSet{DTClient: dtClient}, apiMap, setupTestingDownloadOptions(t, server, projectName))
 
 	assert.NoError(t, err)
 
@@ -505,7 +506,7 @@ func TestDownloadIntegrationDashboards(t *testing.T) {
 	dtClient, _ := dtclient.NewDynatraceClientForTesting(server.URL, server.Client())
 
 	// WHEN we download everything
-	err := doDownloadConfigs(fs, &client.ClientSet{DTClient: dtClient}, apiMap, setupTestingDownloadOptions(t, server, projectName))
+	err := doDownloadConfigs(context.TODO(), fs, &client.ClientSet{DTClient: dtClient}, apiMap, setupTestingDownloadOptions(t, server, projectName))
 
 	assert.NoError(t, err)
 
@@ -604,7 +605,7 @@ func TestDownloadIntegrationAllDashboardsAreDownloadedIfFilterFFTurnedOff(t *tes
 	t.Setenv(featureflags.DownloadFilterClassicConfigs().EnvName(), "false")
 
 	// WHEN we download everything
-	err := doDownloadConfigs(fs, &client.ClientSet{DTClient: dtClient}, apiMap, setupTestingDownloadOptions(t, server, projectName))
+	err := doDownloadConfigs(context.TODO(), fs, &client.ClientSet{DTClient: dtClient}, apiMap, setupTestingDownloadOptions(t, server, projectName))
 
 	assert.NoError(t, err)
 
@@ -721,7 +722,7 @@ func TestDownloadIntegrationAnomalyDetectionMetrics(t *testing.T) {
 	dtClient, _ := dtclient.NewDynatraceClientForTesting(server.URL, server.Client())
 
 	// WHEN we download everything
-	err := doDownloadConfigs(fs, &client.ClientSet{DTClient: dtClient}, apiMap, setupTestingDownloadOptions(t, server, projectName))
+	err := doDownloadConfigs(context.TODO(), fs, &client.ClientSet{DTClient: dtClient}, apiMap, setupTestingDownloadOptions(t, server, projectName))
 
 	assert.NoError(t, err)
 
@@ -862,7 +863,7 @@ func TestDownloadIntegrationHostAutoUpdate(t *testing.T) {
 			dtClient, _ := dtclient.NewDynatraceClientForTesting(server.URL, server.Client())
 
 			// WHEN we download everything
-			err := doDownloadConfigs(fs, &client.ClientSet{DTClient: dtClient}, apiMap, setupTestingDownloadOptions(t, server, testcase.projectName))
+			err := doDownloadConfigs(context.TODO(), fs, &client.ClientSet{DTClient: dtClient}, apiMap, setupTestingDownloadOptions(t, server, testcase.projectName))
 
 			assert.NoError(t, err)
 
@@ -935,7 +936,7 @@ func TestDownloadIntegrationOverwritesFolderAndManifestIfForced(t *testing.T) {
 
 	dtClient, _ := dtclient.NewDynatraceClientForTesting(server.URL, server.Client())
 
-	err := doDownloadConfigs(fs, &client.ClientSet{DTClient: dtClient}, apis, options)
+	err := doDownloadConfigs(context.TODO(), fs, &client.ClientSet{DTClient: dtClient}, apis, options)
 
 	assert.NoError(t, err)
 
@@ -1028,7 +1029,7 @@ func TestDownloadIntegrationDownloadsAPIsAndSettings(t *testing.T) {
 
 	dtClient, _ := dtclient.NewDynatraceClientForTesting(server.URL, server.Client())
 
-	err := doDownloadConfigs(fs, &client.ClientSet{DTClient: dtClient}, apis, opts)
+	err := doDownloadConfigs(context.TODO(), fs, &client.ClientSet{DTClient: dtClient}, apis, opts)
 
 	assert.NoError(t, err)
 
@@ -1088,7 +1089,7 @@ func TestDownloadIntegrationDownloadsOnlyAPIsIfConfigured(t *testing.T) {
 	opts.onlyAPIs = true
 	dtClient, _ := dtclient.NewDynatraceClientForTesting(server.URL, server.Client())
 
-	err := doDownloadConfigs(fs, &client.ClientSet{DTClient: dtClient}, apis, opts)
+	err := doDownloadConfigs(context.TODO(), fs, &client.ClientSet{DTClient: dtClient}, apis, opts)
 
 	assert.NoError(t, err)
 
@@ -1139,7 +1140,7 @@ func TestDownloadIntegrationDoesNotDownloadUnmodifiableSettings(t *testing.T) {
 
 	dtClient, _ := dtclient.NewDynatraceClientForTesting(server.URL, server.Client())
 
-	err := doDownloadConfigs(fs, &client.ClientSet{DTClient: dtClient}, nil, opts)
+	err := doDownloadConfigs(context.TODO(), fs, &client.ClientSet{DTClient: dtClient}, nil, opts)
 
 	assert.NoError(t, err)
 
@@ -1196,7 +1197,7 @@ func TestDownloadIntegrationDownloadsUnmodifiableSettingsIfFFTurnedOff(t *testin
 	// GIVEN filter feature flag is turned OFF
 	t.Setenv(featureflags.DownloadFilterSettingsUnmodifiable().EnvName(), "false")
 
-	err := doDownloadConfigs(fs, &client.ClientSet{DTClient: dtClient}, nil, opts)
+	err := doDownloadConfigs(context.TODO(), fs, &client.ClientSet{DTClient: dtClient}, nil, opts)
 
 	assert.NoError(t, err)
 
@@ -0,0 +1,82 @@
+/*
+ * @license
+ * Copyright 2024 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package drift
+
+import (
+	"fmt"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/cmd/monaco/cmdutils"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/cmd/monaco/completion"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/files"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/log"
+	"github.com/spf13/afero"
+	"github.com/spf13/cobra"
+)
+
+func Command(fs afero.Fs) (cmd *cobra.Command) {
+	var environments, groups, projects []string
+	var reportFile string
+
+	cmd = &cobra.Command{
+		Use:   "drift <manifest.yaml>",
+		Short: "Report objects in Dynatrace environments that differ from their configurations, e.g. because they were changed manually",
+		Long: "Renders all configurations of the manifest's projects and compares them with the objects currently present in the environments. " +
+			"Nothing is modified. Properties managed by Dynatrace, like IDs or metadata, are not compared. " +
+			fmt.Sprintf("If any object drifted from its configuration, or is missing, the command exits with code %d. ", ExitCodeDriftDetected) +
+			"Any other error results in exit code 1.",
+		Example:           "monaco drift manifest.yaml -e prod-environment --report-file drift.json",
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: completion.SingleArgumentManifestFileCompletion,
+		PreRun:            cmdutils.SilenceUsageCommand(),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			manifestName := args[0]
+
+			if !files.IsYamlFileExtension(manifestName) {
+				return fmt.Errorf("wrong format for manifest file! Expected a .yaml file, but got %s", manifestName)
+			}
+
+			return detectDrift(cmd.Context(), fs, options{
+				manifestPath:         manifestName,
+				environmentGroups:    groups,
+				specificEnvironments: environments,
+				specificProjects:     projects,
+				reportFile:           reportFile,
+			})
+		},
+	}
+
+	cmd.Flags().StringSliceVarP(&environments, "environment", "e", []string{},
+		"Specify one (or multiple) environment(s) to check for drift. "+
+			"To set multiple environments either repeat this flag, or separate them using a comma (,). "+
+			"This flag is mutually exclusive with '--group'.")
+	cmd.Flags().StringSliceVarP(&groups, "group", "g", []string{},
+		"Specify one (or multiple) environmentGroup(s) to check for drift. "+
+			"To set multiple groups either repeat this flag, or separate them using a comma (,). "+
+			"This flag is mutually exclusive with '--environment'")
+	cmd.Flags().StringSliceVarP(&projects, "project", "p", []string{}, "Project configurations to check for drift (also checks any dependent configurations)")
+	cmd.Flags().StringVar(&reportFile, "report-file", "", "Write the result of the drift check as JSON to this file, in addition to logging it.")
+
+	if err := cmd.RegisterFlagCompletionFunc("environment", completion.EnvironmentByArg0); err != nil {
+		log.Fatal("failed to setup CLI %v", err)
+	}
+	if err := cmd.RegisterFlagCompletionFunc("project", completion.ProjectsFromManifest); err != nil {
+		log.Fatal("failed to setup CLI %v", err)
+	}
+
+	cmd.MarkFlagsMutuallyExclusive("environment", "group")
+
+	return cmd
+}
@@ -0,0 +1,187 @@
+/*
+ * @license
+ * Copyright 2024 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package drift
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/cmd/monaco/dynatrace"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/errutils"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/log"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/log/field"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/api"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/deploy"
+	manifestloader "github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/manifest/loader"
+	project "github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/project/v2"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/state"
+	"github.com/spf13/afero"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// ExitCodeDriftDetected is the exit code of the drift command if any object drifted from its configuration
+const ExitCodeDriftDetected = 2
+
+// DetectedError is returned if any object drifted from its configuration or is missing
+type DetectedError struct {
+	// Drifted is the number of objects that differ from their configuration
+	Drifted int `json:"drifted"`
+	// Missing is the number of configurations without an object in the environment
+	Missing int `json:"missing"`
+}
+
+func (e DetectedError) Error() string {
+	return fmt.Sprintf("drift detected: %d object(s) differ from their configuration, %d object(s) are missing", e.Drifted, e.Missing)
+}
+
+type options struct {
+	manifestPath         string
+	environmentGroups    []string
+	specificEnvironments []string
+	specificProjects     []string
+	// reportFile is the path the JSON report is written to. No report is written if it is empty.
+	reportFile string
+}
+
+// report is the content of the JSON report file
+type report struct {
+	Results []deploy.DriftResult `json:"results"`
+}
+
+func detectDrift(ctx context.Context, fs afero.Fs, opts options) error {
+	manifestPath, err := filepath.Abs(filepath.Clean(opts.manifestPath))
+	if err != nil {
+		return fmt.Errorf("error while finding absolute path for `%s`: %w", opts.manifestPath, err)
+	}
+
+	m, errs := manifestloader.Load(&manifestloader.Context{
+		Fs:           fs,
+		ManifestPath: manifestPath,
+		Groups:       opts.environmentGroups,
+		Environments: opts.specificEnvironments,
+		Opts:         manifestloader.Options{RequireEnvironmentGroups: true},
+	})
+	if len(errs) > 0 {
+		errutils.PrintErrors(errs)
+		return errors.New("error while loading manifest")
+	}
+
+	projects, errs := project.LoadProjects(fs, project.ProjectLoaderContext{
+		KnownApis:       api.NewAPIs().Filter(api.RemoveDisabled).GetApiNameLookup(),
+		WorkingDir:      filepath.Dir(manifestPath),
+		Manifest:        m,
+		ParametersSerde: config.DefaultParameterParsers,
+	}, opts.specificProjects)
+	if len(errs) > 0 {
+		errutils.PrintErrors(errs)
+		return fmt.Errorf("failed to load projects - %d errors occurred", len(errs))
+	}
+
+	if !dynatrace.VerifyEnvironmentGeneration(m.Environments) {
+		return errors.New("unable to verify Dynatrace environment generation")
+	}
+
+	clientSets, err := dynatrace.CreateEnvironmentClients(m.Environments)
+	if err != nil {
+		return fmt.Errorf("failed to create API clients: %w", err)
+	}
+
+	var deploymentState *state.State
+	if m.State.Enabled() {
+		if deploymentState, err = state.Load(fs, state.ResolvePath(manifestPath, m.State.Path)); err != nil {
+			return fmt.Errorf("failed to load deployment state: %w", err)
+		}
+	}
+
+	results, driftErr := deploy.Drift(ctx, projects, clientSets, deploymentState)
+	sortResults(results)
+	logSummary(results)
+
+	if opts.reportFile != "" {
+		if err := writeReport(fs, opts.reportFile, results); err != nil {
+			return errors.Join(err, driftErr)
+		}
+		log.WithFields(field.F("reportFile", opts.reportFile)).Info("Drift report written to %q", opts.reportFile)
+	}
+
+	if driftErr != nil {
+		return fmt.Errorf("drift detection failed - check logs for details: %w", driftErr)
+	}
+
+	if detected := countDrift(results); detected.Drifted > 0 || detected.Missing > 0 {
+		return detected
+	}
+
+	log.Info("No drift detected")
+	return nil
+}
+
+func sortResults(results []deploy.DriftResult) {
+	slices.SortFunc(results, func(a, b deploy.DriftResult) int {
+		if c := strings.Compare(a.Environment, b.Environment); c != 0 {
+			return c
+		}
+		return strings.Compare(a.Coordinate.String(), b.Coordinate.String())
+	})
+}
+
+func countDrift(results []deploy.DriftResult) DetectedError {
+	var e DetectedError
+	for _, r := range results {
+		switch r.Status {
+		case deploy.DriftStatusDrifted:
+			e.Drifted++
+		case deploy.DriftStatusMissing:
+			e.Missing++
+		}
+	}
+	return e
+}
+
+func logSummary(results []deploy.DriftResult) {
+	statusPerEnv := make(map[string]map[deploy.DriftStatus]int)
+	var envs []string
+	for _, r := range results {
+		if statusPerEnv[r.Environment] == nil {
+			statusPerEnv[r.Environment] = make(map[deploy.DriftStatus]int)
+			envs = append(envs, r.Environment)
+		}
+		statusPerEnv[r.Environment][r.Status]++
+	}
+
+	log.Info("Drift per environment:")
+	for _, env := range envs {
+		s := statusPerEnv[env]
+		log.WithFields(field.F("environment", env), field.F("driftStatus", s)).Info("  - %s:\t%d drifted, %d missing, %d in sync, %d skipped", env, s[deploy.DriftStatusDrifted], s[deploy.DriftStatusMissing], s[deploy.DriftStatusInSync], s[deploy.DriftStatusSkipped])
+	}
+}
+
+func writeReport(fs afero.Fs, path string, results []deploy.DriftResult) error {
+	b, err := json.MarshalIndent(report{Results: results}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal drift report: %w", err)
+	}
+
+	if err := afero.WriteFile(fs, path, b, 0664); err != nil {
+		return fmt.Errorf("failed to write drift report %q: %w", path, err)
+	}
+	return nil
+}
@@ -0,0 +1,99 @@
+//go:build unit
+
+/*
+ * @license
+ * Copyright 2024 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package drift
+
+import (
+	"context"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/json"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/coordinate"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/deploy"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+var testResults = []deploy.DriftResult{
+	{
+		Coordinate:  coordinate.Coordinate{Project: "p", Type: "auto-tag", ConfigId: "b"},
+		Environment: "prod",
+		Status:      deploy.DriftStatusDrifted,
+		RemoteId:    "b-id",
+		Diff:        []json.Difference{{Path: "/description", Current: "changed", Desired: "original"}},
+	},
+	{
+		Coordinate:  coordinate.Coordinate{Project: "p", Type: "auto-tag", ConfigId: "a"},
+		Environment: "prod",
+		Status:      deploy.DriftStatusInSync,
+		RemoteId:    "a-id",
+	},
+	{
+		Coordinate:  coordinate.Coordinate{Project: "p", Type: "auto-tag", ConfigId: "c"},
+		Environment: "dev",
+		Status:      deploy.DriftStatusMissing,
+	},
+	{
+		Coordinate:  coordinate.Coordinate{Project: "p", Type: "auto-tag", ConfigId: "d"},
+		Environment: "dev",
+		Status:      deploy.DriftStatusSkipped,
+	},
+}
+
+func TestCountDrift(t *testing.T) {
+	assert.Equal(t, DetectedError{Drifted: 1, Missing: 1}, countDrift(testResults))
+	assert.Equal(t, DetectedError{}, countDrift(testResults[1:2]))
+}
+
+func TestSortResults(t *testing.T) {
+	results := append([]deploy.DriftResult{}, testResults...)
+	sortResults(results)
+
+	var order []string
+	for _, r := range results {
+		order = append(order, r.Environment+"/"+r.Coordinate.ConfigId)
+	}
+	assert.Equal(t, []string{"dev/c", "dev/d", "prod/a", "prod/b"}, order)
+}
+
+func TestWriteReport(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	require.NoError(t, writeReport(fs, "drift.json", testResults[:1]))
+
+	b, err := afero.ReadFile(fs, "drift.json")
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+  "results": [
+    {
+      "coordinate": {"Project": "p", "Type": "auto-tag", "ConfigId": "b"},
+      "environment": "prod",
+      "status": "drifted",
+      "remoteId": "b-id",
+      "diff": [{"path": "/description", "current": "changed", "desired": "original"}]
+    }
+  ]
+}`, string(b))
+}
+
+func TestDetectDrift_FailsForInvalidManifest(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	require.NoError(t, afero.WriteFile(fs, "/manifest.yaml", []byte(`manifestVersion: "1.0"`), 0644))
+
+	err := detectDrift(context.TODO(), fs, options{manifestPath: "/manifest.yaml"})
+	assert.ErrorContains(t, err, "error while loading manifest")
+}
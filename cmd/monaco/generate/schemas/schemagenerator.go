@@ -20,6 +20,7 @@ import (
 	"fmt"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/log"
 	accountDelete "github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/account/delete"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/template"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/delete"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/manifest"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/persistence/account"
@@ -63,6 +64,12 @@ func generateSchemaFiles(fs afero.Fs, outputfolder string) error {
 		return err
 	}
 
+	if s, err := template.GenerateFunctionDocumentation(); err != nil {
+		return err
+	} else if err := writeSchemaFile(fs, filepath.Join(outputfolder, "monaco-template-functions.json"), s); err != nil {
+		return err
+	}
+
 	return nil
 }
 
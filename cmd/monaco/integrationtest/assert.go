@@ -119,7 +119,7 @@ func AssertAllConfigsAvailability(t *testing.T, fs afero.Fs, manifestPath string
 				continue
 			}
 
-			properties, errs := theConfig.ResolveParameterValues(lookup)
+			properties, errs := theConfig.ResolveParameterValues(ctx, lookup)
 			testutils.FailTestOnAnyError(t, errs, "resolving of parameter values failed")
 
 			properties[config.IdParameter] = "NO REAL ID NEEDED FOR CHECKING AVAILABILITY"
@@ -15,15 +15,20 @@
 package runner
 
 import (
+	"context"
+	"errors"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/cmd/monaco/account"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/cmd/monaco/convert"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/cmd/monaco/delete"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/cmd/monaco/deploy"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/cmd/monaco/download"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/cmd/monaco/drift"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/cmd/monaco/generate"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/cmd/monaco/purge"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/cmd/monaco/state"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/cmd/monaco/support"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/cmd/monaco/version"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/cancellation"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/featureflags"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/log"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/log/field"
@@ -36,9 +41,17 @@ import (
 func Run() int {
 	rootCmd := BuildCli(afero.NewOsFs())
 
-	if err := rootCmd.Execute(); err != nil {
+	ctx, stop := cancellation.SignalContext(context.Background())
+	defer stop()
+
+	if err := rootCmd.ExecuteContext(ctx); err != nil {
 		log.WithFields(field.Error(err)).Error("Error: %v", err)
 		log.WithFields(field.F("errorLogFilePath", log.ErrorFilePath())).Error("error logs written to %s", log.ErrorFilePath())
+
+		var driftErr drift.DetectedError
+		if errors.As(err, &driftErr) {
+			return drift.ExitCodeDriftDetected
+		}
 		return 1
 	}
 	return 0
@@ -89,9 +102,11 @@ Examples:
 	rootCmd.AddCommand(download.GetDownloadCommand(fs, &download.DefaultCommand{}))
 	rootCmd.AddCommand(convert.GetConvertCommand(fs))
 	rootCmd.AddCommand(deploy.GetDeployCommand(fs))
+	rootCmd.AddCommand(drift.Command(fs))
 	rootCmd.AddCommand(delete.GetDeleteCommand(fs))
 	rootCmd.AddCommand(version.GetVersionCommand())
 	rootCmd.AddCommand(generate.Command(fs))
+	rootCmd.AddCommand(state.Command(fs))
 
 	if featureflags.AccountManagement().Enabled() {
 		rootCmd.AddCommand(account.Command(fs))
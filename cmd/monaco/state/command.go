@@ -0,0 +1,146 @@
+/*
+ * @license
+ * Copyright 2024 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package state
+
+import (
+	"fmt"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/cmd/monaco/cmdutils"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/cmd/monaco/completion"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/files"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/log"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/coordinate"
+	"github.com/spf13/afero"
+	"github.com/spf13/cobra"
+)
+
+func Command(fs afero.Fs) (cmd *cobra.Command) {
+
+	cmd = &cobra.Command{
+		Use:     "state",
+		Short:   "Inspect and modify the deployment state file defined in a manifest - take a look at the sub-commands for usage",
+		Example: "monaco state list manifest.yaml -e dev-environment",
+		Args:    cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			_ = cmd.Help()
+		},
+	}
+
+	cmd.AddCommand(listCommand(fs))
+	cmd.AddCommand(showCommand(fs))
+	cmd.AddCommand(removeCommand(fs))
+
+	return cmd
+}
+
+func listCommand(fs afero.Fs) (cmd *cobra.Command) {
+	var environments []string
+
+	cmd = &cobra.Command{
+		Use:               "list <manifest.yaml>",
+		Short:             "List all configurations stored in the deployment state",
+		Example:           "monaco state list manifest.yaml -e dev-environment",
+		Args:              cobra.ExactArgs(1),
+		PreRun:            cmdutils.SilenceUsageCommand(),
+		ValidArgsFunction: completion.SingleArgumentManifestFileCompletion,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := validateManifestFile(args[0]); err != nil {
+				return err
+			}
+			return list(fs, args[0], environments)
+		},
+	}
+
+	addEnvironmentFlag(cmd, &environments, "Specify one (or multiple) environment(s) to list the state of. If not set, the state of all environments is listed.")
+
+	return cmd
+}
+
+func showCommand(fs afero.Fs) (cmd *cobra.Command) {
+	var environments []string
+
+	cmd = &cobra.Command{
+		Use:               "show <manifest.yaml> <project:type:config-id>",
+		Short:             "Show the deployment state of a single configuration",
+		Example:           "monaco state show manifest.yaml my-project:builtin:alerting.profile:my-profile -e dev-environment",
+		Args:              cobra.ExactArgs(2),
+		PreRun:            cmdutils.SilenceUsageCommand(),
+		ValidArgsFunction: completion.SingleArgumentManifestFileCompletion,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := validateManifestFile(args[0]); err != nil {
+				return err
+			}
+			c, err := coordinate.Parse(args[1])
+			if err != nil {
+				return err
+			}
+			return show(fs, args[0], c, environments)
+		},
+	}
+
+	addEnvironmentFlag(cmd, &environments, "Specify one (or multiple) environment(s) to show the state of. If not set, the state in all environments is shown.")
+
+	return cmd
+}
+
+func removeCommand(fs afero.Fs) (cmd *cobra.Command) {
+	var environments []string
+
+	cmd = &cobra.Command{
+		Use:   "remove <manifest.yaml> <project:type:config-id>",
+		Short: "Remove a single configuration from the deployment state",
+		Long: "Remove a single configuration from the deployment state. " +
+			"The next deployment of the configuration will search for a matching object in the environment again, instead of updating the remembered object. " +
+			"Objects in the environment are not modified.",
+		Example:           "monaco state remove manifest.yaml my-project:builtin:alerting.profile:my-profile -e dev-environment",
+		Args:              cobra.ExactArgs(2),
+		PreRun:            cmdutils.SilenceUsageCommand(),
+		ValidArgsFunction: completion.SingleArgumentManifestFileCompletion,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := validateManifestFile(args[0]); err != nil {
+				return err
+			}
+			c, err := coordinate.Parse(args[1])
+			if err != nil {
+				return err
+			}
+			return remove(fs, args[0], c, environments)
+		},
+	}
+
+	addEnvironmentFlag(cmd, &environments, "Specify one (or multiple) environment(s) to remove the configuration from the state of.")
+	if err := cmd.MarkFlagRequired("environment"); err != nil {
+		log.Fatal("failed to setup CLI %v", err)
+	}
+
+	return cmd
+}
+
+func addEnvironmentFlag(cmd *cobra.Command, environments *[]string, usage string) {
+	cmd.Flags().StringSliceVarP(environments, "environment", "e", []string{},
+		usage+" To set multiple environments either repeat this flag, or separate them using a comma (,).")
+
+	if err := cmd.RegisterFlagCompletionFunc("environment", completion.EnvironmentByArg0); err != nil {
+		log.Fatal("failed to setup CLI %v", err)
+	}
+}
+
+func validateManifestFile(manifestName string) error {
+	if !files.IsYamlFileExtension(manifestName) {
+		return fmt.Errorf("wrong format for manifest file! Expected a .yaml file, but got %s", manifestName)
+	}
+	return nil
+}
@@ -0,0 +1,124 @@
+/*
+ * @license
+ * Copyright 2024 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package state
+
+import (
+	"errors"
+	"fmt"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/errutils"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/log"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/log/field"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/coordinate"
+	manifestloader "github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/manifest/loader"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/state"
+	"github.com/spf13/afero"
+	"slices"
+)
+
+func list(fs afero.Fs, manifestPath string, environments []string) error {
+	s, _, err := loadState(fs, manifestPath)
+	if err != nil {
+		return err
+	}
+
+	for _, env := range selectEnvironments(s, environments) {
+		entries := s.Entries(env)
+		log.WithFields(field.F("environment", env)).Info("Environment %q: %d configurations", env, len(entries))
+		for _, e := range entries {
+			log.WithFields(field.F("environment", env), field.Coordinate(e.Coordinate), field.F("remoteId", e.RemoteId)).Info("\t%s -> %s", e.Coordinate, e.RemoteId)
+		}
+	}
+	return nil
+}
+
+func show(fs afero.Fs, manifestPath string, c coordinate.Coordinate, environments []string) error {
+	s, _, err := loadState(fs, manifestPath)
+	if err != nil {
+		return err
+	}
+
+	found := false
+	for _, env := range selectEnvironments(s, environments) {
+		e, ok := s.Get(env, c)
+		if !ok {
+			continue
+		}
+		found = true
+		log.WithFields(field.F("environment", env), field.Coordinate(c), field.F("stateEntry", e)).Info("Environment %q:\n\tconfig type:  %s\n\tremote ID:    %s\n\tpayload hash: %s", env, e.ConfigType, e.RemoteId, e.PayloadHash)
+	}
+
+	if !found {
+		return fmt.Errorf("no deployment state found for %q", c)
+	}
+	return nil
+}
+
+func remove(fs afero.Fs, manifestPath string, c coordinate.Coordinate, environments []string) error {
+	s, statePath, err := loadState(fs, manifestPath)
+	if err != nil {
+		return err
+	}
+
+	removed := 0
+	for _, env := range environments {
+		if s.Remove(env, c) {
+			removed++
+			log.WithFields(field.F("environment", env), field.Coordinate(c)).Info("Removed %q from deployment state of environment %q", c, env)
+		} else {
+			log.WithFields(field.F("environment", env), field.Coordinate(c)).Warn("No deployment state found for %q in environment %q", c, env)
+		}
+	}
+
+	if removed == 0 {
+		return nil
+	}
+	return s.Write(fs, statePath)
+}
+
+// loadState loads the deployment state file defined in the given manifest, and returns it together with its path
+func loadState(fs afero.Fs, manifestPath string) (*state.State, string, error) {
+	m, errs := manifestloader.Load(&manifestloader.Context{
+		Fs:           fs,
+		ManifestPath: manifestPath,
+		Opts:         manifestloader.Options{DoNotResolveEnvVars: true},
+	})
+	if len(errs) > 0 {
+		errutils.PrintErrors(errs)
+		return nil, "", errors.New("error while loading manifest")
+	}
+
+	if !m.State.Enabled() {
+		return nil, "", fmt.Errorf("manifest %q does not define a deployment state file", manifestPath)
+	}
+
+	statePath := state.ResolvePath(manifestPath, m.State.Path)
+	s, err := state.Load(fs, statePath)
+	if err != nil {
+		return nil, "", err
+	}
+	return s, statePath, nil
+}
+
+// selectEnvironments returns the given environments, or all environments of the state if none are given
+func selectEnvironments(s *state.State, environments []string) []string {
+	if len(environments) == 0 {
+		return s.Environments()
+	}
+	selected := slices.Clone(environments)
+	slices.Sort(selected)
+	return selected
+}
@@ -0,0 +1,65 @@
+//go:build unit
+
+/*
+ * @license
+ * Copyright 2024 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package state
+
+import (
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/coordinate"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/state"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+const manifestWithState = `
+manifestVersion: 1.0
+projects: [{name: proj}]
+environmentGroups: [{name: default, environments: [{name: env, url: {value: "https://example.com"}, auth: {token: {name: TOKEN}}}]}]
+state:
+  path: state/state.json
+`
+
+func TestStateCommands(t *testing.T) {
+	c := coordinate.Coordinate{Project: "proj", Type: "auto-tag", ConfigId: "my-tag"}
+
+	fs := afero.NewMemMapFs()
+	require.NoError(t, afero.WriteFile(fs, "manifest.yaml", []byte(manifestWithState), 0644))
+
+	s := state.New()
+	s.Set("env", state.Entry{Coordinate: c, ConfigType: "classic", RemoteId: "1234"})
+	require.NoError(t, s.Write(fs, "state/state.json"))
+
+	assert.NoError(t, list(fs, "manifest.yaml", nil))
+	assert.NoError(t, show(fs, "manifest.yaml", c, nil))
+	assert.Error(t, show(fs, "manifest.yaml", c, []string{"other-env"}))
+
+	assert.NoError(t, remove(fs, "manifest.yaml", c, []string{"env"}))
+
+	loaded, err := state.Load(fs, "state/state.json")
+	require.NoError(t, err)
+	assert.Empty(t, loaded.Entries("env"))
+	assert.Error(t, show(fs, "manifest.yaml", c, nil))
+}
+
+func TestStateCommands_FailIfManifestDefinesNoState(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	require.NoError(t, afero.WriteFile(fs, "manifest.yaml", []byte("manifestVersion: 1.0\nprojects: [{name: proj}]\n"), 0644))
+
+	assert.Error(t, list(fs, "manifest.yaml", nil))
+}
@@ -0,0 +1,83 @@
+/*
+ * @license
+ * Copyright 2024 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package cancellation supports gracefully cancelling long-running operations, e.g. when monaco is interrupted.
+//
+// A cancelled operation shall not start any further work, but finish work that is already in progress. To allow this,
+// requests that are in progress use a context created by WithGracePeriod, which is only cancelled some time after the
+// operation was cancelled.
+package cancellation
+
+import (
+	"context"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/log"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+)
+
+// DefaultGracePeriod is the time work that is already in progress is given to finish after an operation was cancelled
+const DefaultGracePeriod = 30 * time.Second
+
+// SignalContext returns a copy of parent that is cancelled once the process receives SIGINT or SIGTERM. After the
+// first signal was received, the default signal behaviour is restored, so that a second signal terminates the process
+// immediately. The returned stop function releases all resources and must be called once the context is not needed
+// anymore.
+func SignalContext(parent context.Context) (ctx context.Context, stop context.CancelFunc) {
+	ctx, cancel := context.WithCancel(parent)
+
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+
+	go func() {
+		select {
+		case sig := <-signals:
+			signal.Stop(signals)
+			log.Warn("Received %v - cancelling. Operations in progress are finished, but no new ones are started. Repeat the signal to terminate immediately.", sig)
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	return ctx, func() {
+		signal.Stop(signals)
+		cancel()
+	}
+}
+
+// WithGracePeriod returns a context holding all values of ctx, which is cancelled gracePeriod after ctx is cancelled.
+// It is meant to be used for work that is already in progress when ctx is cancelled. The returned cancel function
+// releases all resources and must be called once the context is not needed anymore.
+func WithGracePeriod(ctx context.Context, gracePeriod time.Duration) (context.Context, context.CancelFunc) {
+	graceful, cancel := context.WithCancel(context.WithoutCancel(ctx))
+
+	stop := context.AfterFunc(ctx, func() {
+		timer := time.NewTimer(gracePeriod)
+		defer timer.Stop()
+
+		select {
+		case <-timer.C:
+			cancel()
+		case <-graceful.Done():
+		}
+	})
+
+	return graceful, func() {
+		stop()
+		cancel()
+	}
+}
@@ -0,0 +1,52 @@
+//go:build unit
+
+/*
+ * @license
+ * Copyright 2024 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cancellation
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+type ctxKey struct{}
+
+func TestWithGracePeriod_IsCancelledAfterGracePeriod(t *testing.T) {
+	parent, cancelParent := context.WithCancel(context.WithValue(context.TODO(), ctxKey{}, "value"))
+	ctx, cancel := WithGracePeriod(parent, 50*time.Millisecond)
+	defer cancel()
+
+	assert.Equal(t, "value", ctx.Value(ctxKey{}), "values of the parent must be kept")
+
+	cancelParent()
+	assert.NoError(t, ctx.Err(), "context must not be cancelled together with its parent")
+
+	select {
+	case <-ctx.Done():
+	case <-time.After(5 * time.Second):
+		require.Fail(t, "context was not cancelled after the grace period")
+	}
+}
+
+func TestWithGracePeriod_CancelReleasesContext(t *testing.T) {
+	ctx, cancel := WithGracePeriod(context.TODO(), time.Hour)
+	cancel()
+	assert.ErrorIs(t, ctx.Err(), context.Canceled)
+}
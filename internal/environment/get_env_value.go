@@ -24,22 +24,30 @@ import (
 )
 
 const (
-	ConcurrentRequestsEnvKey = "MONACO_CONCURRENT_REQUESTS"
-	defaultValueKey          = "DEFAULT"
+	ConcurrentRequestsEnvKey     = "MONACO_CONCURRENT_REQUESTS"
+	ConcurrentEnvironmentsEnvKey = "MONACO_CONCURRENT_ENVIRONMENTS"
+	ConcurrentConfigsEnvKey      = "MONACO_CONCURRENT_CONFIGS"
+	defaultValueKey              = "DEFAULT"
 )
 
 var defaultValuesInt = map[string]int{
-	ConcurrentRequestsEnvKey: 5,
-	defaultValueKey:          0,
+	ConcurrentRequestsEnvKey:     5,
+	ConcurrentEnvironmentsEnvKey: 1,
+	ConcurrentConfigsEnvKey:      10,
+	defaultValueKey:              0,
 }
 
 var logStringInt = map[string]string{
-	ConcurrentRequestsEnvKey: "Concurrent Request Limit: %d, from '%s' environment variable",
-	defaultValueKey:          "Environment variable %s: %d",
+	ConcurrentRequestsEnvKey:     "Concurrent Request Limit: %d, from '%s' environment variable",
+	ConcurrentEnvironmentsEnvKey: "Concurrent Environment Deployment Limit: %d, from '%s' environment variable",
+	ConcurrentConfigsEnvKey:      "Concurrent Configuration Deployment Limit: %d, from '%s' environment variable",
+	defaultValueKey:              "Environment variable %s: %d",
 }
 var logStringIntDefault = map[string]string{
-	ConcurrentRequestsEnvKey: "Concurrent Request Limit: %d, '%s' environment variable is NOT set, using default value",
-	defaultValueKey:          "Environment variable %s: %d, variable is NOT set, using default value",
+	ConcurrentRequestsEnvKey:     "Concurrent Request Limit: %d, '%s' environment variable is NOT set, using default value",
+	ConcurrentEnvironmentsEnvKey: "Concurrent Environment Deployment Limit: %d, '%s' environment variable is NOT set, using default value",
+	ConcurrentConfigsEnvKey:      "Concurrent Configuration Deployment Limit: %d, '%s' environment variable is NOT set, using default value",
+	defaultValueKey:              "Environment variable %s: %d, variable is NOT set, using default value",
 }
 
 func getDefaultInt(env string) int {
@@ -57,3 +57,19 @@ func TestWithParallelRequestLimitFromEnvOption(t *testing.T) {
 	require.Equal(t, 11, GetEnvValueIntLog(testEnvVar))
 	require.Equal(t, "Environment variable %s: %d", getLogMessage(testEnvVar, logStringInt))
 }
+
+func TestConcurrentEnvironmentsEnvValue(t *testing.T) {
+	t.Setenv(ConcurrentEnvironmentsEnvKey, "")
+	require.Equal(t, 1, GetEnvValueInt(ConcurrentEnvironmentsEnvKey), "expected default value if no env var is set")
+
+	t.Setenv(ConcurrentEnvironmentsEnvKey, "4")
+	require.Equal(t, 4, GetEnvValueInt(ConcurrentEnvironmentsEnvKey))
+}
+
+func TestConcurrentConfigsEnvValue(t *testing.T) {
+	t.Setenv(ConcurrentConfigsEnvKey, "")
+	require.Equal(t, 10, GetEnvValueInt(ConcurrentConfigsEnvKey), "expected default value if no env var is set")
+
+	t.Setenv(ConcurrentConfigsEnvKey, "25")
+	require.Equal(t, 25, GetEnvValueInt(ConcurrentConfigsEnvKey))
+}
@@ -20,6 +20,7 @@ import (
 	"encoding/base64"
 	"fmt"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/coordinate"
+	"strings"
 )
 
 // GenerateExternalID generates a string that serves as an external ID for a Settings 2.0 object.
@@ -49,4 +50,30 @@ func GenerateExternalID(c coordinate.Coordinate) (string, error) {
 	return fmt.Sprintf("%s%s", prefix, encodedID), nil
 }
 
+// CoordinateFromExternalID returns the [[coordinate.Coordinate]] an external ID was generated from by GenerateExternalID.
+// Only external IDs generated for a coordinate with a project can be resolved. The returned bool is false if the
+// external ID was not generated by monaco, was generated without a project, or was cut because of its length.
+func CoordinateFromExternalID(externalID string) (coordinate.Coordinate, bool) {
+	encodedID, found := strings.CutPrefix(externalID, "monaco:")
+	if !found {
+		return coordinate.Coordinate{}, false
+	}
+
+	decoded, err := base64.StdEncoding.DecodeString(encodedID)
+	if err != nil {
+		return coordinate.Coordinate{}, false
+	}
+
+	parts := strings.Split(string(decoded), "$")
+	if len(parts) != 3 {
+		return coordinate.Coordinate{}, false
+	}
+
+	c := coordinate.Coordinate{Project: parts[0], Type: parts[1], ConfigId: parts[2]}
+	if generated, err := GenerateExternalID(c); err != nil || generated != externalID {
+		return coordinate.Coordinate{}, false
+	}
+	return c, true
+}
+
 type ExternalIDGenerator func(coordinate.Coordinate) (string, error)
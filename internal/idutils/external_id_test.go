@@ -132,3 +132,20 @@ func TestGenerateExternalIdRawIdParts(t *testing.T) {
 	copy(rawId, decoded)
 	assert.Equal(t, "project-name$schema-id$config-id", string(decoded))
 }
+
+func TestCoordinateFromExternalID(t *testing.T) {
+	c := coordinate.Coordinate{Project: "project", Type: "builtin:alerting.profile", ConfigId: "my-config"}
+	externalID, _ := GenerateExternalID(c)
+
+	got, ok := CoordinateFromExternalID(externalID)
+	assert.True(t, ok)
+	assert.Equal(t, c, got)
+
+	legacyID, _ := GenerateExternalID(coordinate.Coordinate{Type: "builtin:alerting.profile", ConfigId: "my-config"})
+	tooLongID, _ := GenerateExternalID(coordinate.Coordinate{Project: "project", Type: "builtin:alerting.profile", ConfigId: strings.Repeat("a", 500)})
+
+	for _, id := range []string{"", "custom-external-id", "monaco:not-base64!", legacyID, tooLongID} {
+		_, ok := CoordinateFromExternalID(id)
+		assert.False(t, ok, "expected %q to not be resolved", id)
+	}
+}
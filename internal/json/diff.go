@@ -0,0 +1,180 @@
+/*
+ * @license
+ * Copyright 2024 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package json
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Difference describes a single value that differs between two JSON documents.
+type Difference struct {
+	// Path is the JSON pointer (RFC 6901) of the differing value, e.g. "/rules/0/name"
+	Path string `json:"path"`
+	// Current is the value found in the current document, nil if the value does not exist there
+	Current any `json:"current"`
+	// Desired is the value found in the desired document
+	Desired any `json:"desired"`
+}
+
+func (d Difference) String() string {
+	return fmt.Sprintf("%s: %s -> %s", d.Path, toJSONString(d.Current), toJSONString(d.Desired))
+}
+
+// Diff compares the desired JSON document with the current one and returns all found differences, ordered by path.
+//
+// Only properties present in the desired document are compared - additional properties of the current document are
+// ignored. This allows comparing a rendered configuration with an object returned by a Dynatrace API, which usually
+// contains additional server-managed properties like IDs or metadata.
+// Arrays are compared element by element if they are of the same length, otherwise the whole array is reported as a
+// single difference.
+func Diff(desired, current []byte) ([]Difference, error) {
+	d, c, err := unmarshalDocuments(desired, current)
+	if err != nil {
+		return nil, err
+	}
+
+	diffs := diffValues("", d, c)
+	sort.SliceStable(diffs, func(i, j int) bool { return diffs[i].Path < diffs[j].Path })
+	return diffs, nil
+}
+
+// Additional returns all properties that are only present in the current document, with a nil Desired value, ordered
+// by path. Properties of array elements are only compared if both arrays are of the same length, as Diff reports arrays
+// of different length as a whole.
+//
+// Additional properties are usually set by the server, e.g. defaults of properties not defined by a configuration.
+// Properties managed by the server, like IDs or metadata, must be removed from both documents before comparing them.
+func Additional(desired, current []byte) ([]Difference, error) {
+	d, c, err := unmarshalDocuments(desired, current)
+	if err != nil {
+		return nil, err
+	}
+
+	additional := additionalValues("", d, c)
+	sort.SliceStable(additional, func(i, j int) bool { return additional[i].Path < additional[j].Path })
+	return additional, nil
+}
+
+func unmarshalDocuments(desired, current []byte) (d, c any, err error) {
+	if err := json.Unmarshal(desired, &d); err != nil {
+		return nil, nil, fmt.Errorf("failed to unmarshal desired JSON: %w", err)
+	}
+
+	if len(current) > 0 {
+		if err := json.Unmarshal(current, &c); err != nil {
+			return nil, nil, fmt.Errorf("failed to unmarshal current JSON: %w", err)
+		}
+	}
+	return d, c, nil
+}
+
+func diffValues(path string, desired, current any) []Difference {
+	switch d := desired.(type) {
+	case map[string]any:
+		c, ok := current.(map[string]any)
+		if !ok {
+			return []Difference{{Path: rootPath(path), Current: current, Desired: desired}}
+		}
+
+		var diffs []Difference
+		for k, v := range d {
+			diffs = append(diffs, diffValues(path+"/"+escapePointerToken(k), v, c[k])...)
+		}
+		return diffs
+
+	case []any:
+		c, ok := current.([]any)
+		if !ok || len(c) != len(d) {
+			return []Difference{{Path: rootPath(path), Current: current, Desired: desired}}
+		}
+
+		var diffs []Difference
+		for i := range d {
+			diffs = append(diffs, diffValues(path+"/"+strconv.Itoa(i), d[i], c[i])...)
+		}
+		return diffs
+
+	default:
+		if !reflect.DeepEqual(desired, current) {
+			return []Difference{{Path: rootPath(path), Current: current, Desired: desired}}
+		}
+		return nil
+	}
+}
+
+func additionalValues(path string, desired, current any) []Difference {
+	switch d := desired.(type) {
+	case map[string]any:
+		c, ok := current.(map[string]any)
+		if !ok {
+			return nil
+		}
+
+		var additional []Difference
+		for k, v := range c {
+			if dv, exists := d[k]; exists {
+				additional = append(additional, additionalValues(path+"/"+escapePointerToken(k), dv, v)...)
+			} else {
+				additional = append(additional, Difference{Path: path + "/" + escapePointerToken(k), Current: v})
+			}
+		}
+		return additional
+
+	case []any:
+		c, ok := current.([]any)
+		if !ok || len(c) != len(d) {
+			return nil
+		}
+
+		var additional []Difference
+		for i := range d {
+			additional = append(additional, additionalValues(path+"/"+strconv.Itoa(i), d[i], c[i])...)
+		}
+		return additional
+
+	default:
+		return nil
+	}
+}
+
+func rootPath(path string) string {
+	if path == "" {
+		return "/"
+	}
+	return path
+}
+
+// escapePointerToken escapes a single path element as defined by RFC 6901
+func escapePointerToken(s string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(s, "~", "~0"), "/", "~1")
+}
+
+func toJSONString(v any) string {
+	if v == nil {
+		return "<none>"
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprintf("%v", v)
+	}
+	return string(b)
+}
@@ -0,0 +1,162 @@
+//go:build unit
+
+/*
+ * @license
+ * Copyright 2024 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package json
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestDiff(t *testing.T) {
+	tests := []struct {
+		name    string
+		desired string
+		current string
+		want    []Difference
+	}{
+		{
+			name:    "equal documents have no differences",
+			desired: `{"name": "a", "enabled": true, "list": [1, 2]}`,
+			current: `{"name": "a", "enabled": true, "list": [1, 2]}`,
+		},
+		{
+			name:    "additional properties of current document are ignored",
+			desired: `{"name": "a"}`,
+			current: `{"id": "1234", "name": "a", "metadata": {"clusterVersion": "1.0"}}`,
+		},
+		{
+			name:    "changed value is reported",
+			desired: `{"name": "a", "nested": {"value": 2}}`,
+			current: `{"name": "a", "nested": {"value": 1}}`,
+			want:    []Difference{{Path: "/nested/value", Current: float64(1), Desired: float64(2)}},
+		},
+		{
+			name:    "missing value is reported",
+			desired: `{"name": "a", "description": "new"}`,
+			current: `{"name": "a"}`,
+			want:    []Difference{{Path: "/description", Current: nil, Desired: "new"}},
+		},
+		{
+			name:    "array elements are compared one by one",
+			desired: `{"rules": [{"key": "a"}, {"key": "b"}]}`,
+			current: `{"rules": [{"key": "a", "id": "1"}, {"key": "c", "id": "2"}]}`,
+			want:    []Difference{{Path: "/rules/1/key", Current: "c", Desired: "b"}},
+		},
+		{
+			name:    "array with different length is reported as a whole",
+			desired: `{"list": [1, 2]}`,
+			current: `{"list": [1]}`,
+			want:    []Difference{{Path: "/list", Current: []any{float64(1)}, Desired: []any{float64(1), float64(2)}}},
+		},
+		{
+			name:    "changed type is reported",
+			desired: `{"value": {"a": 1}}`,
+			current: `{"value": "a"}`,
+			want:    []Difference{{Path: "/value", Current: "a", Desired: map[string]any{"a": float64(1)}}},
+		},
+		{
+			name:    "keys are escaped",
+			desired: `{"a/b": 1, "c~d": 2}`,
+			current: `{}`,
+			want: []Difference{
+				{Path: "/a~1b", Current: nil, Desired: float64(1)},
+				{Path: "/c~0d", Current: nil, Desired: float64(2)},
+			},
+		},
+		{
+			name:    "differences are sorted by path",
+			desired: `{"b": 1, "a": 1, "c": 1}`,
+			current: `{"b": 2, "a": 2, "c": 2}`,
+			want: []Difference{
+				{Path: "/a", Current: float64(2), Desired: float64(1)},
+				{Path: "/b", Current: float64(2), Desired: float64(1)},
+				{Path: "/c", Current: float64(2), Desired: float64(1)},
+			},
+		},
+		{
+			name:    "empty current document",
+			desired: `{"a": 1}`,
+			current: ``,
+			want:    []Difference{{Path: "/", Current: nil, Desired: map[string]any{"a": float64(1)}}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Diff([]byte(tt.desired), []byte(tt.current))
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestAdditional(t *testing.T) {
+	tests := []struct {
+		name    string
+		desired string
+		current string
+		want    []Difference
+	}{
+		{
+			name:    "equal documents have no additional properties",
+			desired: `{"name": "a", "list": [{"key": "a"}]}`,
+			current: `{"name": "a", "list": [{"key": "a"}]}`,
+		},
+		{
+			name:    "additional property of current document is reported",
+			desired: `{"name": "a"}`,
+			current: `{"name": "a", "description": "added"}`,
+			want:    []Difference{{Path: "/description", Current: "added", Desired: nil}},
+		},
+		{
+			name:    "additional nested properties of current document are reported",
+			desired: `{"nested": {"value": 1}, "rules": [{"key": "a"}]}`,
+			current: `{"nested": {"value": 1, "added": true}, "rules": [{"key": "a", "added": 2}]}`,
+			want: []Difference{
+				{Path: "/nested/added", Current: true, Desired: nil},
+				{Path: "/rules/0/added", Current: float64(2), Desired: nil},
+			},
+		},
+		{
+			name:    "changed and missing values are not reported",
+			desired: `{"name": "b", "description": "new", "rules": [{"key": "a"}]}`,
+			current: `{"name": "a", "rules": [{"key": "a", "added": 2}, {"key": "b"}]}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Additional([]byte(tt.desired), []byte(tt.current))
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestDiff_ReturnsErrorOnInvalidJSON(t *testing.T) {
+	_, err := Diff([]byte(`{`), []byte(`{}`))
+	assert.Error(t, err)
+
+	_, err = Diff([]byte(`{}`), []byte(`{`))
+	assert.Error(t, err)
+}
+
+func TestDifference_String(t *testing.T) {
+	assert.Equal(t, `/name: "a" -> "b"`, Difference{Path: "/name", Current: "a", Desired: "b"}.String())
+	assert.Equal(t, `/name: <none> -> "b"`, Difference{Path: "/name", Desired: "b"}.String())
+}
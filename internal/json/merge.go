@@ -0,0 +1,152 @@
+/*
+ * @license
+ * Copyright 2024 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package json
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ParsePointer splits the given JSON pointer (RFC 6901) into its unescaped tokens. The pointer must reference a value
+// within the document, the whole document ("") can not be referenced.
+func ParsePointer(pointer string) ([]string, error) {
+	if pointer == "" || pointer == "/" {
+		return nil, errors.New("pointer must reference a value within the document")
+	}
+	if !strings.HasPrefix(pointer, "/") {
+		return nil, fmt.Errorf("pointer %q must start with '/'", pointer)
+	}
+
+	tokens := strings.Split(pointer[1:], "/")
+	for i, t := range tokens {
+		tokens[i] = strings.ReplaceAll(strings.ReplaceAll(t, "~1", "/"), "~0", "~")
+	}
+	return tokens, nil
+}
+
+// MergeOwned returns the current JSON document with all values referenced by the given JSON pointers replaced by the
+// values of the desired document. Values missing in the desired document are removed. All other values of the current
+// document are kept.
+func MergeOwned(desired, current []byte, ownedPointers []string) ([]byte, error) {
+	return merge(current, desired, ownedPointers)
+}
+
+// MergeIgnored returns the desired JSON document with all values referenced by the given JSON pointers replaced by the
+// values of the current document. Values missing in the current document are removed.
+func MergeIgnored(desired, current []byte, ignoredPointers []string) ([]byte, error) {
+	return merge(desired, current, ignoredPointers)
+}
+
+// merge copies the values referenced by the given pointers from source to target and returns the resulting target
+func merge(target, source []byte, pointers []string) ([]byte, error) {
+	var t, s any
+	if err := json.Unmarshal(target, &t); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal JSON: %w", err)
+	}
+	if err := json.Unmarshal(source, &s); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal JSON: %w", err)
+	}
+
+	for _, p := range pointers {
+		tokens, err := ParsePointer(p)
+		if err != nil {
+			return nil, err
+		}
+
+		v, found := get(s, tokens)
+		if !found {
+			remove(t, tokens)
+			continue
+		}
+		if err := set(t, tokens, v); err != nil {
+			return nil, fmt.Errorf("failed to set value of %q: %w", p, err)
+		}
+	}
+
+	return json.Marshal(t)
+}
+
+func get(doc any, tokens []string) (any, bool) {
+	current := doc
+	for _, t := range tokens {
+		switch c := current.(type) {
+		case map[string]any:
+			v, found := c[t]
+			if !found {
+				return nil, false
+			}
+			current = v
+		case []any:
+			i, err := strconv.Atoi(t)
+			if err != nil || i < 0 || i >= len(c) {
+				return nil, false
+			}
+			current = c[i]
+		default:
+			return nil, false
+		}
+	}
+	return current, true
+}
+
+// set sets the value at the path of the given tokens. Missing parent objects are created, but array elements must exist.
+func set(doc any, tokens []string, value any) error {
+	current := doc
+	for i, t := range tokens {
+		last := i == len(tokens)-1
+
+		switch c := current.(type) {
+		case map[string]any:
+			if last {
+				c[t] = value
+				return nil
+			}
+			if _, found := c[t]; !found {
+				c[t] = make(map[string]any)
+			}
+			current = c[t]
+		case []any:
+			idx, err := strconv.Atoi(t)
+			if err != nil || idx < 0 || idx >= len(c) {
+				return fmt.Errorf("array element %q does not exist", t)
+			}
+			if last {
+				c[idx] = value
+				return nil
+			}
+			current = c[idx]
+		default:
+			return fmt.Errorf("parent of %q is neither an object nor an array", t)
+		}
+	}
+	return nil
+}
+
+// remove deletes the object property at the path of the given tokens, if it exists. Array elements are not removed, as
+// this would shift all following elements.
+func remove(doc any, tokens []string) {
+	parent, found := get(doc, tokens[:len(tokens)-1])
+	if !found {
+		return
+	}
+	if m, ok := parent.(map[string]any); ok {
+		delete(m, tokens[len(tokens)-1])
+	}
+}
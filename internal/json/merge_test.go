@@ -0,0 +1,97 @@
+//go:build unit
+
+/*
+ * @license
+ * Copyright 2024 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package json
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestParsePointer(t *testing.T) {
+	tokens, err := ParsePointer("/rules/0/a~1b~0c")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"rules", "0", "a/b~c"}, tokens)
+
+	for _, invalid := range []string{"", "/", "rules"} {
+		_, err := ParsePointer(invalid)
+		assert.Error(t, err, "pointer %q must be invalid", invalid)
+	}
+}
+
+func TestMergeOwned(t *testing.T) {
+	tests := []struct {
+		name    string
+		desired string
+		current string
+		owned   []string
+		want    string
+	}{
+		{
+			name:    "owned values are replaced, all others are kept",
+			desired: `{"name": "tag", "rules": [{"key": "a"}], "description": "ignored"}`,
+			current: `{"name": "old", "rules": [{"key": "a"}, {"key": "added in UI"}], "description": "kept"}`,
+			owned:   []string{"/name", "/rules"},
+			want:    `{"name": "tag", "rules": [{"key": "a"}], "description": "kept"}`,
+		},
+		{
+			name:    "owned values missing in desired document are removed",
+			desired: `{"name": "tag"}`,
+			current: `{"name": "old", "description": "removed"}`,
+			owned:   []string{"/name", "/description"},
+			want:    `{"name": "tag"}`,
+		},
+		{
+			name:    "missing parent objects are created",
+			desired: `{"settings": {"nested": {"value": 1}}}`,
+			current: `{"name": "old"}`,
+			owned:   []string{"/settings/nested/value"},
+			want:    `{"name": "old", "settings": {"nested": {"value": 1}}}`,
+		},
+		{
+			name:    "existing array elements can be owned",
+			desired: `{"tiles": [{"name": "managed"}]}`,
+			current: `{"tiles": [{"name": "old"}, {"name": "added by user"}]}`,
+			owned:   []string{"/tiles/0"},
+			want:    `{"tiles": [{"name": "managed"}, {"name": "added by user"}]}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := MergeOwned([]byte(tt.desired), []byte(tt.current), tt.owned)
+			require.NoError(t, err)
+			assert.JSONEq(t, tt.want, string(got))
+		})
+	}
+}
+
+func TestMergeIgnored(t *testing.T) {
+	got, err := MergeIgnored(
+		[]byte(`{"name": "dashboard", "tiles": [], "owner": "monaco"}`),
+		[]byte(`{"name": "old", "tiles": [{"name": "added by user"}]}`),
+		[]string{"/tiles", "/owner"})
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"name": "dashboard", "tiles": [{"name": "added by user"}]}`, string(got))
+}
+
+func TestMerge_FailsForMissingArrayElement(t *testing.T) {
+	_, err := MergeOwned([]byte(`{"tiles": [1, 2]}`), []byte(`{"tiles": [1]}`), []string{"/tiles/1"})
+	assert.ErrorContains(t, err, `array element "1" does not exist`)
+}
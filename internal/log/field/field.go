@@ -99,6 +99,18 @@ func StatusDeploymentSkipped() Field {
 	return statusField("skipped")
 }
 
+func StatusDeploymentUnchanged() Field {
+	return statusField("unchanged")
+}
+
+func StatusDeploymentRestored() Field {
+	return statusField("restored")
+}
+
+func StatusDeploymentCancelled() Field {
+	return statusField("cancelled")
+}
+
 func statusField(statusValue string) Field {
 	return Field{"deploymentStatus", statusValue}
 }
@@ -20,6 +20,7 @@ import (
 	"fmt"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/log/field"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/loggers"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/secret"
 	"github.com/go-logr/logr"
 	"github.com/go-logr/zapr"
 	"go.uber.org/zap"
@@ -103,24 +104,26 @@ func New(logOptions loggers.LogOptions) (*Logger, error) {
 
 	var cores []zapcore.Core
 
+	// all outputs mask values registered as sensitive, e.g. resolved secret parameters
+
 	// log to console on configured log level
-	consoleSyncer := zapcore.Lock(os.Stdout)
+	consoleSyncer := zapcore.Lock(zapcore.AddSync(secret.NewMaskingWriter(os.Stdout)))
 	cores = append(cores, zapcore.NewCore(encoder, consoleSyncer, logLevel))
 
 	if logOptions.File != nil {
 		debugLevel := zap.NewAtomicLevelAt(zapcore.DebugLevel) // always debug log to file
-		fileSyncer := zapcore.Lock(zapcore.AddSync(logOptions.File))
+		fileSyncer := zapcore.Lock(zapcore.AddSync(secret.NewMaskingWriter(logOptions.File)))
 		cores = append(cores, zapcore.NewCore(encoder, fileSyncer, debugLevel))
 	}
 
 	if logOptions.ErrorFile != nil {
 		errLevel := zap.NewAtomicLevelAt(zapcore.ErrorLevel) // only write errors to err file
-		fileSyncer := zapcore.Lock(zapcore.AddSync(logOptions.ErrorFile))
+		fileSyncer := zapcore.Lock(zapcore.AddSync(secret.NewMaskingWriter(logOptions.ErrorFile)))
 		cores = append(cores, zapcore.NewCore(encoder, fileSyncer, errLevel))
 	}
 
 	if logOptions.LogSpy != nil {
-		spySyncer := zapcore.Lock(zapcore.AddSync(logOptions.LogSpy))
+		spySyncer := zapcore.Lock(zapcore.AddSync(secret.NewMaskingWriter(logOptions.LogSpy)))
 		cores = append(cores, zapcore.NewCore(encoder, spySyncer, logLevel))
 	}
 
@@ -0,0 +1,91 @@
+/*
+ * @license
+ * Copyright 2024 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package secret
+
+import (
+	"encoding/json"
+	"io"
+	"slices"
+	"strings"
+	"sync"
+)
+
+const mask = "****"
+
+// MinSensitiveLength is the minimum length of values registered by RegisterSensitive. Shorter values are not masked, as
+// masking all of their occurrences would render logs unreadable.
+const MinSensitiveLength = 4
+
+var sensitive = struct {
+	mu     sync.RWMutex
+	values []string
+}{}
+
+// RegisterSensitive marks the given value as sensitive, so that Mask and writers returned by NewMaskingWriter replace
+// all of its occurrences. Its JSON-escaped form is masked as well, as sensitive values are usually part of JSON payloads.
+// Values shorter than MinSensitiveLength are ignored.
+func RegisterSensitive(value string) {
+	if len(value) < MinSensitiveLength {
+		return
+	}
+
+	variants := []string{value}
+	if b, err := json.Marshal(value); err == nil {
+		if escaped := string(b[1 : len(b)-1]); escaped != value {
+			variants = append(variants, escaped)
+		}
+	}
+
+	sensitive.mu.Lock()
+	defer sensitive.mu.Unlock()
+
+	for _, v := range variants {
+		if !slices.Contains(sensitive.values, v) {
+			sensitive.values = append(sensitive.values, v)
+		}
+	}
+	// replace longer values first, so that values containing other values are fully masked
+	slices.SortFunc(sensitive.values, func(a, b string) int { return len(b) - len(a) })
+}
+
+// Mask replaces all occurrences of values registered by RegisterSensitive in the given string
+func Mask(s string) string {
+	sensitive.mu.RLock()
+	defer sensitive.mu.RUnlock()
+
+	for _, v := range sensitive.values {
+		s = strings.ReplaceAll(s, v, mask)
+	}
+	return s
+}
+
+// NewMaskingWriter returns a writer that masks all values registered by RegisterSensitive before writing to w.
+// Each write is masked on its own, so a value split across two writes is not masked.
+func NewMaskingWriter(w io.Writer) io.Writer {
+	return maskingWriter{w: w}
+}
+
+type maskingWriter struct {
+	w io.Writer
+}
+
+func (m maskingWriter) Write(p []byte) (int, error) {
+	if _, err := io.WriteString(m.w, Mask(string(p))); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
@@ -0,0 +1,48 @@
+//go:build unit
+
+/*
+ * @license
+ * Copyright 2024 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package secret
+
+import (
+	"bytes"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestMask(t *testing.T) {
+	RegisterSensitive("s3cr3t")
+	RegisterSensitive(`pass"word`)
+	RegisterSensitive("")
+	RegisterSensitive("on")
+
+	assert.Equal(t, "token=****", Mask("token=s3cr3t"))
+	assert.Equal(t, `{"password": "****"}`, Mask(`{"password": "pass\"word"}`), "JSON-escaped values must be masked")
+	assert.Equal(t, "nothing to mask", Mask("nothing to mask"))
+	assert.Equal(t, "monitoring: on", Mask("monitoring: on"), "values shorter than the minimum length must not be masked")
+}
+
+func TestMaskingWriter(t *testing.T) {
+	RegisterSensitive("writer-secret")
+
+	var buf bytes.Buffer
+	n, err := NewMaskingWriter(&buf).Write([]byte("value: writer-secret\n"))
+	require.NoError(t, err)
+	assert.Equal(t, len("value: writer-secret\n"), n)
+	assert.Equal(t, "value: ****\n", buf.String())
+}
@@ -21,6 +21,7 @@ import (
 	lib "github.com/dynatrace/dynatrace-configuration-as-code-core/api/rest"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/log"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/log/field"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/secret"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/timeutils"
 	"github.com/google/uuid"
 	"github.com/spf13/afero"
@@ -125,14 +126,18 @@ func (l *FileBasedLogger) logRequest(id string, request *http.Request, body io.R
 	}
 
 	// write dump
-	if _, err := l.requestLogFile.WriteString(fmt.Sprintf("%s", string(dump))); err != nil {
+	if _, err := l.requestLogFile.WriteString(secret.Mask(string(dump))); err != nil {
 		return err
 	}
 
 	// write body
 	if body != nil {
 		defer body.Close()
-		if _, err := io.Copy(l.requestLogFile, body); err != nil {
+		b, err := io.ReadAll(body)
+		if err != nil {
+			return err
+		}
+		if _, err := l.requestLogFile.WriteString(secret.Mask(string(b))); err != nil {
 			return err
 		}
 	}
@@ -163,14 +168,18 @@ func (l *FileBasedLogger) logResponse(id string, response *http.Response, body i
 	}
 
 	// write dump
-	if _, err := l.responseLogFile.WriteString(fmt.Sprintf("%s", string(dump))); err != nil {
+	if _, err := l.responseLogFile.WriteString(secret.Mask(string(dump))); err != nil {
 		return err
 	}
 
 	// write body
 	if body != nil {
 		defer body.Close()
-		if _, err := io.Copy(l.responseLogFile, body); err != nil {
+		b, err := io.ReadAll(body)
+		if err != nil {
+			return err
+		}
+		if _, err := l.responseLogFile.WriteString(secret.Mask(string(b))); err != nil {
 			return err
 		}
 	}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	accountmanagement "github.com/dynatrace/dynatrace-configuration-as-code-core/gen/account_management"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/cancellation"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/log"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/log/field"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/loggers"
@@ -74,6 +75,10 @@ type AccountDeployer struct {
 	idMap                idMap
 	logger               loggers.Logger
 	maxConcurrentDeploys int
+	// ctx is used for all requests. Once a deployment is cancelled, requests in progress are given a grace period to finish.
+	ctx context.Context
+	// cancelled is done once the deployment is cancelled and no further resources shall be deployed
+	cancelled <-chan struct{}
 }
 
 func WithMaxConcurrentDeploys(maxConcurrentDeploys int) func(*AccountDeployer) {
@@ -87,6 +92,7 @@ func NewAccountDeployer(client client, opts ...func(*AccountDeployer)) *AccountD
 		accClient: client,
 		idMap:     newIdMap(),
 		logger:    log.WithFields(field.F("account", client.getAccountInfo().Name)),
+		ctx:       context.TODO(),
 	}
 	for _, o := range opts {
 		o(ac)
@@ -94,22 +100,54 @@ func NewAccountDeployer(client client, opts ...func(*AccountDeployer)) *AccountD
 	return ac
 }
 
-func (d *AccountDeployer) Deploy(res *account.Resources) error {
+// Deploy deploys all given resources to the account. Once ctx is cancelled, no further resources are deployed, and
+// requests in progress are given cancellation.DefaultGracePeriod to finish.
+func (d *AccountDeployer) Deploy(ctx context.Context, res *account.Resources) error {
+	d.cancelled = ctx.Done()
+	ctx, cancel := cancellation.WithGracePeriod(ctx, cancellation.DefaultGracePeriod)
+	defer cancel()
+	d.ctx = ctx
+
 	err := d.fetchExistingResources()
 	if err != nil {
 		return err
 	}
+	if err := d.checkCancelled(); err != nil {
+		return err
+	}
 
 	err = d.deployResources(res)
 	if err != nil {
 		return err
 	}
+	if err := d.checkCancelled(); err != nil {
+		return err
+	}
 
 	err = d.updateBindings(res)
 	if err != nil {
 		return err
 	}
 
+	return d.checkCancelled()
+}
+
+// isCancelled returns whether the deployment was cancelled, and no further resources shall be deployed
+func (d *AccountDeployer) isCancelled() bool {
+	select {
+	case <-d.cancelled:
+		return true
+	default:
+		return false
+	}
+}
+
+// checkCancelled returns an error if the deployment was cancelled
+func (d *AccountDeployer) checkCancelled() error {
+	if d.isCancelled() {
+		d.logger.Warn("Deployment was cancelled - remaining resources are not deployed")
+		return fmt.Errorf("deployment to account %q cancelled: %w", d.accClient.getAccountInfo().Name, context.Canceled)
+	}
 	return nil
 }
 
@@ -203,6 +241,9 @@ func (d *AccountDeployer) deployPolicies(policies map[string]account.Policy, dis
 		policy := policy
 		deployPolicyJob := func(wg *sync.WaitGroup, errCh chan error) {
 			defer wg.Done()
+			if d.isCancelled() {
+				return
+			}
 			d.logger.Info("Deploying policy %s", policy.Name)
 			pUuid, err := d.upsertPolicy(d.logCtx(), policy)
 			if err != nil {
@@ -219,6 +260,9 @@ func (d *AccountDeployer) deployGroups(groups map[string]account.Group, dispatch
 		group := group
 		deployGroupJob := func(wg *sync.WaitGroup, errCh chan error) {
 			defer wg.Done()
+			if d.isCancelled() {
+				return
+			}
 			d.logger.Info("Deploying group %s", group.Name)
 			gUuid, err := d.upsertGroup(d.logCtx(), group)
 			if err != nil {
@@ -237,6 +281,9 @@ func (d *AccountDeployer) deployUsers(users map[string]account.User, dispatcher
 		user := user
 		deployUserJob := func(wg *sync.WaitGroup, errCh chan error) {
 			defer wg.Done()
+			if d.isCancelled() {
+				return
+			}
 			d.logger.Info("Deploying user %s", user.Email)
 			if _, err := d.upsertUser(d.logCtx(), user); err != nil {
 				errCh <- fmt.Errorf("unable to deploy user for account %s: %w", d.accClient.getAccountInfo().AccountUUID, err)
@@ -254,6 +301,9 @@ func (d *AccountDeployer) deployGroupBindings(groups map[account.GroupId]account
 
 		updateBindingsJob := func(wg *sync.WaitGroup, errCh chan error) {
 			defer wg.Done()
+			if d.isCancelled() {
+				return
+			}
 			if err := d.updateGroupPolicyBindings(d.logCtx(), group); err != nil {
 				errCh <- fmt.Errorf("unable to deploy policy binding for account %s: %w", d.accClient.getAccountInfo().AccountUUID, err)
 			}
@@ -274,6 +324,9 @@ func (d *AccountDeployer) deployUserBindings(users map[account.UserId]account.Us
 		deployUserBindingsJob :=
 			func(wg *sync.WaitGroup, errCh chan error) {
 				defer wg.Done()
+				if d.isCancelled() {
+					return
+				}
 				d.logger.Info("Updating group bindings for user %s", user.Email)
 				if err := d.updateUserGroupBindings(d.logCtx(), user); err != nil {
 					errCh <- fmt.Errorf("unable to deploy user binding for account %s: %w", d.accClient.getAccountInfo().AccountUUID, err)
@@ -509,5 +562,5 @@ func (d *AccountDeployer) managementZoneIdLookup(envName, mzName string) remoteI
 }
 
 func (d *AccountDeployer) logCtx() context.Context {
-	return logr.NewContext(context.TODO(), d.logger.GetLogr())
+	return logr.NewContext(d.ctx, d.logger.GetLogr())
 }
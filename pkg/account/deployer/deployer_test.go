@@ -19,6 +19,7 @@
 package deployer
 
 import (
+	"context"
 	"errors"
 	accountmanagement "github.com/dynatrace/dynatrace-configuration-as-code-core/gen/account_management"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/account"
@@ -42,13 +43,29 @@ func mockClient(t *testing.T) *Mockclient {
 }
 
 func TestDeployer(t *testing.T) {
+	t.Run("Deployer - No resources are deployed once cancelled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.TODO())
+		defer cancel()
+
+		mockedClient := mockClient(t)
+		instance := NewAccountDeployer(mockedClient)
+		mockedClient.EXPECT().getAllGroups(gomock.Any()).DoAndReturn(func(context.Context) (map[string]remoteId, error) {
+			cancel()
+			return map[string]remoteId{}, nil
+		})
+		mockedClient.EXPECT().getManagementZones(gomock.Any()).Return([]accountmanagement.ManagementZoneResourceDto{}, nil)
+		mockedClient.EXPECT().getGlobalPolicies(gomock.Any()).Return(map[string]remoteId{}, nil)
+		err := instance.Deploy(ctx, testResources(t))
+		assert.ErrorIs(t, err, context.Canceled)
+	})
+
 	t.Run("Deployer - Getting global policies fails", func(t *testing.T) {
 		mockedClient := mockClient(t)
 		instance := NewAccountDeployer(mockedClient)
 		mockedClient.EXPECT().getAllGroups(gomock.Any()).Return(map[string]remoteId{}, nil)
 		mockedClient.EXPECT().getManagementZones(gomock.Any()).Return([]accountmanagement.ManagementZoneResourceDto{{"env12345", "Mzone", "-3664092122630505211"}}, nil)
 		mockedClient.EXPECT().getGlobalPolicies(gomock.Any()).Return(nil, errors.New("ERR - GET GLOBAL POLICIES"))
-		err := instance.Deploy(testResources(t))
+		err := instance.Deploy(context.TODO(), testResources(t))
 		assert.Error(t, err)
 	})
 
@@ -58,7 +75,7 @@ func TestDeployer(t *testing.T) {
 		mockedClient.EXPECT().getAllGroups(gomock.Any()).Return(map[string]remoteId{}, nil)
 		mockedClient.EXPECT().getGlobalPolicies(gomock.Any()).Return(nil, errors.New("ERR - GET GLOBAL POLICIES"))
 		mockedClient.EXPECT().getManagementZones(gomock.Any()).Return(nil, errors.New("ERR - GET MANAGEMENT ZONES"))
-		err := instance.Deploy(testResources(t))
+		err := instance.Deploy(context.TODO(), testResources(t))
 		assert.Error(t, err)
 	})
 
@@ -73,7 +90,7 @@ func TestDeployer(t *testing.T) {
 		mockedClient.EXPECT().upsertGroup(gomock.Any(), gomock.Any(), gomock.Any()).Return("3158497c-7fc7-44bc-ab15-c3ab8fea8560", nil)
 		mockedClient.EXPECT().upsertUser(gomock.Any(), gomock.Any()).Return("5b9aaf94-26d0-4464-a469-3d8563612554", nil)
 
-		err := instance.Deploy(testResources(t))
+		err := instance.Deploy(context.TODO(), testResources(t))
 		assert.Error(t, err)
 	})
 
@@ -88,7 +105,7 @@ func TestDeployer(t *testing.T) {
 		mockedClient.EXPECT().upsertGroup(gomock.Any(), gomock.Any(), gomock.Any()).Return("", errors.New("ERR - UPSERT GROUP"))
 		mockedClient.EXPECT().upsertUser(gomock.Any(), gomock.Any()).Return("5b9aaf94-26d0-4464-a469-3d8563612554", nil)
 
-		err := instance.Deploy(testResources(t))
+		err := instance.Deploy(context.TODO(), testResources(t))
 		assert.Error(t, err)
 	})
 
@@ -107,7 +124,7 @@ func TestDeployer(t *testing.T) {
 		mockedClient.EXPECT().updatePermissions(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
 		mockedClient.EXPECT().updateAccountPolicyBindings(gomock.Any(), gomock.Any(), gomock.Any()).Return(errors.New("ERR - POLICY BINDINGS"))
 
-		err := instance.Deploy(testResources(t))
+		err := instance.Deploy(context.TODO(), testResources(t))
 		assert.Error(t, err)
 	})
 
@@ -127,7 +144,7 @@ func TestDeployer(t *testing.T) {
 		mockedClient.EXPECT().updateGroupBindings(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
 		mockedClient.EXPECT().updatePermissions(gomock.Any(), gomock.Any(), gomock.Any()).Return(errors.New("ERR - GROUP PERMISSIONS"))
 
-		err := instance.Deploy(testResources(t))
+		err := instance.Deploy(context.TODO(), testResources(t))
 		assert.Error(t, err)
 	})
 
@@ -142,7 +159,7 @@ func TestDeployer(t *testing.T) {
 		mockedClient.EXPECT().upsertGroup(gomock.Any(), gomock.Any(), gomock.Any()).Return("3158497c-7fc7-44bc-ab15-c3ab8fea8560", nil)
 		mockedClient.EXPECT().upsertUser(gomock.Any(), gomock.Any()).Return("", errors.New("ERR - UPSERT USER"))
 
-		err := instance.Deploy(testResources(t))
+		err := instance.Deploy(context.TODO(), testResources(t))
 		assert.Error(t, err)
 	})
 
@@ -160,7 +177,7 @@ func TestDeployer(t *testing.T) {
 		mockedClient.EXPECT().updatePermissions(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
 		mockedClient.EXPECT().upsertUser(gomock.Any(), gomock.Any()).Return("5b9aaf94-26d0-4464-a469-3d8563612554", nil)
 		mockedClient.EXPECT().updateGroupBindings(gomock.Any(), gomock.Any(), gomock.Any()).Return(errors.New("ERR - GROUP BINDINGS"))
-		err := instance.Deploy(testResources(t))
+		err := instance.Deploy(context.TODO(), testResources(t))
 		assert.Error(t, err)
 	})
 
@@ -178,7 +195,7 @@ func TestDeployer(t *testing.T) {
 		mockedClient.EXPECT().updatePermissions(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
 		mockedClient.EXPECT().upsertUser(gomock.Any(), gomock.Any()).Return("5b9aaf94-26d0-4464-a469-3d8563612554", nil)
 		mockedClient.EXPECT().updateGroupBindings(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
-		err := instance.Deploy(testResources(t))
+		err := instance.Deploy(context.TODO(), testResources(t))
 		assert.NoError(t, err)
 	})
 }
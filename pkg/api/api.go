@@ -55,6 +55,7 @@ type API struct {
 	// or if they require a special format, e.g. a zip file.
 	//
 	// Those configs include all configs handling credentials, as well as the extension-API.
+	// Credential configs can still be deployed, by providing the credentials as `secret` parameters.
 	SkipDownload bool
 	// TweakResponseFunc can be optionally registered to add custom code that changes the
 	// payload of the downloaded api content (e.g. to exclude unwanted/unnecessary fields)
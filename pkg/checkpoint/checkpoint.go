@@ -0,0 +1,264 @@
+/*
+ * @license
+ * Copyright 2024 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package checkpoint holds the progress of a deployment. A checkpoint lists all configurations that were deployed
+// successfully, so that an interrupted deployment can be resumed without deploying them again.
+package checkpoint
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/log"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/log/field"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/coordinate"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/entities"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/parameter"
+	"github.com/spf13/afero"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Version of the checkpoint file format written by this package
+const Version = 1
+
+// Entry is a single configuration that was deployed successfully to an environment
+type Entry struct {
+	// Coordinate of the deployed configuration
+	Coordinate coordinate.Coordinate `json:"coordinate"`
+	// EntityName is the name of the object the configuration was deployed to
+	EntityName string `json:"entityName"`
+	// Properties are the resolved parameters of the configuration, including the ID of the object it was deployed to
+	Properties parameter.Properties `json:"properties"`
+	// PayloadHash is the hash of the deployed payload, see state.HashPayload. The entry is only valid as long as the
+	// configuration renders to the same payload.
+	PayloadHash string `json:"payloadHash"`
+}
+
+// ResolvedEntity returns the entity the configuration of the entry was deployed as
+func (e Entry) ResolvedEntity() entities.ResolvedEntity {
+	return entities.ResolvedEntity{
+		EntityName: e.EntityName,
+		Coordinate: e.Coordinate,
+		Properties: e.Properties,
+	}
+}
+
+// Checkpoint holds the Entry of each configuration deployed successfully, per environment. It is safe for concurrent use.
+type Checkpoint struct {
+	mu           sync.Mutex
+	environments map[string]map[coordinate.Coordinate]Entry
+	// autosave is nil unless the Checkpoint writes itself when changed, see Autosave
+	autosave *autosave
+}
+
+type autosave struct {
+	fs       afero.Fs
+	path     string
+	interval time.Duration
+	// lastWrite is the time the checkpoint was last written
+	lastWrite time.Time
+	// dirty is true if the checkpoint changed since it was last written
+	dirty bool
+}
+
+type checkpointFile struct {
+	Version      int                `json:"version"`
+	Environments map[string][]Entry `json:"environments"`
+}
+
+// New returns a new empty Checkpoint
+func New() *Checkpoint {
+	return &Checkpoint{environments: make(map[string]map[coordinate.Coordinate]Entry)}
+}
+
+// Load reads the Checkpoint stored at the given path. In contrast to state files, the checkpoint file must exist.
+func Load(fs afero.Fs, path string) (*Checkpoint, error) {
+	b, err := afero.ReadFile(fs, path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read checkpoint file %q: %w", path, err)
+	}
+
+	var f checkpointFile
+	if err := json.Unmarshal(b, &f); err != nil {
+		return nil, fmt.Errorf("failed to parse checkpoint file %q: %w", path, err)
+	}
+	if f.Version != Version {
+		return nil, fmt.Errorf("checkpoint file %q has unsupported version %d, expected version %d", path, f.Version, Version)
+	}
+
+	c := New()
+	for env, entries := range f.Environments {
+		for _, e := range entries {
+			c.Set(env, e)
+		}
+	}
+	return c, nil
+}
+
+// Write stores the Checkpoint at the given path. Missing parent directories are created.
+// The file is replaced atomically, so it is never left incomplete, even if the process is killed while writing it.
+func (c *Checkpoint) Write(fs afero.Fs, path string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.write(fs, path)
+}
+
+// Autosave makes the Checkpoint write itself to the given path whenever it changed, at most once per interval, so that
+// the progress of a deployment is kept even if the process is killed. Changes not written yet are written by Flush.
+func (c *Checkpoint) Autosave(fs afero.Fs, path string, interval time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.autosave = &autosave{fs: fs, path: path, interval: interval}
+}
+
+// Flush writes all changes not written yet, if Autosave is enabled. Otherwise, it does nothing.
+func (c *Checkpoint) Flush() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.autosave == nil || !c.autosave.dirty {
+		return nil
+	}
+	return c.save()
+}
+
+// changed writes the Checkpoint if Autosave is enabled and it was not written within the autosave interval.
+// c.mu must be held.
+func (c *Checkpoint) changed() {
+	if c.autosave == nil {
+		return
+	}
+
+	c.autosave.dirty = true
+	if time.Since(c.autosave.lastWrite) < c.autosave.interval {
+		return
+	}
+	if err := c.save(); err != nil {
+		log.WithFields(field.Error(err)).Warn("Failed to write deployment checkpoint: %v", err)
+	}
+}
+
+// save writes the Checkpoint to the autosave path. c.mu must be held.
+func (c *Checkpoint) save() error {
+	c.autosave.lastWrite = time.Now()
+	if err := c.write(c.autosave.fs, c.autosave.path); err != nil {
+		return err
+	}
+	c.autosave.dirty = false
+	return nil
+}
+
+// write stores the Checkpoint at the given path by writing a temporary file and renaming it. c.mu must be held.
+func (c *Checkpoint) write(fs afero.Fs, path string) error {
+	f := checkpointFile{
+		Version:      Version,
+		Environments: make(map[string][]Entry, len(c.environments)),
+	}
+	for env := range c.environments {
+		f.Environments[env] = c.entries(env)
+	}
+
+	b, err := json.MarshalIndent(f, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal checkpoint: %w", err)
+	}
+
+	if err := fs.MkdirAll(filepath.Dir(path), 0777); err != nil {
+		return fmt.Errorf("failed to create directory for checkpoint file %q: %w", path, err)
+	}
+
+	tmpPath := path + ".tmp"
+	if err := afero.WriteFile(fs, tmpPath, b, 0664); err != nil {
+		return fmt.Errorf("failed to write checkpoint file %q: %w", path, err)
+	}
+	if err := fs.Rename(tmpPath, path); err != nil {
+		return fmt.Errorf("failed to write checkpoint file %q: %w", path, err)
+	}
+	return nil
+}
+
+// Get returns the Entry of the configuration with the given coordinate in the given environment.
+// The returned bool is false if no such entry exists.
+func (c *Checkpoint) Get(environment string, coord coordinate.Coordinate) (Entry, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	e, found := c.environments[environment][coord]
+	return e, found
+}
+
+// Set stores the given Entry for the given environment, replacing any existing entry for the same coordinate.
+func (c *Checkpoint) Set(environment string, e Entry) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, found := c.environments[environment]; !found {
+		c.environments[environment] = make(map[coordinate.Coordinate]Entry)
+	}
+	c.environments[environment][e.Coordinate] = e
+	c.changed()
+}
+
+// Remove deletes the Entry of the configuration with the given coordinate in the given environment, e.g. as it is
+// invalid and the configuration needs to be deployed again.
+func (c *Checkpoint) Remove(environment string, coord coordinate.Coordinate) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	delete(c.environments[environment], coord)
+	if len(c.environments[environment]) == 0 {
+		delete(c.environments, environment)
+	}
+	c.changed()
+}
+
+// Environments returns the sorted names of all environments the Checkpoint holds entries for
+func (c *Checkpoint) Environments() []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	envs := make([]string, 0, len(c.environments))
+	for env := range c.environments {
+		envs = append(envs, env)
+	}
+	slices.Sort(envs)
+	return envs
+}
+
+// Entries returns all entries of the given environment, sorted by coordinate
+func (c *Checkpoint) Entries(environment string) []Entry {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.entries(environment)
+}
+
+// entries returns all entries of the given environment, sorted by coordinate. c.mu must be held.
+func (c *Checkpoint) entries(environment string) []Entry {
+	entries := make([]Entry, 0, len(c.environments[environment]))
+	for _, e := range c.environments[environment] {
+		entries = append(entries, e)
+	}
+	slices.SortFunc(entries, func(a, b Entry) int {
+		return strings.Compare(a.Coordinate.String(), b.Coordinate.String())
+	})
+	return entries
+}
@@ -0,0 +1,146 @@
+//go:build unit
+
+/*
+ * @license
+ * Copyright 2024 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package checkpoint_test
+
+import (
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/checkpoint"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/coordinate"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/entities"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/parameter"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/state"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+var entry = checkpoint.Entry{
+	Coordinate:  coordinate.Coordinate{Project: "p", Type: "auto-tag", ConfigId: "a"},
+	EntityName:  "a",
+	Properties:  parameter.Properties{"id": "a-id", "name": "a", "nested": map[string]any{"value": "v"}},
+	PayloadHash: state.HashPayload([]byte(`{"name": "a"}`)),
+}
+
+func TestCheckpoint_GetSetRemove(t *testing.T) {
+	c := checkpoint.New()
+
+	_, found := c.Get("env", entry.Coordinate)
+	assert.False(t, found)
+
+	c.Set("env", entry)
+	got, found := c.Get("env", entry.Coordinate)
+	assert.True(t, found)
+	assert.Equal(t, entry, got)
+
+	_, found = c.Get("other-env", entry.Coordinate)
+	assert.False(t, found)
+
+	c.Remove("env", entry.Coordinate)
+	_, found = c.Get("env", entry.Coordinate)
+	assert.False(t, found)
+	assert.Empty(t, c.Environments())
+}
+
+func TestEntry_ResolvedEntity(t *testing.T) {
+	assert.Equal(t, entities.ResolvedEntity{EntityName: "a", Coordinate: entry.Coordinate, Properties: entry.Properties}, entry.ResolvedEntity())
+}
+
+func TestWriteAndLoad(t *testing.T) {
+	fs := afero.NewMemMapFs()
+
+	c := checkpoint.New()
+	c.Set("env", entry)
+	require.NoError(t, c.Write(fs, "some/dir/checkpoint.json"))
+
+	loaded, err := checkpoint.Load(fs, "some/dir/checkpoint.json")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"env"}, loaded.Environments())
+	assert.Equal(t, []checkpoint.Entry{entry}, loaded.Entries("env"))
+}
+
+func TestLoad_ReturnsErrorOnInvalidFile(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+	}{
+		{"invalid JSON", `{`},
+		{"unsupported version", `{"version": 42, "environments": {}}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fs := afero.NewMemMapFs()
+			require.NoError(t, afero.WriteFile(fs, "checkpoint.json", []byte(tt.content), 0644))
+
+			_, err := checkpoint.Load(fs, "checkpoint.json")
+			assert.Error(t, err)
+		})
+	}
+
+	t.Run("missing file", func(t *testing.T) {
+		_, err := checkpoint.Load(afero.NewMemMapFs(), "checkpoint.json")
+		assert.ErrorContains(t, err, "failed to read checkpoint file")
+	})
+}
+
+func TestAutosave(t *testing.T) {
+	other := entry
+	other.Coordinate.ConfigId = "b"
+
+	loadEntries := func(t *testing.T, fs afero.Fs) []checkpoint.Entry {
+		loaded, err := checkpoint.Load(fs, "checkpoints/checkpoint.json")
+		require.NoError(t, err)
+		return loaded.Entries("env")
+	}
+
+	t.Run("changes are written immediately with interval zero", func(t *testing.T) {
+		fs := afero.NewMemMapFs()
+		c := checkpoint.New()
+		c.Autosave(fs, "checkpoints/checkpoint.json", 0)
+
+		c.Set("env", entry)
+		assert.Len(t, loadEntries(t, fs), 1)
+
+		c.Set("env", other)
+		assert.Len(t, loadEntries(t, fs), 2)
+
+		exists, err := afero.Exists(fs, "checkpoints/checkpoint.json.tmp")
+		require.NoError(t, err)
+		assert.False(t, exists, "temporary file must be renamed")
+	})
+
+	t.Run("changes within the interval are written by Flush", func(t *testing.T) {
+		fs := afero.NewMemMapFs()
+		c := checkpoint.New()
+		c.Autosave(fs, "checkpoints/checkpoint.json", time.Hour)
+
+		c.Set("env", entry)
+		c.Set("env", other)
+		assert.Len(t, loadEntries(t, fs), 1, "only the first change must be written within the interval")
+
+		require.NoError(t, c.Flush())
+		assert.Len(t, loadEntries(t, fs), 2)
+	})
+
+	t.Run("Flush does nothing without autosave", func(t *testing.T) {
+		c := checkpoint.New()
+		c.Set("env", entry)
+		assert.NoError(t, c.Flush())
+	})
+}
@@ -67,6 +67,11 @@ type ConfigClient interface {
 	//	 PUT <environment-url>/api/config/v1/alertingProfiles/<id> ... with the given (or found by unique name) entity ID
 	UpsertConfigByNonUniqueNameAndId(ctx context.Context, a api.API, entityID string, name string, payload []byte, duplicate bool) (entity DynatraceEntity, err error)
 
+	// UpdateConfigById updates the existing Dynatrace config with the given id, regardless of its current name.
+	// It calls the underlying PUT endpoint for the API. E.g. for alerting profiles this would be:
+	//    PUT <environment-url>/api/config/v1/alertingProfiles/<id> ... to update the config
+	UpdateConfigById(ctx context.Context, a api.API, id string, name string, payload []byte) (entity DynatraceEntity, err error)
+
 	// DeleteConfigById removes a given config for a given API using its id.
 	// It calls the DELETE endpoint for the API. E.g. for alerting profiles this would be:
 	//    DELETE <environment-url>/api/config/v1/alertingProfiles/<id> ... to delete the config
@@ -157,6 +162,14 @@ type ListSettingsOptions struct {
 // ListSettingsFilter can be used to filter fetched settings objects with custom criteria, e.g. o.ExternalId == ""
 type ListSettingsFilter func(DownloadSettingsObject) bool
 
+// EntitiesClient provides access to the monitored entities of an environment
+type EntitiesClient interface {
+	// ListEntities returns all monitored entities matching the given entity selector.
+	// It calls the underlying GET endpoint of the entities API:
+	//    GET <environment-url>/api/v2/entities?entitySelector=<entitySelector>
+	ListEntities(ctx context.Context, entitySelector string) ([]MonitoredEntity, error)
+}
+
 //go:generate mockgen -source=client.go -destination=client_mock.go -package=dtclient DynatraceClient
 
 // Client provides the functionality for performing basic CRUD operations on any Dynatrace API
@@ -170,6 +183,7 @@ type ListSettingsFilter func(DownloadSettingsObject) bool
 type Client interface {
 	ConfigClient
 	SettingsClient
+	EntitiesClient
 }
 
 // DynatraceClient is the default implementation of the HTTP
@@ -214,11 +228,15 @@ type DynatraceClient struct {
 
 	// classicConfigsCache caches classic settings values
 	classicConfigsCache cache.Cache[[]Value]
+
+	// entitiesCache caches monitored entities by entity selector
+	entitiesCache cache.Cache[[]MonitoredEntity]
 }
 
 var (
 	_ SettingsClient = (*DynatraceClient)(nil)
 	_ ConfigClient   = (*DynatraceClient)(nil)
+	_ EntitiesClient = (*DynatraceClient)(nil)
 	_ Client         = (*DynatraceClient)(nil)
 )
 
@@ -274,7 +292,7 @@ func WithAutoServerVersion() func(client *DynatraceClient) {
 }
 
 // WithCachingDisabled allows disabling the client's builtin caching mechanism for
-// classic configs, schema constraints and settings objects. Disabling the caching
+// classic configs, schema constraints, settings objects and monitored entities. Disabling the caching
 // is recommended in situations where configs are fetched immediately after their creation (e.g. in test scenarios)
 func WithCachingDisabled(disabled bool) func(client *DynatraceClient) {
 	return func(d *DynatraceClient) {
@@ -282,6 +300,7 @@ func WithCachingDisabled(disabled bool) func(client *DynatraceClient) {
 			d.classicConfigsCache = &cache.NoopCache[[]Value]{}
 			d.schemaConstraintsCache = &cache.NoopCache[SchemaConstraints]{}
 			d.settingsCache = &cache.NoopCache[[]DownloadSettingsObject]{}
+			d.entitiesCache = &cache.NoopCache[[]MonitoredEntity]{}
 		}
 	}
 }
@@ -332,6 +351,7 @@ func NewPlatformClient(dtURL string, classicURL string, client *rest.Client, cla
 		settingsCache:          &cache.DefaultCache[[]DownloadSettingsObject]{},
 		classicConfigsCache:    &cache.DefaultCache[[]Value]{},
 		schemaConstraintsCache: &cache.DefaultCache[SchemaConstraints]{},
+		entitiesCache:          &cache.DefaultCache[[]MonitoredEntity]{},
 	}
 
 	for _, o := range opts {
@@ -362,6 +382,7 @@ func NewClassicClient(dtURL string, client *rest.Client, opts ...func(dynatraceC
 		settingsCache:          &cache.DefaultCache[[]DownloadSettingsObject]{},
 		classicConfigsCache:    &cache.DefaultCache[[]Value]{},
 		schemaConstraintsCache: &cache.DefaultCache[SchemaConstraints]{},
+		entitiesCache:          &cache.DefaultCache[[]MonitoredEntity]{},
 	}
 
 	for _, o := range opts {
@@ -500,6 +521,23 @@ func (d *DynatraceClient) upsertConfigByNonUniqueNameAndId(ctx context.Context,
 	return d.upsertDynatraceEntityByNonUniqueNameAndId(ctx, entityId, name, api, payload, duplicate)
 }
 
+func (d *DynatraceClient) UpdateConfigById(ctx context.Context, api api.API, id string, name string, payload []byte) (entity DynatraceEntity, err error) {
+	d.limiter.ExecuteBlocking(func() {
+		entity, err = d.updateConfigById(ctx, api, id, name, payload)
+	})
+	return
+}
+
+func (d *DynatraceClient) updateConfigById(ctx context.Context, api api.API, id string, name string, payload []byte) (entity DynatraceEntity, err error) {
+	entity, err = d.updateDynatraceObject(ctx, api.CreateURL(d.environmentURLClassic), name, id, api, payload)
+	if err != nil {
+		return DynatraceEntity{}, err
+	}
+	// the name of the object might have changed
+	d.classicConfigsCache.Delete(api.ID)
+	return entity, nil
+}
+
 func (d *DynatraceClient) GetSettingById(objectId string) (res *DownloadSettingsObject, err error) {
 	d.limiter.ExecuteBlocking(func() {
 		res, err = d.getSettingById(context.TODO(), objectId)
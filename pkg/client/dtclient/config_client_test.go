@@ -459,6 +459,24 @@ func TestUpsertConfigByName(t *testing.T) {
 	}
 }
 
+func TestUpdateConfigById(t *testing.T) {
+	var requests []string
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		requests = append(requests, req.Method+" "+req.URL.Path)
+		rw.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	dtClient, _ := NewDynatraceClientForTesting(server.URL, server.Client())
+	testApi := api.API{ID: "test", URLPath: "/test/api", PropertyNameOfGetAllResponse: api.StandardApiPropertyNameOfGetAllResponse}
+
+	entity, err := dtClient.UpdateConfigById(context.TODO(), testApi, "42", "MY CONFIG", []byte(`{}`))
+	assert.NoError(t, err)
+	assert.Equal(t, "42", entity.Id)
+	assert.Equal(t, "MY CONFIG", entity.Name)
+	assert.Equal(t, []string{"PUT /test/api/42"}, requests, "object must be updated without listing existing objects")
+}
+
 type testServerResponse struct {
 	statusCode int
 	body       string
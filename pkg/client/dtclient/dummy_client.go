@@ -192,6 +192,23 @@ func (c *DummyClient) UpsertConfigByNonUniqueNameAndId(_ context.Context, a api.
 	}, nil
 }
 
+func (c *DummyClient) UpdateConfigById(_ context.Context, a api.API, id string, name string, data []byte) (entity DynatraceEntity, err error) {
+	entries, _ := c.GetEntries(a)
+
+	for _, entry := range entries {
+		if entry.Id == id {
+			c.writeRequest(a, name, data)
+
+			return DynatraceEntity{
+				Id:   id,
+				Name: name,
+			}, nil
+		}
+	}
+
+	return DynatraceEntity{}, fmt.Errorf("object with id %q not found", id)
+}
+
 func (c *DummyClient) writeRequest(a api.API, name string, payload []byte) {
 	if c.Fs == nil {
 		return
@@ -289,3 +306,14 @@ func (c *DummyClient) ListSettings(_ context.Context, _ string, _ ListSettingsOp
 func (c *DummyClient) DeleteSettings(_ string) error {
 	return nil
 }
+
+// ListEntities returns a single entity for every entity selector, so that entity parameters can be resolved during
+// dry-runs. The same ID is returned for the same selector.
+func (c *DummyClient) ListEntities(_ context.Context, entitySelector string) ([]MonitoredEntity, error) {
+	return []MonitoredEntity{
+		{
+			EntityId:    "DUMMY-" + uuid.NewSHA1(uuid.NameSpaceOID, []byte(entitySelector)).String(),
+			DisplayName: entitySelector,
+		},
+	}, nil
+}
@@ -0,0 +1,82 @@
+/*
+ * @license
+ * Copyright 2024 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package dtclient
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/log"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/rest"
+	"net/url"
+)
+
+const entitiesAPIPath = "/api/v2/entities"
+
+// MonitoredEntity is a monitored entity as returned by the entities API
+type MonitoredEntity struct {
+	EntityId    string `json:"entityId"`
+	Type        string `json:"type"`
+	DisplayName string `json:"displayName"`
+}
+
+func (d *DynatraceClient) ListEntities(ctx context.Context, entitySelector string) (res []MonitoredEntity, err error) {
+	d.limiter.ExecuteBlocking(func() {
+		res, err = d.listEntities(ctx, entitySelector)
+	})
+	return
+}
+
+func (d *DynatraceClient) listEntities(ctx context.Context, entitySelector string) ([]MonitoredEntity, error) {
+	if entities, cached := d.entitiesCache.Get(entitySelector); cached {
+		log.WithCtxFields(ctx).Debug("Using cached entities for entity selector %s", entitySelector)
+		return entities, nil
+	}
+
+	params := url.Values{
+		"entitySelector": []string{entitySelector},
+		"pageSize":       []string{defaultPageSize},
+	}
+
+	result := make([]MonitoredEntity, 0)
+
+	addToResult := func(body []byte) (int, error) {
+		var parsed struct {
+			Entities []MonitoredEntity `json:"entities"`
+		}
+		if err := json.Unmarshal(body, &parsed); err != nil {
+			return 0, fmt.Errorf("failed to unmarshal response: %w", err)
+		}
+
+		result = append(result, parsed.Entities...)
+		return len(parsed.Entities), nil
+	}
+
+	u, err := buildUrl(d.environmentURLClassic, entitiesAPIPath, params)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request for entity selector %q: %w", entitySelector, err)
+	}
+
+	_, err = rest.ListPaginated(ctx, d.classicClient, d.retrySettings, u, entitySelector, addToResult)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list entities matching %q: %w", entitySelector, err)
+	}
+
+	d.entitiesCache.Set(entitySelector, result)
+
+	return result, nil
+}
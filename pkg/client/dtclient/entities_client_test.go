@@ -0,0 +1,76 @@
+//go:build unit
+
+/*
+ * @license
+ * Copyright 2024 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package dtclient
+
+import (
+	"context"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/rest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestListEntities(t *testing.T) {
+	apiHits := 0
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		apiHits++
+		assert.Equal(t, entitiesAPIPath, req.URL.Path)
+
+		if req.URL.Query().Get("nextPageKey") == "page2" {
+			assert.Empty(t, req.URL.Query().Get("entitySelector"), "api/v2 requests must not repeat query params on following pages")
+			_, _ = rw.Write([]byte(`{"totalCount": 2, "entities": [{"entityId": "HOST_GROUP-2", "type": "HOST_GROUP", "displayName": "payments"}]}`))
+			return
+		}
+
+		assert.Equal(t, `type(HOST_GROUP),entityName("payments")`, req.URL.Query().Get("entitySelector"))
+		_, _ = rw.Write([]byte(`{"totalCount": 2, "nextPageKey": "page2", "entities": [{"entityId": "HOST_GROUP-1", "type": "HOST_GROUP", "displayName": "payments"}]}`))
+	}))
+	defer server.Close()
+
+	restClient := rest.NewRestClient(server.Client(), nil, rest.CreateRateLimitStrategy())
+	d, _ := NewClassicClient(server.URL, restClient)
+
+	entities, err := d.ListEntities(context.TODO(), `type(HOST_GROUP),entityName("payments")`)
+	require.NoError(t, err)
+	assert.Equal(t, []MonitoredEntity{
+		{EntityId: "HOST_GROUP-1", Type: "HOST_GROUP", DisplayName: "payments"},
+		{EntityId: "HOST_GROUP-2", Type: "HOST_GROUP", DisplayName: "payments"},
+	}, entities)
+	assert.Equal(t, 2, apiHits)
+
+	_, err = d.ListEntities(context.TODO(), `type(HOST_GROUP),entityName("payments")`)
+	require.NoError(t, err)
+	assert.Equal(t, 2, apiHits, "entities must be cached per entity selector")
+}
+
+func TestListEntities_Error(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.WriteHeader(http.StatusBadRequest)
+		_, _ = rw.Write([]byte(`{"error": {"code": 400, "message": "Invalid entity selector"}}`))
+	}))
+	defer server.Close()
+
+	restClient := rest.NewRestClient(server.Client(), nil, rest.CreateRateLimitStrategy())
+	d, _ := NewClassicClient(server.URL, restClient, WithRetrySettings(rest.RetrySettings{Normal: rest.RetrySetting{MaxRetries: 1}}))
+
+	_, err := d.ListEntities(context.TODO(), "type(")
+	assert.ErrorContains(t, err, `failed to list entities matching "type("`)
+}
@@ -17,6 +17,7 @@
 package config
 
 import (
+	"context"
 	"fmt"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/json"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/coordinate"
@@ -24,10 +25,15 @@ import (
 	configErrors "github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/errors"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/parameter"
 	compoundParam "github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/parameter/compound"
+	entityParam "github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/parameter/entity"
 	envParam "github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/parameter/environment"
+	fileParam "github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/parameter/file"
 	listParam "github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/parameter/list"
+	lookupParam "github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/parameter/lookup"
 	refParam "github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/parameter/reference"
+	secretParam "github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/parameter/secret"
 	valueParam "github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/parameter/value"
+	variableParam "github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/parameter/variable"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/template"
 )
 
@@ -123,6 +129,30 @@ func (BucketType) ID() TypeId {
 	return BucketTypeId
 }
 
+// DeployPolicy defines whether a config may create and/or update the object it is deployed to
+type DeployPolicy string
+
+const (
+	// DeployPolicyUpsert creates the object if it does not exist, and updates it otherwise. It is the default policy.
+	DeployPolicyUpsert DeployPolicy = "upsert"
+	// DeployPolicyCreateOnly creates the object if it does not exist, but never updates an existing object.
+	DeployPolicyCreateOnly DeployPolicy = "createOnly"
+	// DeployPolicyUpdateOnly updates the object if it exists, but never creates it.
+	DeployPolicyUpdateOnly DeployPolicy = "updateOnly"
+)
+
+// DeployPolicies contains all valid deploy policies
+var DeployPolicies = []DeployPolicy{DeployPolicyUpsert, DeployPolicyCreateOnly, DeployPolicyUpdateOnly}
+
+// Merge defines that a config is merged into the existing object it is deployed to, instead of replacing the whole
+// object. Exactly one of OwnedPaths and IgnoredPaths is set.
+type Merge struct {
+	// OwnedPaths are the JSON pointers of all values managed by the config. All other values of the object are kept.
+	OwnedPaths []string
+	// IgnoredPaths are the JSON pointers of all values not managed by the config. They are kept as found in the object.
+	IgnoredPaths []string
+}
+
 // Config struct defining a configuration which can be deployed.
 type Config struct {
 	// template used to render the request send to the dynatrace api
@@ -139,6 +169,9 @@ type Config struct {
 	// in the template
 	Parameters Parameters
 
+	// ParameterConstraints are the constraints of parameters by parameter name, checked once the parameters are resolved
+	ParameterConstraints map[string]parameter.Constraints
+
 	// Skip flag indicates if the deployment of this configuration should be skipped. It is resolved during project loading.
 	Skip bool
 
@@ -148,6 +181,20 @@ type Config struct {
 
 	// OriginObjectId is the DT object ID of the object when it was downloaded from an environment
 	OriginObjectId string
+
+	// DeployPolicy defines whether the config may create and/or update its object. Empty means DeployPolicyUpsert.
+	DeployPolicy DeployPolicy
+
+	// Merge defines how the config is merged into its existing object. If it is nil, the object is replaced as a whole.
+	Merge *Merge
+}
+
+// EffectiveDeployPolicy returns the DeployPolicy of the config, defaulting to DeployPolicyUpsert
+func (c *Config) EffectiveDeployPolicy() DeployPolicy {
+	if c.DeployPolicy == "" {
+		return DeployPolicyUpsert
+	}
+	return c.DeployPolicy
 }
 
 func (c *Config) Render(properties map[string]interface{}) (string, error) {
@@ -202,6 +249,11 @@ var DefaultParameterParsers = map[string]parameter.ParameterSerDe{
 	envParam.EnvironmentVariableParameterType: envParam.EnvironmentVariableParameterSerde,
 	compoundParam.CompoundParameterType:       compoundParam.CompoundParameterSerde,
 	listParam.ListParameterType:               listParam.ListParameterSerde,
+	fileParam.FileParameterType:               fileParam.FileParameterSerde,
+	secretParam.SecretParameterType:           secretParam.SecretParameterSerde,
+	entityParam.EntityParameterType:           entityParam.EntityParameterSerde,
+	lookupParam.LookupParameterType:           lookupParam.LookupParameterSerde,
+	variableParam.VariableParameterType:       variableParam.VariableParameterSerde,
 }
 
 func (c *Config) References() []coordinate.Coordinate {
@@ -238,9 +290,13 @@ type EntityLookup interface {
 // config.ResolvedEntity values of configurations that the config.Config could depend on.
 // Ordering of configurations to ensure that possible dependency configurations are contained in teh EntityLookup is responsibility
 // of the caller of ResolveParameterValues.
+// Entity parameters can only be resolved if the EntityLookup also implements parameter.EntitySelectorResolver, and
+// lookup parameters only if it implements parameter.ObjectLookup.
+//
+// The given context is passed to parameters making requests, e.g. to secret stores.
 //
 // ResolveParameterValues will return a slice of errors for any failures during sorting or resolving parameters.
-func (c *Config) ResolveParameterValues(entities EntityLookup) (parameter.Properties, []error) {
+func (c *Config) ResolveParameterValues(ctx context.Context, entities EntityLookup) (parameter.Properties, []error) {
 	if c == nil {
 		return nil, nil
 	}
@@ -250,7 +306,7 @@ func (c *Config) ResolveParameterValues(entities EntityLookup) (parameter.Proper
 	parameters, sortErrs := getSortedParameters(c)
 	errors = append(errors, sortErrs...)
 
-	properties, errs := resolveValues(c, entities, parameters)
+	properties, errs := resolveValues(ctx, c, entities, parameters)
 	errors = append(errors, errs...)
 
 	if len(errors) > 0 {
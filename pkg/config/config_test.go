@@ -19,13 +19,16 @@
 package config
 
 import (
+	"context"
 	"errors"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/coordinate"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/entities"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/parameter"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/parameter/value"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/template"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"testing"
 )
 
@@ -85,7 +88,7 @@ func TestResolveParameterValues(t *testing.T) {
 		Skip:        false,
 	}
 
-	values, errs := conf.ResolveParameterValues(entityLookup{})
+	values, errs := conf.ResolveParameterValues(context.TODO(), entityLookup{})
 
 	assert.Empty(t, errs, "there should be no errors (errors: %s)", errs)
 	assert.Equal(t, name, values[NameParameter])
@@ -125,11 +128,62 @@ func TestResolveParameterValuesShouldFailWhenReferencingNonExistingConfig(t *tes
 		Skip:        false,
 	}
 
-	_, errs := conf.ResolveParameterValues(entityLookup{})
+	_, errs := conf.ResolveParameterValues(context.TODO(), entityLookup{})
 
 	assert.NotEmpty(t, errs, "there should be errors (no errors: %d)", len(errs))
 }
 
+func TestResolveParameterValuesChecksConstraints(t *testing.T) {
+	maxThreshold := 100.0
+
+	conf := Config{
+		Template:    generateDummyTemplate(t),
+		Coordinate:  coordinate.Coordinate{Project: "project1", Type: "dashboard", ConfigId: "dashboard-1"},
+		Environment: "development",
+		Parameters: Parameters{
+			NameParameter: &parameter.DummyParameter{Value: "dashboard"},
+			"threshold":   &parameter.DummyParameter{Value: "150"},
+			"stage":       &parameter.DummyParameter{Value: "prod"},
+		},
+		ParameterConstraints: map[string]parameter.Constraints{
+			"threshold": {Type: parameter.ConstraintTypeInt, Max: &maxThreshold},
+			"stage":     {Enum: []string{"dev", "prod"}},
+		},
+	}
+
+	_, errs := conf.ResolveParameterValues(context.TODO(), entityLookup{})
+
+	require.Len(t, errs, 1)
+	var resolveErr parameter.ParameterResolveValueError
+	require.ErrorAs(t, errs[0], &resolveErr)
+	assert.Equal(t, "threshold", resolveErr.ParameterName)
+	assert.ErrorContains(t, errs[0], `constraint violated: value "150" is greater than the maximum 100`)
+}
+
+func TestResolveParameterValuesChecksConstraintsAgainstUnescapedValues(t *testing.T) {
+	conf := Config{
+		Template:    generateDummyTemplate(t),
+		Coordinate:  coordinate.Coordinate{Project: "project1", Type: "dashboard", ConfigId: "dashboard-1"},
+		Environment: "development",
+		Parameters: Parameters{
+			NameParameter: value.New("dashboard"),
+			"quoted":      value.New(`say "hello"`),
+			"path":        value.New(`C:\temp`),
+			"multiline":   value.New("first\nsecond"),
+		},
+		ParameterConstraints: map[string]parameter.Constraints{
+			"quoted":    {Pattern: `say "[a-z]+"`},
+			"path":      {Enum: []string{`C:\temp`}},
+			"multiline": {Pattern: "first\nsecond"},
+		},
+	}
+
+	properties, errs := conf.ResolveParameterValues(context.TODO(), entityLookup{})
+
+	require.Empty(t, errs)
+	assert.Equal(t, `say \"hello\"`, properties["quoted"], "resolved values must still be escaped")
+}
+
 func TestResolveParameterValuesShouldFailWhenReferencingSkippedConfig(t *testing.T) {
 	referenceCoordinate := coordinate.Coordinate{
 		Project:  "project1",
@@ -172,7 +226,7 @@ func TestResolveParameterValuesShouldFailWhenReferencingSkippedConfig(t *testing
 		},
 	}
 
-	_, errs := conf.ResolveParameterValues(lookup)
+	_, errs := conf.ResolveParameterValues(context.TODO(), lookup)
 
 	assert.NotEmpty(t, errs, "there should be errors (no errors: %d)", len(errs))
 }
@@ -199,7 +253,7 @@ func TestResolveParameterValuesShouldFailWhenParameterResolveReturnsError(t *tes
 		Skip:        false,
 	}
 
-	_, errs := conf.ResolveParameterValues(entityLookup{})
+	_, errs := conf.ResolveParameterValues(context.TODO(), entityLookup{})
 
 	assert.NotEmpty(t, errs, "there should be errors (no : %d)", len(errs))
 }
@@ -382,7 +436,7 @@ func TestConfigMethodsAreNilSafe(t *testing.T) {
 		var c *Config
 		c = nil
 		assert.NotPanics(t, func() {
-			_, _ = c.ResolveParameterValues(nil)
+			_, _ = c.ResolveParameterValues(context.TODO(), nil)
 		})
 	})
 }
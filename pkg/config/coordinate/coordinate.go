@@ -14,7 +14,10 @@
 
 package coordinate
 
-import "fmt"
+import (
+	"fmt"
+	"strings"
+)
 
 // Coordinate struct used to specify the location of a certain configuration
 type Coordinate struct {
@@ -38,3 +41,23 @@ func (c Coordinate) Match(coordinate Coordinate) bool {
 		c.Type == coordinate.Type &&
 		c.ConfigId == coordinate.ConfigId
 }
+
+// Parse parses a coordinate of the form "project:type:configId". As Settings schema IDs contain colons as well,
+// the project is everything before the first colon, and the config ID everything after the last one.
+func Parse(s string) (Coordinate, error) {
+	first := strings.Index(s, ":")
+	last := strings.LastIndex(s, ":")
+	if first < 0 || first == last {
+		return Coordinate{}, fmt.Errorf("invalid configuration coordinate %q: expected the format 'project:type:configId'", s)
+	}
+
+	c := Coordinate{
+		Project:  s[:first],
+		Type:     s[first+1 : last],
+		ConfigId: s[last+1:],
+	}
+	if c.Project == "" || c.Type == "" || c.ConfigId == "" {
+		return Coordinate{}, fmt.Errorf("invalid configuration coordinate %q: project, type and configId must not be empty", s)
+	}
+	return c, nil
+}
@@ -68,3 +68,30 @@ func TestMatchShouldReturnFalseOnNonMatchingSameApi(t *testing.T) {
 
 	assert.False(t, result, "shouldn't match")
 }
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		given   string
+		want    Coordinate
+		wantErr bool
+	}{
+		{given: "proj:auto-tag:my-tag", want: Coordinate{Project: "proj", Type: "auto-tag", ConfigId: "my-tag"}},
+		{given: "proj:builtin:alerting.profile:profile", want: Coordinate{Project: "proj", Type: "builtin:alerting.profile", ConfigId: "profile"}},
+		{given: "proj:auto-tag", wantErr: true},
+		{given: ":auto-tag:my-tag", wantErr: true},
+		{given: "proj:auto-tag:", wantErr: true},
+		{given: "proj::my-tag", wantErr: true},
+		{given: "proj", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.given, func(t *testing.T) {
+			got, err := Parse(tt.given)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
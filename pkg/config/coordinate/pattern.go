@@ -0,0 +1,58 @@
+// @license
+// Copyright 2024 Dynatrace LLC
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package coordinate
+
+import (
+	"fmt"
+	"path"
+)
+
+// Pattern matches coordinates of the given project and type, whose config ID matches a glob pattern
+type Pattern struct {
+	Project string
+	Type    string
+	// ConfigId is a glob pattern as supported by path.Match, e.g. "dashboard-*"
+	ConfigId string
+}
+
+// ParsePattern parses a pattern of the form "project:type:configId", see Parse.
+func ParsePattern(s string) (Pattern, error) {
+	c, err := Parse(s)
+	if err != nil {
+		return Pattern{}, err
+	}
+
+	if _, err := path.Match(c.ConfigId, ""); err != nil {
+		return Pattern{}, fmt.Errorf("invalid configuration coordinate %q: %w", s, err)
+	}
+	return Pattern{
+		Project:  c.Project,
+		Type:     c.Type,
+		ConfigId: c.ConfigId,
+	}, nil
+}
+
+func (p Pattern) String() string {
+	return fmt.Sprintf("%s:%s:%s", p.Project, p.Type, p.ConfigId)
+}
+
+// Matches tests if the given coordinate matches this pattern
+func (p Pattern) Matches(c Coordinate) bool {
+	if c.Project != p.Project || c.Type != p.Type {
+		return false
+	}
+	matched, _ := path.Match(p.ConfigId, c.ConfigId) // the pattern is validated by ParsePattern
+	return matched
+}
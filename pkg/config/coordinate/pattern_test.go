@@ -0,0 +1,60 @@
+// @license
+// Copyright 2024 Dynatrace LLC
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build unit
+
+package coordinate
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestParsePattern(t *testing.T) {
+	tests := []struct {
+		given   string
+		want    Pattern
+		wantErr bool
+	}{
+		{given: "proj:dashboard:my-dashboard", want: Pattern{Project: "proj", Type: "dashboard", ConfigId: "my-dashboard"}},
+		{given: "proj:builtin:alerting.profile:profile-*", want: Pattern{Project: "proj", Type: "builtin:alerting.profile", ConfigId: "profile-*"}},
+		{given: "proj:dashboard", wantErr: true},
+		{given: "proj::id", wantErr: true},
+		{given: "proj:dashboard:", wantErr: true},
+		{given: "proj:dashboard:[", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.given, func(t *testing.T) {
+			got, err := ParsePattern(tt.given)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.given, got.String())
+		})
+	}
+}
+
+func TestPattern_Matches(t *testing.T) {
+	p := Pattern{Project: "proj", Type: "dashboard", ConfigId: "team-*"}
+
+	assert.True(t, p.Matches(Coordinate{Project: "proj", Type: "dashboard", ConfigId: "team-a"}))
+	assert.False(t, p.Matches(Coordinate{Project: "proj", Type: "dashboard", ConfigId: "other"}))
+	assert.False(t, p.Matches(Coordinate{Project: "other", Type: "dashboard", ConfigId: "team-a"}))
+	assert.False(t, p.Matches(Coordinate{Project: "proj", Type: "auto-tag", ConfigId: "team-a"}))
+}
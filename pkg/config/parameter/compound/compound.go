@@ -20,7 +20,6 @@ import (
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/strings"
 	template2 "github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/template"
 	"github.com/google/go-cmp/cmp"
-	templ "text/template" // nosemgrep: go.lang.security.audit.xss.import-text-template.import-text-template
 
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/coordinate"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/parameter"
@@ -36,19 +35,18 @@ var CompoundParameterSerde = parameter.ParameterSerDe{
 }
 
 type CompoundParameter struct {
-	format               *templ.Template
+	// rawFormatString is parsed when resolving the value, as parsed templates holding template functions can not be
+	// compared
 	rawFormatString      string
 	referencedParameters []parameter.ParameterReference
 }
 
 func New(name string, format string, referencedParameters []parameter.ParameterReference) (*CompoundParameter, error) {
-	formatTempl, err := template.ParseTemplate(name, format)
-	if err != nil {
+	if _, err := template.ParseTemplate(name, format); err != nil {
 		return &CompoundParameter{}, err
 	}
 
 	return &CompoundParameter{
-		format:               formatTempl,
 		rawFormatString:      format,
 		referencedParameters: referencedParameters,
 	}, nil
@@ -56,6 +54,7 @@ func New(name string, format string, referencedParameters []parameter.ParameterR
 
 // this forces the compiler to check if CompoundParameter is of type Parameter
 var _ parameter.Parameter = (*CompoundParameter)(nil)
+var _ parameter.RawValueResolver = (*CompoundParameter)(nil)
 
 func (p *CompoundParameter) GetType() string {
 	return CompoundParameterType
@@ -66,22 +65,33 @@ func (p *CompoundParameter) GetReferences() []parameter.ParameterReference {
 }
 
 func (p *CompoundParameter) ResolveValue(context parameter.ResolveContext) (interface{}, error) {
+	val, err := p.ResolveRawValue(context)
+	if err != nil {
+		return nil, err
+	}
+	return template2.EscapeSpecialCharactersInValue(val, template2.FullStringEscapeFunction)
+}
+
+func (p *CompoundParameter) ResolveRawValue(context parameter.ResolveContext) (interface{}, error) {
 	compoundData := make(map[string]interface{})
 
 	for _, param := range p.referencedParameters {
 		compoundData[param.Property] = context.ResolvedParameterValues[param.Property]
 	}
 
+	format, err := template.ParseTemplate(context.ParameterName, p.rawFormatString)
+	if err != nil {
+		return nil, fmt.Errorf("error resolving compound value: %w", err)
+	}
+
 	out := bytes.Buffer{}
-	err := p.format.Execute(&out, compoundData)
+	err = format.Execute(&out, compoundData)
 
 	if err != nil {
 		return nil, fmt.Errorf("error resolving compound value: %w", err)
 	}
 
-	str := out.String()
-	return template2.EscapeSpecialCharactersInValue(str, template2.FullStringEscapeFunction)
-
+	return out.String(), nil
 }
 
 // Equal is required to compare two CompoundParameter without opening all fields.
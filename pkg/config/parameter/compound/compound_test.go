@@ -224,3 +224,20 @@ func TestWriteCompoundParameterErrorOnMissingReferences(t *testing.T) {
 	_, err = writeCompoundParameter(context)
 	require.Error(t, err, "expected an error writing missing references")
 }
+
+func TestResolveValueWithFunctions(t *testing.T) {
+	context := parameter.ResolveContext{
+		ResolvedParameterValues: parameter.Properties{
+			"name": "My Dashboard",
+		},
+	}
+	compoundParameter, err := New("testName", `{{ .name | lower | replace " " "-" }}`, []parameter.ParameterReference{
+		{Property: "name"},
+	})
+	require.NoError(t, err)
+
+	result, err := compoundParameter.ResolveValue(context)
+	require.NoError(t, err)
+
+	assert.Equal(t, "my-dashboard", strings.ToString(result))
+}
@@ -0,0 +1,167 @@
+// @license
+// Copyright 2024 Dynatrace LLC
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package parameter
+
+import (
+	"fmt"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/strings"
+	"math"
+	"regexp"
+	"slices"
+	"strconv"
+)
+
+const (
+	ConstraintTypeInt    = "int"
+	ConstraintTypeBool   = "bool"
+	ConstraintTypeString = "string"
+)
+
+// Constraints restrict the values a parameter may resolve to. They are checked once the parameter is resolved.
+type Constraints struct {
+	// Type of the value, one of ConstraintTypeInt, ConstraintTypeBool and ConstraintTypeString. Strings are accepted
+	// as int and bool values if they can be parsed as such, e.g. values of environment variables. Empty means any type.
+	Type string
+
+	// Pattern is a regular expression the whole value must match
+	Pattern string
+
+	// Enum lists all allowed values. Empty means any value.
+	Enum []string
+
+	// Min and Max are the inclusive bounds of numeric values. Nil means unbounded.
+	Min *float64
+	Max *float64
+
+	// Required states that the value must not be empty. Other constraints are not checked for empty values.
+	Required bool
+}
+
+// ParseConstraints parses Constraints from the given map, e.g. the `constraints` of a parameter
+func ParseConstraints(value map[string]interface{}) (Constraints, error) {
+	var c Constraints
+
+	for k, v := range value {
+		switch k {
+		case "type":
+			c.Type = strings.ToString(v)
+			if !slices.Contains([]string{ConstraintTypeInt, ConstraintTypeBool, ConstraintTypeString}, c.Type) {
+				return Constraints{}, fmt.Errorf("invalid constraint type %q, must be one of %q, %q or %q", c.Type, ConstraintTypeInt, ConstraintTypeBool, ConstraintTypeString)
+			}
+		case "pattern":
+			c.Pattern = strings.ToString(v)
+			if _, err := regexp.Compile(c.Pattern); err != nil {
+				return Constraints{}, fmt.Errorf("invalid constraint pattern %q: %w", c.Pattern, err)
+			}
+		case "enum":
+			values, ok := v.([]interface{})
+			if !ok {
+				return Constraints{}, fmt.Errorf("constraint `enum` must be a list of values")
+			}
+			for _, e := range values {
+				c.Enum = append(c.Enum, strings.ToString(e))
+			}
+		case "min", "max":
+			n, err := toNumber(v)
+			if err != nil {
+				return Constraints{}, fmt.Errorf("constraint `%s` must be a number", k)
+			}
+			if k == "min" {
+				c.Min = &n
+			} else {
+				c.Max = &n
+			}
+		case "required":
+			b, ok := v.(bool)
+			if !ok {
+				return Constraints{}, fmt.Errorf("constraint `required` must be true or false")
+			}
+			c.Required = b
+		default:
+			return Constraints{}, fmt.Errorf("unknown constraint `%s`", k)
+		}
+	}
+
+	return c, nil
+}
+
+// Check returns an error describing the offending value if it violates the constraints
+func (c Constraints) Check(value interface{}) error {
+	if value == nil || value == "" {
+		if c.Required {
+			return fmt.Errorf("value is required, but empty")
+		}
+		return nil
+	}
+
+	s := strings.ToString(value)
+
+	switch c.Type {
+	case ConstraintTypeInt:
+		if n, err := toNumber(value); err != nil || n != math.Trunc(n) {
+			return fmt.Errorf("value %q is not an int", s)
+		}
+	case ConstraintTypeBool:
+		if _, isBool := value.(bool); !isBool {
+			if _, err := strconv.ParseBool(s); err != nil {
+				return fmt.Errorf("value %q is not a bool", s)
+			}
+		}
+	case ConstraintTypeString:
+		if _, isString := value.(string); !isString {
+			return fmt.Errorf("value %q is not a string", s)
+		}
+	}
+
+	if c.Pattern != "" && !regexp.MustCompile(`^(?:`+c.Pattern+`)$`).MatchString(s) {
+		return fmt.Errorf("value %q does not match pattern %q", s, c.Pattern)
+	}
+
+	if len(c.Enum) > 0 && !slices.Contains(c.Enum, s) {
+		return fmt.Errorf("value %q is not one of %q", s, c.Enum)
+	}
+
+	if c.Min != nil || c.Max != nil {
+		n, err := toNumber(value)
+		if err != nil {
+			return fmt.Errorf("value %q is not a number", s)
+		}
+		if c.Min != nil && n < *c.Min {
+			return fmt.Errorf("value %q is less than the minimum %v", s, *c.Min)
+		}
+		if c.Max != nil && n > *c.Max {
+			return fmt.Errorf("value %q is greater than the maximum %v", s, *c.Max)
+		}
+	}
+
+	return nil
+}
+
+func toNumber(v interface{}) (float64, error) {
+	switch n := v.(type) {
+	case int:
+		return float64(n), nil
+	case int64:
+		return float64(n), nil
+	case uint64:
+		return float64(n), nil
+	case float64:
+		return n, nil
+	case string:
+		return strconv.ParseFloat(n, 64)
+	default:
+		return 0, fmt.Errorf("%v is not a number", v)
+	}
+}
@@ -0,0 +1,111 @@
+//go:build unit
+
+// @license
+// Copyright 2024 Dynatrace LLC
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package parameter
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestParseConstraints(t *testing.T) {
+	min, max := 1.0, 100.0
+
+	c, err := ParseConstraints(map[string]interface{}{
+		"type":     "int",
+		"pattern":  "[0-9]+",
+		"enum":     []interface{}{1, "2", 3},
+		"min":      1,
+		"max":      100.0,
+		"required": true,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, Constraints{
+		Type:     ConstraintTypeInt,
+		Pattern:  "[0-9]+",
+		Enum:     []string{"1", "2", "3"},
+		Min:      &min,
+		Max:      &max,
+		Required: true,
+	}, c)
+}
+
+func TestParseConstraints_Errors(t *testing.T) {
+	tests := []struct {
+		name  string
+		value map[string]interface{}
+		want  string
+	}{
+		{"unknown type", map[string]interface{}{"type": "float"}, `invalid constraint type "float"`},
+		{"invalid pattern", map[string]interface{}{"pattern": "[a-"}, `invalid constraint pattern "[a-"`},
+		{"enum is no list", map[string]interface{}{"enum": "a"}, "constraint `enum` must be a list of values"},
+		{"min is no number", map[string]interface{}{"min": "one"}, "constraint `min` must be a number"},
+		{"required is no bool", map[string]interface{}{"required": "yes"}, "constraint `required` must be true or false"},
+		{"unknown constraint", map[string]interface{}{"maxLength": 3}, "unknown constraint `maxLength`"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseConstraints(tt.value)
+			assert.ErrorContains(t, err, tt.want)
+		})
+	}
+}
+
+func TestConstraints_Check(t *testing.T) {
+	one, ten := 1.0, 10.0
+
+	tests := []struct {
+		name        string
+		constraints Constraints
+		value       interface{}
+		wantErr     string
+	}{
+		{"no constraints", Constraints{}, "anything", ""},
+		{"required value set", Constraints{Required: true}, "value", ""},
+		{"required value empty", Constraints{Required: true}, "", "value is required, but empty"},
+		{"required value nil", Constraints{Required: true}, nil, "value is required, but empty"},
+		{"optional empty value is not checked", Constraints{Type: ConstraintTypeInt, Min: &one}, "", ""},
+		{"int", Constraints{Type: ConstraintTypeInt}, 42, ""},
+		{"int string", Constraints{Type: ConstraintTypeInt}, "42", ""},
+		{"not an int", Constraints{Type: ConstraintTypeInt}, "4.2", `value "4.2" is not an int`},
+		{"bool", Constraints{Type: ConstraintTypeBool}, true, ""},
+		{"bool string", Constraints{Type: ConstraintTypeBool}, "false", ""},
+		{"not a bool", Constraints{Type: ConstraintTypeBool}, "yes", `value "yes" is not a bool`},
+		{"string", Constraints{Type: ConstraintTypeString}, "text", ""},
+		{"not a string", Constraints{Type: ConstraintTypeString}, 42, `value "42" is not a string`},
+		{"pattern matches", Constraints{Pattern: "[a-z]+-[0-9]+"}, "team-42", ""},
+		{"pattern must match whole value", Constraints{Pattern: "[a-z]+"}, "team-42", `value "team-42" does not match pattern "[a-z]+"`},
+		{"enum", Constraints{Enum: []string{"dev", "prod"}}, "prod", ""},
+		{"not in enum", Constraints{Enum: []string{"dev", "prod"}}, "stage", `value "stage" is not one of ["dev" "prod"]`},
+		{"within bounds", Constraints{Min: &one, Max: &ten}, "10", ""},
+		{"less than min", Constraints{Min: &one, Max: &ten}, 0, `value "0" is less than the minimum 1`},
+		{"greater than max", Constraints{Min: &one, Max: &ten}, "11", `value "11" is greater than the maximum 10`},
+		{"bounds of no number", Constraints{Max: &ten}, "ten", `value "ten" is not a number`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.constraints.Check(tt.value)
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tt.wantErr)
+			}
+		})
+	}
+}
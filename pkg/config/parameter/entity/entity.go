@@ -0,0 +1,140 @@
+// @license
+// Copyright 2024 Dynatrace LLC
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package entity
+
+import (
+	"fmt"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/log"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/log/field"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/strings"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/parameter"
+	"slices"
+)
+
+// EntityParameterType specifies the type of the parameter used in config files
+const EntityParameterType = "entity"
+
+const (
+	// OnMismatchFail fails resolving the parameter if no or multiple entities match the selector
+	OnMismatchFail = "fail"
+	// OnMismatchWarn logs a warning if no or multiple entities match the selector. The parameter resolves to an empty
+	// value if no entity matches, and to the first entity ID in lexical order if multiple entities match.
+	OnMismatchWarn = "warn"
+)
+
+var EntityParameterSerde = parameter.ParameterSerDe{
+	Serializer:   writeEntityParameter,
+	Deserializer: parseEntityParameter,
+}
+
+// EntityParameter resolves to the ID of the monitored entity matching an entity selector, e.g.
+// `type(HOST_GROUP),entityName("payments")`, in the environment the config is deployed to.
+type EntityParameter struct {
+	// Selector is the entity selector the entity is looked up with
+	Selector string
+
+	// OnMismatch defines what happens if no or multiple entities match the selector, either OnMismatchFail or
+	// OnMismatchWarn
+	OnMismatch string
+}
+
+// this forces the compiler to check if EntityParameter is of type Parameter
+var _ parameter.Parameter = (*EntityParameter)(nil)
+
+func New(selector string) *EntityParameter {
+	return &EntityParameter{
+		Selector:   selector,
+		OnMismatch: OnMismatchFail,
+	}
+}
+
+func (p *EntityParameter) GetType() string {
+	return EntityParameterType
+}
+
+func (p *EntityParameter) GetReferences() []parameter.ParameterReference {
+	// entities are looked up in the environment and never reference other configs
+	return []parameter.ParameterReference{}
+}
+
+func (p *EntityParameter) ResolveValue(context parameter.ResolveContext) (interface{}, error) {
+	if context.EntitySelectorResolver == nil {
+		return nil, parameter.NewParameterResolveValueError(context, "monitored entities can not be looked up")
+	}
+
+	ids, err := context.EntitySelectorResolver.ResolveEntitySelector(p.Selector)
+	if err != nil {
+		return nil, parameter.NewParameterResolveValueError(context, fmt.Sprintf("failed to look up entities matching `%s`: %s", p.Selector, err))
+	}
+
+	if len(ids) == 1 {
+		return ids[0], nil
+	}
+
+	var msg string
+	if len(ids) == 0 {
+		msg = fmt.Sprintf("no entity matches `%s`", p.Selector)
+	} else {
+		msg = fmt.Sprintf("%d entities match `%s`: %v", len(ids), p.Selector, ids)
+	}
+
+	if p.OnMismatch != OnMismatchWarn {
+		return nil, parameter.NewParameterResolveValueError(context, msg)
+	}
+
+	log.WithFields(field.Coordinate(context.ConfigCoordinate), field.Environment(context.Environment, context.Group)).Warn("Parameter %q of config %s: %s", context.ParameterName, context.ConfigCoordinate, msg)
+	if len(ids) == 0 {
+		return "", nil
+	}
+	return slices.Min(ids), nil
+}
+
+// parseEntityParameter parses an EntityParameter from a given context.
+// it requires a `selector` field to be set. `onMismatch` is an optional field.
+func parseEntityParameter(context parameter.ParameterParserContext) (parameter.Parameter, error) {
+	selector, ok := context.Value["selector"]
+	if !ok {
+		return nil, parameter.NewParameterParserError(context, "missing property `selector`")
+	}
+
+	p := New(strings.ToString(selector))
+
+	if onMismatch, ok := context.Value["onMismatch"]; ok {
+		p.OnMismatch = strings.ToString(onMismatch)
+		if p.OnMismatch != OnMismatchFail && p.OnMismatch != OnMismatchWarn {
+			return nil, parameter.NewParameterParserError(context, fmt.Sprintf("invalid value of `onMismatch` %q, must be %q or %q", p.OnMismatch, OnMismatchFail, OnMismatchWarn))
+		}
+	}
+
+	return p, nil
+}
+
+func writeEntityParameter(context parameter.ParameterWriterContext) (map[string]interface{}, error) {
+	entityParam, ok := context.Parameter.(*EntityParameter)
+
+	if !ok {
+		return nil, parameter.NewParameterWriterError(context, "unexpected type. parameter is not of type `EntityParameter`")
+	}
+
+	result := map[string]interface{}{
+		"selector": entityParam.Selector,
+	}
+
+	if entityParam.OnMismatch != OnMismatchFail {
+		result["onMismatch"] = entityParam.OnMismatch
+	}
+
+	return result, nil
+}
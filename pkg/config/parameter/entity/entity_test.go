@@ -0,0 +1,121 @@
+//go:build unit
+
+// @license
+// Copyright 2024 Dynatrace LLC
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package entity
+
+import (
+	"errors"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/coordinate"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/parameter"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+type selectorResolver map[string][]string
+
+func (r selectorResolver) ResolveEntitySelector(entitySelector string) ([]string, error) {
+	ids, found := r[entitySelector]
+	if !found {
+		return nil, errors.New("invalid entity selector")
+	}
+	return ids, nil
+}
+
+func TestParseEntityParameter(t *testing.T) {
+	param, err := parseEntityParameter(parameter.ParameterParserContext{
+		Value: map[string]interface{}{
+			"selector": `type(HOST_GROUP),entityName("payments")`,
+		},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, &EntityParameter{Selector: `type(HOST_GROUP),entityName("payments")`, OnMismatch: OnMismatchFail}, param)
+
+	param, err = parseEntityParameter(parameter.ParameterParserContext{
+		Value: map[string]interface{}{
+			"selector":   "type(SERVICE)",
+			"onMismatch": "warn",
+		},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, &EntityParameter{Selector: "type(SERVICE)", OnMismatch: OnMismatchWarn}, param)
+}
+
+func TestParseEntityParameter_Errors(t *testing.T) {
+	_, err := parseEntityParameter(parameter.ParameterParserContext{
+		Value: map[string]interface{}{},
+	})
+	assert.ErrorContains(t, err, "missing property `selector`")
+
+	_, err = parseEntityParameter(parameter.ParameterParserContext{
+		Value: map[string]interface{}{"selector": "type(SERVICE)", "onMismatch": "ignore"},
+	})
+	assert.ErrorContains(t, err, `invalid value of `+"`onMismatch`"+` "ignore"`)
+}
+
+func TestWriteEntityParameter(t *testing.T) {
+	result, err := writeEntityParameter(parameter.ParameterWriterContext{Parameter: New("type(SERVICE)")})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"selector": "type(SERVICE)"}, result)
+
+	result, err = writeEntityParameter(parameter.ParameterWriterContext{Parameter: &EntityParameter{Selector: "type(SERVICE)", OnMismatch: OnMismatchWarn}})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"selector": "type(SERVICE)", "onMismatch": "warn"}, result)
+}
+
+func TestResolveEntityParameter(t *testing.T) {
+	resolver := selectorResolver{
+		"single":   {"HOST_GROUP-1"},
+		"none":     {},
+		"multiple": {"SERVICE-2", "SERVICE-1"},
+	}
+
+	tests := []struct {
+		name       string
+		selector   string
+		onMismatch string
+		want       any
+		wantErr    string
+	}{
+		{"single entity", "single", OnMismatchFail, "HOST_GROUP-1", ""},
+		{"no entity fails", "none", OnMismatchFail, nil, "no entity matches `none`"},
+		{"multiple entities fail", "multiple", OnMismatchFail, nil, "2 entities match `multiple`"},
+		{"no entity warns", "none", OnMismatchWarn, "", ""},
+		{"multiple entities warn", "multiple", OnMismatchWarn, "SERVICE-1", ""},
+		{"lookup error", "invalid", OnMismatchWarn, nil, "failed to look up entities matching `invalid`: invalid entity selector"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			val, err := (&EntityParameter{Selector: tt.selector, OnMismatch: tt.onMismatch}).ResolveValue(parameter.ResolveContext{
+				EntitySelectorResolver: resolver,
+				ConfigCoordinate:       coordinate.Coordinate{Project: "p", Type: "builtin:alerting.profile", ConfigId: "c"},
+				ParameterName:          "scope",
+			})
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, val)
+		})
+	}
+}
+
+func TestResolveEntityParameter_WithoutResolver(t *testing.T) {
+	_, err := New("type(SERVICE)").ResolveValue(parameter.ResolveContext{})
+	assert.ErrorContains(t, err, "monitored entities can not be looked up")
+}
@@ -64,6 +64,7 @@ func NewWithDefault(name string, defaultValue string) *EnvironmentVariableParame
 
 // this forces the compiler to check if EnvironmentVariableParameter is of type Parameter
 var _ parameter.Parameter = (*EnvironmentVariableParameter)(nil)
+var _ parameter.RawValueResolver = (*EnvironmentVariableParameter)(nil)
 
 func (p *EnvironmentVariableParameter) GetType() string {
 	return EnvironmentVariableParameterType
@@ -75,7 +76,14 @@ func (p *EnvironmentVariableParameter) GetReferences() []parameter.ParameterRefe
 }
 
 func (p *EnvironmentVariableParameter) ResolveValue(context parameter.ResolveContext) (interface{}, error) {
+	val, err := p.ResolveRawValue(context)
+	if err != nil {
+		return nil, err
+	}
+	return template.EscapeSpecialCharactersInValue(val, template.FullStringEscapeFunction)
+}
 
+func (p *EnvironmentVariableParameter) ResolveRawValue(context parameter.ResolveContext) (interface{}, error) {
 	val, found := os.LookupEnv(p.Name)
 	if !found && p.HasDefaultValue {
 		val = p.DefaultValue
@@ -83,7 +91,7 @@ func (p *EnvironmentVariableParameter) ResolveValue(context parameter.ResolveCon
 		return nil, parameter.NewParameterResolveValueError(context, fmt.Sprintf("environment variable `%s` not set", p.Name))
 	}
 
-	return template.EscapeSpecialCharactersInValue(val, template.FullStringEscapeFunction)
+	return val, nil
 }
 
 // parseEnvironmentValueParameter parses an EnvironmentVariableParameter from a given context.
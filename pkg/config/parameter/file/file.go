@@ -0,0 +1,130 @@
+// @license
+// Copyright 2024 Dynatrace LLC
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package file
+
+import (
+	"fmt"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/strings"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/template"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/parameter"
+	"github.com/spf13/afero"
+	"path/filepath"
+	"strconv"
+)
+
+// FileParameterType specifies the type of the parameter used in config files
+const FileParameterType = "file"
+
+var FileParameterSerde = parameter.ParameterSerDe{
+	Serializer:   writeFileParameter,
+	Deserializer: parseFileParameter,
+}
+
+// FileParameter defines a parameter whose value is the content of a file. The file is read when the config is loaded.
+// By default, the content is escaped, so that it can be used within a JSON string.
+type FileParameter struct {
+	// Path of the file, relative to the folder of the config file the parameter is defined in
+	Path string
+
+	// Content of the file
+	Content string
+
+	// Escape states whether the content is escaped for use within a JSON string
+	Escape bool
+}
+
+// New returns a FileParameter with the given content, stored in the file at the given path. The content is escaped.
+func New(path string, content string) *FileParameter {
+	return &FileParameter{
+		Path:    path,
+		Content: content,
+		Escape:  true,
+	}
+}
+
+// this forces the compiler to check if FileParameter is of type Parameter
+var _ parameter.Parameter = (*FileParameter)(nil)
+var _ parameter.RawValueResolver = (*FileParameter)(nil)
+
+func (p *FileParameter) GetType() string {
+	return FileParameterType
+}
+
+func (p *FileParameter) GetReferences() []parameter.ParameterReference {
+	// file parameters cannot have references
+	return []parameter.ParameterReference{}
+}
+
+func (p *FileParameter) ResolveValue(_ parameter.ResolveContext) (interface{}, error) {
+	if !p.Escape {
+		return p.Content, nil
+	}
+	return template.EscapeSpecialCharactersInValue(p.Content, template.FullStringEscapeFunction)
+}
+
+func (p *FileParameter) ResolveRawValue(_ parameter.ResolveContext) (interface{}, error) {
+	return p.Content, nil
+}
+
+// parseFileParameter parses a FileParameter from a given context and reads the file it references.
+// it requires a `path` field to be set. `escape` is an optional field, defaulting to true.
+func parseFileParameter(context parameter.ParameterParserContext) (parameter.Parameter, error) {
+	path, ok := context.Value["path"]
+	if !ok {
+		return nil, parameter.NewParameterParserError(context, "missing property `path`")
+	}
+
+	escape := true
+	if val, ok := context.Value["escape"]; ok {
+		b, err := strconv.ParseBool(strings.ToString(val))
+		if err != nil {
+			return nil, parameter.NewParameterParserError(context, fmt.Sprintf("property `escape` must be 'true' or 'false' (current value is: '%v')", val))
+		}
+		escape = b
+	}
+
+	if context.Fs == nil {
+		return nil, parameter.NewParameterParserError(context, "files can not be read in this context")
+	}
+
+	p := filepath.FromSlash(strings.ToString(path))
+	content, err := afero.ReadFile(context.Fs, filepath.Join(context.Folder, p))
+	if err != nil {
+		return nil, parameter.NewParameterParserError(context, fmt.Sprintf("failed to read file %q: %s", p, err))
+	}
+
+	return &FileParameter{
+		Path:    p,
+		Content: string(content),
+		Escape:  escape,
+	}, nil
+}
+
+func writeFileParameter(context parameter.ParameterWriterContext) (map[string]interface{}, error) {
+	fileParam, ok := context.Parameter.(*FileParameter)
+
+	if !ok {
+		return nil, parameter.NewParameterWriterError(context, "unexpected type. parameter is not of type `FileParameter`")
+	}
+
+	result := make(map[string]interface{})
+	result["path"] = filepath.ToSlash(fileParam.Path)
+
+	if !fileParam.Escape {
+		result["escape"] = false
+	}
+
+	return result, nil
+}
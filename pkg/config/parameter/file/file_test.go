@@ -0,0 +1,97 @@
+//go:build unit
+
+// @license
+// Copyright 2024 Dynatrace LLC
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package file
+
+import (
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/parameter"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"path/filepath"
+	"testing"
+)
+
+func testFs(t *testing.T) afero.Fs {
+	fs := afero.NewMemMapFs()
+	require.NoError(t, afero.WriteFile(fs, filepath.Join("project", "workflow", "scripts", "task.js"), []byte("console.log(\"hello\");\n"), 0644))
+	return fs
+}
+
+func TestParseFileParameter(t *testing.T) {
+	param, err := parseFileParameter(parameter.ParameterParserContext{
+		Fs:     testFs(t),
+		Folder: filepath.Join("project", "workflow"),
+		Value: map[string]interface{}{
+			"path": "scripts/task.js",
+		},
+	})
+	require.NoError(t, err)
+
+	fileParameter, ok := param.(*FileParameter)
+	require.True(t, ok, "parsed parameter should be file parameter")
+	assert.Equal(t, "file", fileParameter.GetType())
+	assert.Equal(t, filepath.FromSlash("scripts/task.js"), fileParameter.Path)
+	assert.Equal(t, "console.log(\"hello\");\n", fileParameter.Content)
+	assert.True(t, fileParameter.Escape, "content should be escaped by default")
+}
+
+func TestParseFileParameter_Errors(t *testing.T) {
+	tests := []struct {
+		name  string
+		value map[string]interface{}
+		want  string
+	}{
+		{"missing path", map[string]interface{}{}, "missing property `path`"},
+		{"missing file", map[string]interface{}{"path": "scripts/missing.js"}, "failed to read file"},
+		{"invalid escape", map[string]interface{}{"path": "scripts/task.js", "escape": "maybe"}, "property `escape` must be 'true' or 'false'"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := parseFileParameter(parameter.ParameterParserContext{
+				Fs:     testFs(t),
+				Folder: filepath.Join("project", "workflow"),
+				Value:  tt.value,
+			})
+			assert.ErrorContains(t, err, tt.want)
+		})
+	}
+}
+
+func TestResolveFileParameter(t *testing.T) {
+	escaped, err := New("task.js", "console.log(\"hello\");\n").ResolveValue(parameter.ResolveContext{})
+	require.NoError(t, err)
+	assert.Equal(t, `console.log(\"hello\");\n`, escaped)
+
+	p := New("fragment.json", `{"key": "value"}`)
+	p.Escape = false
+	raw, err := p.ResolveValue(parameter.ResolveContext{})
+	require.NoError(t, err)
+	assert.Equal(t, `{"key": "value"}`, raw)
+}
+
+func TestWriteFileParameter(t *testing.T) {
+	p := New(filepath.Join("scripts", "task.js"), "content")
+	result, err := writeFileParameter(parameter.ParameterWriterContext{Parameter: p})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"path": "scripts/task.js"}, result)
+
+	p.Escape = false
+	result, err = writeFileParameter(parameter.ParameterWriterContext{Parameter: p})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"path": "scripts/task.js", "escape": false}, result)
+}
@@ -144,6 +144,9 @@ func parseSubParameter(paramValue interface{}, context parameter.ParameterParser
 		Environment:   context.Environment,
 		ParameterName: context.ParameterName,
 		Value:         subValue,
+		Fs:            context.Fs,
+		Folder:        context.Folder,
+		Variables:     context.Variables,
 	}
 	p, err := value.ValueParameterSerde.Deserializer(subContext)
 	if err != nil {
@@ -0,0 +1,231 @@
+// @license
+// Copyright 2024 Dynatrace LLC
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lookup
+
+import (
+	"fmt"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/maps"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/strings"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/template"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/parameter"
+	"slices"
+	gostrings "strings"
+)
+
+// LookupParameterType specifies the type of the parameter used in config files
+const LookupParameterType = "lookup"
+
+// IdProperty is the property holding the ID of an object. Settings objects additionally provide the properties
+// "scope", "externalId" and "schemaVersion", as well as all top-level fields of their value.
+const IdProperty = "id"
+
+var LookupParameterSerde = parameter.ParameterSerDe{
+	Serializer:   writeLookupParameter,
+	Deserializer: parseLookupParameter,
+}
+
+// LookupParameter resolves to a property of an existing object of the environment the config is deployed to, e.g. of
+// a built-in alerting profile or a management zone that is not managed by monaco. Exactly one object of the classic
+// API or settings schema must match all properties of Match.
+type LookupParameter struct {
+	// Api is the ID of the classic API of the object. Either Api or Schema is set.
+	Api string
+
+	// Schema is the ID of the settings schema of the object. Either Api or Schema is set.
+	Schema string
+
+	// Match maps properties of the object to the values they must have
+	Match map[string]string
+
+	// Property of the object the parameter resolves to
+	Property string
+}
+
+// this forces the compiler to check if LookupParameter is of type Parameter
+var _ parameter.Parameter = (*LookupParameter)(nil)
+var _ parameter.RawValueResolver = (*LookupParameter)(nil)
+
+func (p *LookupParameter) GetType() string {
+	return LookupParameterType
+}
+
+func (p *LookupParameter) GetReferences() []parameter.ParameterReference {
+	// looked up objects are not managed by monaco and never reference other configs
+	return []parameter.ParameterReference{}
+}
+
+func (p *LookupParameter) ResolveValue(context parameter.ResolveContext) (interface{}, error) {
+	val, err := p.ResolveRawValue(context)
+	if err != nil {
+		return nil, err
+	}
+	return template.EscapeSpecialCharactersInValue(val, template.FullStringEscapeFunction)
+}
+
+func (p *LookupParameter) ResolveRawValue(context parameter.ResolveContext) (interface{}, error) {
+	if context.ObjectLookup == nil {
+		return nil, parameter.NewParameterResolveValueError(context, "existing objects can not be looked up")
+	}
+
+	var objects []parameter.Properties
+	var err error
+	if p.Api != "" {
+		objects, err = context.ObjectLookup.ListClassicObjects(p.Api)
+	} else {
+		objects, err = context.ObjectLookup.ListSettingsObjects(p.Schema)
+	}
+	if err != nil {
+		return nil, parameter.NewParameterResolveValueError(context, fmt.Sprintf("failed to look up objects of %s: %s", p.target(), err))
+	}
+
+	var matches []parameter.Properties
+	for _, o := range objects {
+		if p.matches(o) {
+			matches = append(matches, o)
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		return nil, parameter.NewParameterResolveValueError(context, fmt.Sprintf("no object of %s matches %s", p.target(), p.matchString()))
+	case 1:
+	default:
+		ids := make([]string, len(matches))
+		for i, m := range matches {
+			ids[i] = strings.ToString(m[IdProperty])
+		}
+		return nil, parameter.NewParameterResolveValueError(context, fmt.Sprintf("%d objects of %s match %s: %v", len(matches), p.target(), p.matchString(), ids))
+	}
+
+	val, found := matches[0][p.Property]
+	if !found {
+		return nil, parameter.NewParameterResolveValueError(context, fmt.Sprintf("object %q of %s has no property `%s`", strings.ToString(matches[0][IdProperty]), p.target(), p.Property))
+	}
+
+	return val, nil
+}
+
+func (p *LookupParameter) matches(object parameter.Properties) bool {
+	for k, v := range p.Match {
+		actual, found := object[k]
+		if !found || strings.ToString(actual) != v {
+			return false
+		}
+	}
+	return true
+}
+
+func (p *LookupParameter) target() string {
+	if p.Api != "" {
+		return fmt.Sprintf("API %q", p.Api)
+	}
+	return fmt.Sprintf("schema %q", p.Schema)
+}
+
+func (p *LookupParameter) matchString() string {
+	keys := make([]string, 0, len(p.Match))
+	for k := range p.Match {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+
+	conditions := make([]string, len(keys))
+	for i, k := range keys {
+		conditions[i] = fmt.Sprintf("%s=%q", k, p.Match[k])
+	}
+	return gostrings.Join(conditions, ", ")
+}
+
+// parseLookupParameter parses a LookupParameter from a given context.
+// it requires exactly one of the fields `api` and `schema`, and the properties to match with `name` and/or `match`.
+// `property` is an optional field, defaulting to the ID of the object.
+func parseLookupParameter(context parameter.ParameterParserContext) (parameter.Parameter, error) {
+	p := &LookupParameter{
+		Match:    make(map[string]string),
+		Property: IdProperty,
+	}
+
+	if api, found := context.Value["api"]; found {
+		p.Api = strings.ToString(api)
+	}
+	if schema, found := context.Value["schema"]; found {
+		p.Schema = strings.ToString(schema)
+	}
+
+	if (p.Api == "") == (p.Schema == "") {
+		return nil, parameter.NewParameterParserError(context, "exactly one of the properties `api` and `schema` must be set")
+	}
+
+	if match, found := context.Value["match"]; found {
+		var m map[string]interface{}
+		switch v := match.(type) {
+		case map[interface{}]interface{}:
+			m = maps.ToStringMap(v)
+		case map[string]interface{}:
+			m = v
+		default:
+			return nil, parameter.NewParameterParserError(context, "property `match` must map properties to values")
+		}
+		for k, v := range m {
+			p.Match[k] = strings.ToString(v)
+		}
+	}
+
+	if name, found := context.Value["name"]; found {
+		p.Match["name"] = strings.ToString(name)
+	}
+
+	if len(p.Match) == 0 {
+		return nil, parameter.NewParameterParserError(context, "missing property `name` or `match`")
+	}
+
+	if property, found := context.Value["property"]; found {
+		p.Property = strings.ToString(property)
+	}
+
+	return p, nil
+}
+
+func writeLookupParameter(context parameter.ParameterWriterContext) (map[string]interface{}, error) {
+	lookupParam, ok := context.Parameter.(*LookupParameter)
+
+	if !ok {
+		return nil, parameter.NewParameterWriterError(context, "unexpected type. parameter is not of type `LookupParameter`")
+	}
+
+	result := make(map[string]interface{})
+
+	if lookupParam.Api != "" {
+		result["api"] = lookupParam.Api
+	} else {
+		result["schema"] = lookupParam.Schema
+	}
+
+	if name, found := lookupParam.Match["name"]; found && len(lookupParam.Match) == 1 {
+		result["name"] = name
+	} else {
+		match := make(map[string]interface{}, len(lookupParam.Match))
+		for k, v := range lookupParam.Match {
+			match[k] = v
+		}
+		result["match"] = match
+	}
+
+	if lookupParam.Property != IdProperty {
+		result["property"] = lookupParam.Property
+	}
+
+	return result, nil
+}
@@ -0,0 +1,189 @@
+//go:build unit
+
+// @license
+// Copyright 2024 Dynatrace LLC
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lookup
+
+import (
+	"errors"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/parameter"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+type objectLookup struct {
+	classic  map[string][]parameter.Properties
+	settings map[string][]parameter.Properties
+}
+
+func (l objectLookup) ListClassicObjects(apiID string) ([]parameter.Properties, error) {
+	if objects, found := l.classic[apiID]; found {
+		return objects, nil
+	}
+	return nil, errors.New("unknown API")
+}
+
+func (l objectLookup) ListSettingsObjects(schemaID string) ([]parameter.Properties, error) {
+	return l.settings[schemaID], nil
+}
+
+func TestParseLookupParameter(t *testing.T) {
+	tests := []struct {
+		name  string
+		value map[string]interface{}
+		want  *LookupParameter
+	}{
+		{
+			"classic object by name",
+			map[string]interface{}{"api": "alerting-profile", "name": "Default"},
+			&LookupParameter{Api: "alerting-profile", Match: map[string]string{"name": "Default"}, Property: IdProperty},
+		},
+		{
+			"settings object by fields",
+			map[string]interface{}{
+				"schema":   "builtin:management-zones",
+				"match":    map[interface{}]interface{}{"name": "Payments", "description": "owned by team payments"},
+				"property": "description",
+			},
+			&LookupParameter{Schema: "builtin:management-zones", Match: map[string]string{"name": "Payments", "description": "owned by team payments"}, Property: "description"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			param, err := parseLookupParameter(parameter.ParameterParserContext{Value: tt.value})
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, param)
+		})
+	}
+}
+
+func TestParseLookupParameter_Errors(t *testing.T) {
+	tests := []struct {
+		name  string
+		value map[string]interface{}
+		want  string
+	}{
+		{"neither api nor schema", map[string]interface{}{"name": "Default"}, "exactly one of the properties `api` and `schema` must be set"},
+		{"api and schema", map[string]interface{}{"api": "alerting-profile", "schema": "builtin:alerting.profile", "name": "Default"}, "exactly one of the properties `api` and `schema` must be set"},
+		{"nothing to match", map[string]interface{}{"api": "alerting-profile"}, "missing property `name` or `match`"},
+		{"invalid match", map[string]interface{}{"api": "alerting-profile", "match": "Default"}, "property `match` must map properties to values"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := parseLookupParameter(parameter.ParameterParserContext{Value: tt.value})
+			assert.ErrorContains(t, err, tt.want)
+		})
+	}
+}
+
+func TestWriteLookupParameter(t *testing.T) {
+	result, err := writeLookupParameter(parameter.ParameterWriterContext{
+		Parameter: &LookupParameter{Api: "alerting-profile", Match: map[string]string{"name": "Default"}, Property: IdProperty},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"api": "alerting-profile", "name": "Default"}, result)
+
+	result, err = writeLookupParameter(parameter.ParameterWriterContext{
+		Parameter: &LookupParameter{Schema: "builtin:management-zones", Match: map[string]string{"name": "Payments", "scope": "environment"}, Property: "description"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{
+		"schema":   "builtin:management-zones",
+		"match":    map[string]interface{}{"name": "Payments", "scope": "environment"},
+		"property": "description",
+	}, result)
+}
+
+func TestResolveLookupParameter(t *testing.T) {
+	lookup := objectLookup{
+		classic: map[string][]parameter.Properties{
+			"alerting-profile": {
+				{IdProperty: "profile-1", "name": "Default"},
+				{IdProperty: "profile-2", "name": "Duplicate"},
+				{IdProperty: "profile-3", "name": "Duplicate"},
+			},
+		},
+		settings: map[string][]parameter.Properties{
+			"builtin:management-zones": {
+				{IdProperty: "zone-1", "scope": "environment", "name": "Payments", "description": `owned by "payments"`},
+				{IdProperty: "zone-2", "scope": "environment", "name": "Checkout"},
+			},
+		},
+	}
+
+	tests := []struct {
+		name    string
+		param   LookupParameter
+		want    any
+		wantErr string
+	}{
+		{
+			"classic object ID",
+			LookupParameter{Api: "alerting-profile", Match: map[string]string{"name": "Default"}, Property: IdProperty},
+			"profile-1",
+			"",
+		},
+		{
+			"settings object property is escaped",
+			LookupParameter{Schema: "builtin:management-zones", Match: map[string]string{"name": "Payments", "scope": "environment"}, Property: "description"},
+			`owned by \"payments\"`,
+			"",
+		},
+		{
+			"no matching object",
+			LookupParameter{Api: "alerting-profile", Match: map[string]string{"name": "Missing"}, Property: IdProperty},
+			nil,
+			`no object of API "alerting-profile" matches name="Missing"`,
+		},
+		{
+			"multiple matching objects",
+			LookupParameter{Api: "alerting-profile", Match: map[string]string{"name": "Duplicate"}, Property: IdProperty},
+			nil,
+			`2 objects of API "alerting-profile" match name="Duplicate": [profile-2 profile-3]`,
+		},
+		{
+			"missing property",
+			LookupParameter{Schema: "builtin:management-zones", Match: map[string]string{"name": "Checkout"}, Property: "description"},
+			nil,
+			"object \"zone-2\" of schema \"builtin:management-zones\" has no property `description`",
+		},
+		{
+			"lookup fails",
+			LookupParameter{Api: "unknown", Match: map[string]string{"name": "Default"}, Property: IdProperty},
+			nil,
+			`failed to look up objects of API "unknown": unknown API`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			val, err := tt.param.ResolveValue(parameter.ResolveContext{ObjectLookup: lookup, ParameterName: "param"})
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, val)
+		})
+	}
+}
+
+func TestResolveLookupParameter_WithoutLookup(t *testing.T) {
+	_, err := (&LookupParameter{Api: "alerting-profile", Match: map[string]string{"name": "Default"}, Property: IdProperty}).ResolveValue(parameter.ResolveContext{})
+	assert.ErrorContains(t, err, "existing objects can not be looked up")
+}
@@ -15,9 +15,11 @@
 package parameter
 
 import (
+	"context"
 	"fmt"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/coordinate"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/errors"
+	"github.com/spf13/afero"
 )
 
 // Properties defines a map representing resolved parameters
@@ -28,10 +30,35 @@ type PropertyResolver interface {
 	GetResolvedProperty(coordinate coordinate.Coordinate, propertyName string) (any, bool)
 }
 
+// EntitySelectorResolver is used in parameter resolution to fetch the IDs of the monitored entities of the environment
+// matching an entity selector
+type EntitySelectorResolver interface {
+	ResolveEntitySelector(entitySelector string) ([]string, error)
+}
+
+// ObjectLookup is used in parameter resolution to fetch the properties of the existing objects of the environment,
+// including objects not managed by monaco
+type ObjectLookup interface {
+	// ListClassicObjects returns the properties of all objects of the classic API with the given ID
+	ListClassicObjects(apiID string) ([]Properties, error)
+
+	// ListSettingsObjects returns the properties of all settings objects of the given schema
+	ListSettingsObjects(schemaID string) ([]Properties, error)
+}
+
 // ResolveContext used to give some more information on the resolving phase
 type ResolveContext struct {
+	// Context of the deployment, used by parameters making requests. Nil if parameters are resolved outside a deployment.
+	Context context.Context
+
 	PropertyResolver PropertyResolver
 
+	// resolves entity selectors, nil if monitored entities can not be looked up
+	EntitySelectorResolver EntitySelectorResolver
+
+	// looks up existing objects, nil if the objects of the environment can not be looked up
+	ObjectLookup ObjectLookup
+
 	// coordinates of the current config
 	ConfigCoordinate coordinate.Coordinate
 
@@ -63,6 +90,12 @@ type Parameter interface {
 	ResolveValue(context ResolveContext) (interface{}, error)
 }
 
+// RawValueResolver is implemented by parameters escaping their resolved value, so it can be placed into JSON templates
+type RawValueResolver interface {
+	// ResolveRawValue resolves the value of this parameter like ResolveValue, but without escaping it.
+	ResolveRawValue(context ResolveContext) (interface{}, error)
+}
+
 type NamedParameter struct {
 	Name      string
 	Parameter Parameter
@@ -87,6 +120,12 @@ type ParameterParserContext struct {
 	ParameterName string
 	// current value to parse
 	Value map[string]interface{}
+	// Fs the config file is loaded from. Parameters loading additional files use it to read them.
+	Fs afero.Fs
+	// Folder containing the config file. Relative file paths of parameters are resolved against it.
+	Folder string
+	// Variables holds the values of the manifest variables for the environment the config is loaded for
+	Variables map[string]string
 }
 
 type ParameterParserError struct {
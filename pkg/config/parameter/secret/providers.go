@@ -0,0 +1,259 @@
+// @license
+// Copyright 2024 Dynatrace LLC
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package secret
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/spf13/afero"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const (
+	// SecretsKeyEnvVar is the environment variable holding the base64 encoded AES-256 key of encrypted secrets files
+	SecretsKeyEnvVar = "MONACO_SECRETS_KEY"
+
+	// VaultAddrEnvVar is the environment variable holding the address of the Vault server
+	VaultAddrEnvVar = "VAULT_ADDR"
+
+	// VaultTokenEnvVar is the environment variable holding the token used to authenticate against Vault
+	VaultTokenEnvVar = "VAULT_TOKEN"
+
+	defaultVaultMount = "secret"
+)
+
+// Provider loads the values of secret parameters from a secret store
+type Provider interface {
+	// Validate checks that the given parameter defines all properties the provider requires
+	Validate(p *SecretParameter) error
+
+	// Resolve returns the value of the secret referenced by the given parameter. Requests to the secret store use the
+	// given context.
+	Resolve(ctx context.Context, p *SecretParameter) (string, error)
+}
+
+// Providers holds all providers secret parameters can use, by the name used in config files
+var Providers = map[string]Provider{
+	"environment": EnvironmentProvider{},
+	"file":        FileProvider{},
+	"vault":       VaultProvider{Client: &http.Client{Timeout: 30 * time.Second}},
+}
+
+// EnvironmentProvider loads secrets from the environment variable named by the parameter
+type EnvironmentProvider struct{}
+
+func (EnvironmentProvider) Validate(*SecretParameter) error {
+	return nil
+}
+
+func (EnvironmentProvider) Resolve(_ context.Context, p *SecretParameter) (string, error) {
+	val, found := os.LookupEnv(p.Name)
+	if !found {
+		return "", fmt.Errorf("environment variable `%s` not set", p.Name)
+	}
+	return val, nil
+}
+
+// FileProvider loads secrets from an encrypted secrets file next to the config. The file contains a JSON object
+// mapping names to secret values, encrypted with AES-256-GCM using the key stored in SecretsKeyEnvVar.
+// Use EncryptSecrets to create such a file.
+type FileProvider struct{}
+
+func (FileProvider) Validate(p *SecretParameter) error {
+	if p.Path == "" {
+		return errors.New("missing property `path`")
+	}
+	if p.fs == nil {
+		return errors.New("files can not be read in this context")
+	}
+	return nil
+}
+
+func (FileProvider) Resolve(_ context.Context, p *SecretParameter) (string, error) {
+	key, err := secretsKey()
+	if err != nil {
+		return "", err
+	}
+
+	content, err := afero.ReadFile(p.fs, filepath.Join(p.folder, p.Path))
+	if err != nil {
+		return "", fmt.Errorf("failed to read secrets file %q: %w", p.Path, err)
+	}
+
+	secrets, err := decryptSecrets(key, content)
+	if err != nil {
+		return "", fmt.Errorf("failed to decrypt secrets file %q: %w", p.Path, err)
+	}
+
+	val, found := secrets[p.Name]
+	if !found {
+		return "", fmt.Errorf("secrets file %q does not contain a secret named %q", p.Path, p.Name)
+	}
+	return val, nil
+}
+
+func secretsKey() ([]byte, error) {
+	encoded, found := os.LookupEnv(SecretsKeyEnvVar)
+	if !found {
+		return nil, fmt.Errorf("environment variable `%s` not set", SecretsKeyEnvVar)
+	}
+	key, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("environment variable `%s` is not base64 encoded: %w", SecretsKeyEnvVar, err)
+	}
+	return key, nil
+}
+
+// EncryptSecrets returns the content of a secrets file readable by FileProvider, holding the given secrets encrypted
+// with the given AES-256 key
+func EncryptSecrets(key []byte, secrets map[string]string) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+
+	plaintext, err := json.Marshal(secrets)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, err
+	}
+
+	ciphertext := gcm.Seal(nonce, nonce, plaintext, nil)
+	return []byte(base64.StdEncoding.EncodeToString(ciphertext)), nil
+}
+
+func decryptSecrets(key []byte, content []byte) (map[string]string, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+
+	ciphertext, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(content)))
+	if err != nil {
+		return nil, fmt.Errorf("content is not base64 encoded: %w", err)
+	}
+	if len(ciphertext) < gcm.NonceSize() {
+		return nil, errors.New("content is too short")
+	}
+
+	nonce, ciphertext := ciphertext[:gcm.NonceSize()], ciphertext[gcm.NonceSize():]
+	plaintext, err := gcm.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var secrets map[string]string
+	if err := json.Unmarshal(plaintext, &secrets); err != nil {
+		return nil, fmt.Errorf("content is not a JSON object of strings: %w", err)
+	}
+	return secrets, nil
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	if len(key) != 32 {
+		return nil, fmt.Errorf("key must be 32 bytes long, but is %d bytes long", len(key))
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// VaultProvider loads secrets from a HashiCorp Vault KV version 2 secrets engine. The secret at the parameter's path is
+// read from the engine mounted at the parameter's mount (default "secret"), and the value of the key named by the
+// parameter is returned. The Vault server and token are configured via VaultAddrEnvVar and VaultTokenEnvVar.
+type VaultProvider struct {
+	Client *http.Client
+}
+
+func (VaultProvider) Validate(p *SecretParameter) error {
+	if p.Path == "" {
+		return errors.New("missing property `path`")
+	}
+	return nil
+}
+
+func (v VaultProvider) Resolve(ctx context.Context, p *SecretParameter) (string, error) {
+	addr, found := os.LookupEnv(VaultAddrEnvVar)
+	if !found {
+		return "", fmt.Errorf("environment variable `%s` not set", VaultAddrEnvVar)
+	}
+	token, found := os.LookupEnv(VaultTokenEnvVar)
+	if !found {
+		return "", fmt.Errorf("environment variable `%s` not set", VaultTokenEnvVar)
+	}
+
+	mount := p.Mount
+	if mount == "" {
+		mount = defaultVaultMount
+	}
+
+	u, err := url.JoinPath(addr, "v1", mount, "data", filepath.ToSlash(p.Path))
+	if err != nil {
+		return "", fmt.Errorf("invalid vault address %q: %w", addr, err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("X-Vault-Token", token)
+
+	resp, err := v.Client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to read secret from vault: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("failed to read secret %q from vault: HTTP %d", p.Path, resp.StatusCode)
+	}
+
+	var body struct {
+		Data struct {
+			Data map[string]any `json:"data"`
+		} `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return "", fmt.Errorf("failed to parse vault response: %w", err)
+	}
+
+	val, found := body.Data.Data[p.Name]
+	if !found {
+		return "", fmt.Errorf("vault secret %q does not contain a key named %q", p.Path, p.Name)
+	}
+	s, ok := val.(string)
+	if !ok {
+		return "", fmt.Errorf("value of key %q of vault secret %q is not a string", p.Name, p.Path)
+	}
+	return s, nil
+}
@@ -0,0 +1,230 @@
+// @license
+// Copyright 2024 Dynatrace LLC
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package secret
+
+import (
+	gocontext "context"
+	"fmt"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/secret"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/strings"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/template"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/parameter"
+	"github.com/spf13/afero"
+	"golang.org/x/exp/maps"
+	"path/filepath"
+	"slices"
+	"sync"
+)
+
+// SecretParameterType specifies the type of the parameter used in config files
+const SecretParameterType = "secret"
+
+var SecretParameterSerde = parameter.ParameterSerDe{
+	Serializer:   writeSecretParameter,
+	Deserializer: parseSecretParameter,
+}
+
+// SecretParameter defines a parameter whose value is loaded from a secret store by a Provider. Resolved values are
+// registered as sensitive, so that they are masked in logs, traffic logs and reports. Values shorter than
+// secret.MinSensitiveLength are rejected, as they can not be masked safely.
+type SecretParameter struct {
+	// Provider is the name of the Provider the secret is loaded from
+	Provider string
+
+	// Name of the secret within the secret store
+	Name string
+
+	// Path of the secret store, if required by the provider. For the file provider this is the path of the secrets file
+	// relative to the folder of the config file, for the vault provider the path of the secret within the KV engine.
+	Path string
+
+	// Mount is the path the KV engine is mounted at. It is only used by the vault provider.
+	Mount string
+
+	// fs and folder are used to read files relative to the config file the parameter is defined in
+	fs     afero.Fs
+	folder string
+}
+
+// this forces the compiler to check if SecretParameter is of type Parameter
+var _ parameter.Parameter = (*SecretParameter)(nil)
+var _ parameter.RawValueResolver = (*SecretParameter)(nil)
+
+func (p *SecretParameter) GetType() string {
+	return SecretParameterType
+}
+
+func (p *SecretParameter) GetReferences() []parameter.ParameterReference {
+	// secret parameters cannot have references
+	return []parameter.ParameterReference{}
+}
+
+func (p *SecretParameter) ResolveValue(context parameter.ResolveContext) (interface{}, error) {
+	val, err := p.ResolveRawValue(context)
+	if err != nil {
+		return nil, err
+	}
+
+	escaped, err := template.EscapeSpecialCharactersInValue(val, template.FullStringEscapeFunction)
+	if err != nil {
+		return nil, err
+	}
+	if s, ok := escaped.(string); ok {
+		secret.RegisterSensitive(s)
+	}
+	return escaped, nil
+}
+
+func (p *SecretParameter) ResolveRawValue(context parameter.ResolveContext) (interface{}, error) {
+	ctx := context.Context
+	if ctx == nil {
+		ctx = gocontext.TODO()
+	}
+
+	cache := cacheFromContext(ctx)
+	key := p.cacheKey()
+	if val, found := cache.get(key); found {
+		return val, nil
+	}
+
+	provider, found := Providers[p.Provider]
+	if !found {
+		return nil, parameter.NewParameterResolveValueError(context, fmt.Sprintf("unknown secret provider %q", p.Provider))
+	}
+
+	val, err := provider.Resolve(ctx, p)
+	if err != nil {
+		return nil, parameter.NewParameterResolveValueError(context, fmt.Sprintf("failed to resolve secret %q: %s", p.Name, err))
+	}
+
+	if len(val) < secret.MinSensitiveLength {
+		return nil, parameter.NewParameterResolveValueError(context, fmt.Sprintf("value of secret %q is shorter than %d characters and can not be masked safely", p.Name, secret.MinSensitiveLength))
+	}
+	secret.RegisterSensitive(val)
+
+	cache.set(key, val)
+	return val, nil
+}
+
+// secretKey identifies a secret within the secret stores of all providers
+type secretKey struct {
+	provider, name, path, mount, folder string
+}
+
+func (p *SecretParameter) cacheKey() secretKey {
+	return secretKey{provider: p.Provider, name: p.Name, path: p.Path, mount: p.Mount, folder: p.folder}
+}
+
+type ctxKeySecretCache struct{}
+
+// WithCache returns a context caching the values of all secrets resolved with it, so that each secret is loaded from
+// its store only once, even if it is used by many configs and environments. Secrets are only cached as long as the
+// returned context is used, e.g. for a single deployment. If the given context already caches secrets, it is returned.
+func WithCache(ctx gocontext.Context) gocontext.Context {
+	if cacheFromContext(ctx) != nil {
+		return ctx
+	}
+	return gocontext.WithValue(ctx, ctxKeySecretCache{}, &secretCache{values: make(map[secretKey]string)})
+}
+
+// cacheFromContext returns the cache of the given context, nil if secrets are not cached
+func cacheFromContext(ctx gocontext.Context) *secretCache {
+	c, _ := ctx.Value(ctxKeySecretCache{}).(*secretCache)
+	return c
+}
+
+// secretCache holds the values of resolved secrets. A nil cache does not cache anything.
+type secretCache struct {
+	mu     sync.Mutex
+	values map[secretKey]string
+}
+
+func (c *secretCache) get(key secretKey) (string, bool) {
+	if c == nil {
+		return "", false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	val, found := c.values[key]
+	return val, found
+}
+
+func (c *secretCache) set(key secretKey, val string) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.values[key] = val
+}
+
+// parseSecretParameter parses a SecretParameter from a given context.
+// it requires the `provider` and `name` fields to be set. Which other fields are required depends on the provider.
+func parseSecretParameter(context parameter.ParameterParserContext) (parameter.Parameter, error) {
+	providerName, ok := context.Value["provider"]
+	if !ok {
+		return nil, parameter.NewParameterParserError(context, "missing property `provider`")
+	}
+	provider, found := Providers[strings.ToString(providerName)]
+	if !found {
+		names := maps.Keys(Providers)
+		slices.Sort(names)
+		return nil, parameter.NewParameterParserError(context, fmt.Sprintf("unknown secret provider %q, must be one of %v", providerName, names))
+	}
+
+	name, ok := context.Value["name"]
+	if !ok {
+		return nil, parameter.NewParameterParserError(context, "missing property `name`")
+	}
+
+	p := &SecretParameter{
+		Provider: strings.ToString(providerName),
+		Name:     strings.ToString(name),
+		fs:       context.Fs,
+		folder:   context.Folder,
+	}
+	if path, ok := context.Value["path"]; ok {
+		p.Path = filepath.FromSlash(strings.ToString(path))
+	}
+	if mount, ok := context.Value["mount"]; ok {
+		p.Mount = strings.ToString(mount)
+	}
+
+	if err := provider.Validate(p); err != nil {
+		return nil, parameter.NewParameterParserError(context, err.Error())
+	}
+	return p, nil
+}
+
+func writeSecretParameter(context parameter.ParameterWriterContext) (map[string]interface{}, error) {
+	secretParam, ok := context.Parameter.(*SecretParameter)
+
+	if !ok {
+		return nil, parameter.NewParameterWriterError(context, "unexpected type. parameter is not of type `SecretParameter`")
+	}
+
+	result := make(map[string]interface{})
+	result["provider"] = secretParam.Provider
+	result["name"] = secretParam.Name
+
+	if secretParam.Path != "" {
+		result["path"] = filepath.ToSlash(secretParam.Path)
+	}
+	if secretParam.Mount != "" {
+		result["mount"] = secretParam.Mount
+	}
+
+	return result, nil
+}
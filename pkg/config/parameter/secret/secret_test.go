@@ -0,0 +1,231 @@
+//go:build unit
+
+// @license
+// Copyright 2024 Dynatrace LLC
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package secret
+
+import (
+	"context"
+	"encoding/base64"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/secret"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/parameter"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+)
+
+var testKey = []byte("0123456789abcdef0123456789abcdef")
+
+func TestParseSecretParameter(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	param, err := parseSecretParameter(parameter.ParameterParserContext{
+		Fs:     fs,
+		Folder: "project",
+		Value: map[string]interface{}{
+			"provider": "file",
+			"name":     "token",
+			"path":     "secrets/prod.enc",
+		},
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, &SecretParameter{
+		Provider: "file",
+		Name:     "token",
+		Path:     filepath.FromSlash("secrets/prod.enc"),
+		fs:       fs,
+		folder:   "project",
+	}, param)
+}
+
+func TestParseSecretParameter_Errors(t *testing.T) {
+	tests := []struct {
+		name  string
+		value map[string]interface{}
+		want  string
+	}{
+		{"missing provider", map[string]interface{}{"name": "token"}, "missing property `provider`"},
+		{"unknown provider", map[string]interface{}{"provider": "keychain", "name": "token"}, `unknown secret provider "keychain"`},
+		{"missing name", map[string]interface{}{"provider": "environment"}, "missing property `name`"},
+		{"file without path", map[string]interface{}{"provider": "file", "name": "token"}, "missing property `path`"},
+		{"vault without path", map[string]interface{}{"provider": "vault", "name": "token"}, "missing property `path`"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := parseSecretParameter(parameter.ParameterParserContext{
+				Fs:    afero.NewMemMapFs(),
+				Value: tt.value,
+			})
+			assert.ErrorContains(t, err, tt.want)
+		})
+	}
+}
+
+func TestWriteSecretParameter(t *testing.T) {
+	result, err := writeSecretParameter(parameter.ParameterWriterContext{
+		Parameter: &SecretParameter{Provider: "vault", Name: "token", Path: "monaco/prod", Mount: "kv"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"provider": "vault", "name": "token", "path": "monaco/prod", "mount": "kv"}, result)
+}
+
+func TestResolveSecretParameter_Environment(t *testing.T) {
+	t.Setenv("SECRET_PARAM_TEST", `env"secret`)
+
+	val, err := (&SecretParameter{Provider: "environment", Name: "SECRET_PARAM_TEST"}).ResolveValue(parameter.ResolveContext{})
+	require.NoError(t, err)
+	assert.Equal(t, `env\"secret`, val)
+	assert.Equal(t, "token: ****", secret.Mask(`token: env"secret`), "resolved values must be masked")
+	assert.Equal(t, `{"token": "****"}`, secret.Mask(`{"token": "env\"secret"}`), "escaped values must be masked")
+
+	_, err = (&SecretParameter{Provider: "environment", Name: "SECRET_PARAM_TEST_UNSET"}).ResolveValue(parameter.ResolveContext{})
+	assert.ErrorContains(t, err, "environment variable `SECRET_PARAM_TEST_UNSET` not set")
+}
+
+func TestResolveSecretParameter_File(t *testing.T) {
+	content, err := EncryptSecrets(testKey, map[string]string{"token": "file-secret"})
+	require.NoError(t, err)
+
+	fs := afero.NewMemMapFs()
+	require.NoError(t, afero.WriteFile(fs, filepath.Join("project", "secrets.enc"), content, 0644))
+	p := &SecretParameter{Provider: "file", Name: "token", Path: "secrets.enc", fs: fs, folder: "project"}
+
+	t.Run("resolves secret", func(t *testing.T) {
+		t.Setenv(SecretsKeyEnvVar, base64.StdEncoding.EncodeToString(testKey))
+
+		val, err := p.ResolveValue(parameter.ResolveContext{})
+		require.NoError(t, err)
+		assert.Equal(t, "file-secret", val)
+		assert.Equal(t, "****", secret.Mask("file-secret"))
+	})
+
+	t.Run("fails for unknown secret", func(t *testing.T) {
+		t.Setenv(SecretsKeyEnvVar, base64.StdEncoding.EncodeToString(testKey))
+
+		_, err := (&SecretParameter{Provider: "file", Name: "other", Path: "secrets.enc", fs: fs, folder: "project"}).ResolveValue(parameter.ResolveContext{})
+		assert.ErrorContains(t, err, `does not contain a secret named "other"`)
+	})
+
+	t.Run("fails with wrong key", func(t *testing.T) {
+		t.Setenv(SecretsKeyEnvVar, base64.StdEncoding.EncodeToString([]byte("fedcba9876543210fedcba9876543210")))
+
+		_, err := p.ResolveValue(parameter.ResolveContext{})
+		assert.ErrorContains(t, err, "failed to decrypt secrets file")
+	})
+
+	t.Run("fails without key", func(t *testing.T) {
+		_, err := p.ResolveValue(parameter.ResolveContext{})
+		assert.ErrorContains(t, err, "environment variable `MONACO_SECRETS_KEY` not set")
+	})
+}
+
+func TestResolveSecretParameter_Vault(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Vault-Token") != "vault-token" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		switch r.URL.Path {
+		case "/v1/secret/data/monaco/prod":
+			_, _ = w.Write([]byte(`{"data": {"data": {"token": "vault-secret"}, "metadata": {"version": 1}}}`))
+		case "/v1/kv/data/monaco/prod":
+			_, _ = w.Write([]byte(`{"data": {"data": {"token": "kv-secret"}, "metadata": {"version": 3}}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	t.Setenv(VaultAddrEnvVar, server.URL)
+	t.Setenv(VaultTokenEnvVar, "vault-token")
+
+	val, err := (&SecretParameter{Provider: "vault", Name: "token", Path: "monaco/prod"}).ResolveValue(parameter.ResolveContext{})
+	require.NoError(t, err)
+	assert.Equal(t, "vault-secret", val)
+
+	val, err = (&SecretParameter{Provider: "vault", Name: "token", Path: "monaco/prod", Mount: "kv"}).ResolveValue(parameter.ResolveContext{})
+	require.NoError(t, err)
+	assert.Equal(t, "kv-secret", val)
+
+	_, err = (&SecretParameter{Provider: "vault", Name: "other", Path: "monaco/prod"}).ResolveValue(parameter.ResolveContext{})
+	assert.ErrorContains(t, err, `does not contain a key named "other"`)
+
+	_, err = (&SecretParameter{Provider: "vault", Name: "token", Path: "monaco/missing"}).ResolveValue(parameter.ResolveContext{})
+	assert.ErrorContains(t, err, "HTTP 404")
+
+	t.Setenv(VaultTokenEnvVar, "wrong-token")
+	_, err = (&SecretParameter{Provider: "vault", Name: "token", Path: "monaco/prod"}).ResolveValue(parameter.ResolveContext{})
+	assert.ErrorContains(t, err, "HTTP 403")
+}
+
+func TestResolveSecretParameter_VaultCachesSecrets(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		_, _ = w.Write([]byte(`{"data": {"data": {"token": "cached-secret"}}}`))
+	}))
+	defer server.Close()
+
+	t.Setenv(VaultAddrEnvVar, server.URL)
+	t.Setenv(VaultTokenEnvVar, "vault-token")
+
+	ctx := WithCache(context.TODO())
+	for i := 0; i < 3; i++ {
+		val, err := (&SecretParameter{Provider: "vault", Name: "token", Path: "monaco/cached"}).ResolveValue(parameter.ResolveContext{Context: ctx})
+		require.NoError(t, err)
+		assert.Equal(t, "cached-secret", val)
+	}
+	assert.Equal(t, 1, requests, "secret must only be requested once")
+
+	_, err := (&SecretParameter{Provider: "vault", Name: "token", Path: "monaco/other"}).ResolveValue(parameter.ResolveContext{Context: ctx})
+	require.NoError(t, err)
+	assert.Equal(t, 2, requests, "secrets of other paths must be requested")
+
+	_, err = (&SecretParameter{Provider: "vault", Name: "token", Path: "monaco/cached"}).ResolveValue(parameter.ResolveContext{Context: WithCache(context.TODO())})
+	require.NoError(t, err)
+	assert.Equal(t, 3, requests, "secrets must not be cached across contexts")
+
+	_, err = (&SecretParameter{Provider: "vault", Name: "token", Path: "monaco/cached"}).ResolveValue(parameter.ResolveContext{})
+	require.NoError(t, err)
+	assert.Equal(t, 4, requests, "secrets must not be cached without a cache in the context")
+}
+
+func TestResolveSecretParameter_VaultUsesContext(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"data": {"data": {"token": "vault-secret"}}}`))
+	}))
+	defer server.Close()
+
+	t.Setenv(VaultAddrEnvVar, server.URL)
+	t.Setenv(VaultTokenEnvVar, "vault-token")
+
+	ctx, cancel := context.WithCancel(context.TODO())
+	cancel()
+
+	_, err := (&SecretParameter{Provider: "vault", Name: "token", Path: "monaco/prod"}).ResolveValue(parameter.ResolveContext{Context: ctx})
+	assert.ErrorContains(t, err, "context canceled")
+}
+
+func TestResolveSecretParameter_ShortValuesAreRejected(t *testing.T) {
+	t.Setenv("SECRET_PARAM_TEST_SHORT", "on")
+
+	_, err := (&SecretParameter{Provider: "environment", Name: "SECRET_PARAM_TEST_SHORT"}).ResolveValue(parameter.ResolveContext{})
+	assert.ErrorContains(t, err, `value of secret "SECRET_PARAM_TEST_SHORT" is shorter than 4 characters and can not be masked safely`)
+}
@@ -39,6 +39,7 @@ func New(value interface{}) *ValueParameter {
 
 // this forces the compiler to check if ValueParameter is of type Parameter
 var _ parameter.Parameter = (*ValueParameter)(nil)
+var _ parameter.RawValueResolver = (*ValueParameter)(nil)
 
 func (p *ValueParameter) GetType() string {
 	return ValueParameterType
@@ -49,8 +50,16 @@ func (p *ValueParameter) GetReferences() []parameter.ParameterReference {
 	return []parameter.ParameterReference{}
 }
 
-func (p *ValueParameter) ResolveValue(_ parameter.ResolveContext) (interface{}, error) {
-	return template.EscapeSpecialCharactersInValue(p.Value, template.FullStringEscapeFunction)
+func (p *ValueParameter) ResolveValue(context parameter.ResolveContext) (interface{}, error) {
+	val, err := p.ResolveRawValue(context)
+	if err != nil {
+		return nil, err
+	}
+	return template.EscapeSpecialCharactersInValue(val, template.FullStringEscapeFunction)
+}
+
+func (p *ValueParameter) ResolveRawValue(_ parameter.ResolveContext) (interface{}, error) {
+	return p.Value, nil
 }
 
 // parseValueParameter parses a given context into an instance of ValueParameter.
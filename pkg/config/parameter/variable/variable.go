@@ -0,0 +1,131 @@
+// @license
+// Copyright 2024 Dynatrace LLC
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package variable
+
+import (
+	"fmt"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/strings"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/template"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/parameter"
+)
+
+// VariableParameterType specifies the type of the parameter used in config files
+const VariableParameterType = "variable"
+
+var VariableParameterSerde = parameter.ParameterSerDe{
+	Serializer:   writeVariableParameter,
+	Deserializer: parseVariableParameter,
+}
+
+// VariableParameter references a variable defined in the manifest. The value of the variable is taken from the
+// environment the config is loaded for, including group and environment overrides. A default value can be defined,
+// which is used if the variable is not defined for the environment.
+type VariableParameter struct {
+	// name of the referenced manifest variable
+	Name string
+
+	// flag indicating that the variable is defined for the environment of the config
+	Defined bool
+
+	// value of the variable for the environment of the config.
+	// note: this value is only used, if the `Defined` flag is set to true.
+	Value string
+
+	// flag indicating that a default value has been set. this is needed, as
+	// we cannot distinguish an empty string from an not set value.
+	HasDefaultValue bool
+
+	// default value used if the variable is not defined for the environment.
+	// note: this value is only used, if the `HasDefaultValue` flag is set to true.
+	DefaultValue string
+}
+
+// this forces the compiler to check if VariableParameter is of type Parameter
+var _ parameter.Parameter = (*VariableParameter)(nil)
+var _ parameter.RawValueResolver = (*VariableParameter)(nil)
+
+func (p *VariableParameter) GetType() string {
+	return VariableParameterType
+}
+
+func (p *VariableParameter) GetReferences() []parameter.ParameterReference {
+	// manifest variables cannot reference other parameters
+	return []parameter.ParameterReference{}
+}
+
+func (p *VariableParameter) ResolveValue(context parameter.ResolveContext) (interface{}, error) {
+	val, err := p.ResolveRawValue(context)
+	if err != nil {
+		return nil, err
+	}
+	return template.EscapeSpecialCharactersInValue(val, template.FullStringEscapeFunction)
+}
+
+func (p *VariableParameter) ResolveRawValue(context parameter.ResolveContext) (interface{}, error) {
+	val := p.Value
+	if !p.Defined && p.HasDefaultValue {
+		val = p.DefaultValue
+	} else if !p.Defined {
+		return nil, parameter.NewParameterResolveValueError(context, fmt.Sprintf("variable `%s` is not defined in the manifest for environment `%s`", p.Name, context.Environment))
+	}
+
+	return val, nil
+}
+
+// parseVariableParameter parses a VariableParameter from a given context and looks up the value of the variable for
+// the environment of the config. It requires a `name` field to be set. `default` is an optional field.
+func parseVariableParameter(context parameter.ParameterParserContext) (parameter.Parameter, error) {
+	name, ok := context.Value["name"]
+	if !ok {
+		return nil, parameter.NewParameterParserError(context, "missing property `name`")
+	}
+
+	p := &VariableParameter{
+		Name: strings.ToString(name),
+	}
+	if p.Name == "" {
+		return nil, parameter.NewParameterParserError(context, "property `name` must not be empty")
+	}
+
+	if val, ok := context.Variables[p.Name]; ok {
+		p.Defined = true
+		p.Value = val
+	}
+
+	if val, ok := context.Value["default"]; ok {
+		p.HasDefaultValue = true
+		p.DefaultValue = strings.ToString(val)
+	}
+
+	return p, nil
+}
+
+func writeVariableParameter(context parameter.ParameterWriterContext) (map[string]interface{}, error) {
+	variableParam, ok := context.Parameter.(*VariableParameter)
+
+	if !ok {
+		return nil, parameter.NewParameterWriterError(context, "unexpected type. parameter is not of type `VariableParameter`")
+	}
+
+	result := make(map[string]interface{})
+
+	if variableParam.HasDefaultValue {
+		result["default"] = variableParam.DefaultValue
+	}
+
+	result["name"] = variableParam.Name
+
+	return result, nil
+}
@@ -0,0 +1,117 @@
+//go:build unit
+
+// @license
+// Copyright 2024 Dynatrace LLC
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package variable
+
+import (
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/parameter"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestParseVariableParameter(t *testing.T) {
+	tests := []struct {
+		name  string
+		value map[string]interface{}
+		want  *VariableParameter
+	}{
+		{
+			name:  "defined variable",
+			value: map[string]interface{}{"name": "domain"},
+			want:  &VariableParameter{Name: "domain", Defined: true, Value: "example.com"},
+		},
+		{
+			name:  "undefined variable",
+			value: map[string]interface{}{"name": "channel"},
+			want:  &VariableParameter{Name: "channel"},
+		},
+		{
+			name:  "undefined variable with default",
+			value: map[string]interface{}{"name": "channel", "default": "#alerts"},
+			want:  &VariableParameter{Name: "channel", HasDefaultValue: true, DefaultValue: "#alerts"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseVariableParameter(parameter.ParameterParserContext{
+				Value:     tt.value,
+				Variables: map[string]string{"domain": "example.com"},
+			})
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestParseVariableParameter_Errors(t *testing.T) {
+	_, err := parseVariableParameter(parameter.ParameterParserContext{Value: map[string]interface{}{"default": "value"}})
+	assert.ErrorContains(t, err, "missing property `name`")
+
+	_, err = parseVariableParameter(parameter.ParameterParserContext{Value: map[string]interface{}{"name": ""}})
+	assert.ErrorContains(t, err, "must not be empty")
+}
+
+func TestWriteVariableParameter(t *testing.T) {
+	got, err := writeVariableParameter(parameter.ParameterWriterContext{
+		Parameter: &VariableParameter{Name: "channel", Defined: true, Value: "#ops", HasDefaultValue: true, DefaultValue: "#alerts"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"name": "channel", "default": "#alerts"}, got)
+}
+
+func TestResolveVariableParameter(t *testing.T) {
+	tests := []struct {
+		name      string
+		parameter VariableParameter
+		want      interface{}
+	}{
+		{
+			name:      "defined variable",
+			parameter: VariableParameter{Name: "domain", Defined: true, Value: "example.com"},
+			want:      "example.com",
+		},
+		{
+			name:      "defined variable takes precedence over default",
+			parameter: VariableParameter{Name: "channel", Defined: true, Value: "#ops", HasDefaultValue: true, DefaultValue: "#alerts"},
+			want:      "#ops",
+		},
+		{
+			name:      "default of undefined variable",
+			parameter: VariableParameter{Name: "channel", HasDefaultValue: true, DefaultValue: "#alerts"},
+			want:      "#alerts",
+		},
+		{
+			name:      "special characters are escaped",
+			parameter: VariableParameter{Name: "signature", Defined: true, Value: `"Ops"`},
+			want:      `\"Ops\"`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.parameter.ResolveValue(parameter.ResolveContext{})
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestResolveVariableParameter_Undefined(t *testing.T) {
+	p := VariableParameter{Name: "channel"}
+
+	_, err := p.ResolveValue(parameter.ResolveContext{Environment: "prod"})
+	assert.ErrorContains(t, err, "variable `channel` is not defined in the manifest for environment `prod`")
+}
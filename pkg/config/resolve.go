@@ -17,6 +17,8 @@
 package config
 
 import (
+	"context"
+	"fmt"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/strings"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/coordinate"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/parameter"
@@ -24,6 +26,7 @@ import (
 
 // resolveValues validates and resolves the given sorted parameters into actual values
 func resolveValues(
+	ctx context.Context,
 	c *Config,
 	entities EntityLookup,
 	parameters []parameter.NamedParameter,
@@ -33,6 +36,10 @@ func resolveValues(
 
 	properties := make(parameter.Properties)
 
+	// entity selectors and existing objects can only be resolved if the lookup has access to the environment
+	entitySelectorResolver, _ := entities.(parameter.EntitySelectorResolver)
+	objectLookup, _ := entities.(parameter.ObjectLookup)
+
 	for _, container := range parameters {
 		name := container.Name
 		param := container.Parameter
@@ -44,20 +51,32 @@ func resolveValues(
 			continue
 		}
 
-		val, err := param.ResolveValue(parameter.ResolveContext{
+		resolveContext := parameter.ResolveContext{
+			Context:                 ctx,
 			PropertyResolver:        entities,
+			EntitySelectorResolver:  entitySelectorResolver,
+			ObjectLookup:            objectLookup,
 			ConfigCoordinate:        c.Coordinate,
 			Group:                   c.Group,
 			Environment:             c.Environment,
 			ParameterName:           name,
 			ResolvedParameterValues: properties,
-		})
+		}
+
+		val, err := param.ResolveValue(resolveContext)
 
 		if err != nil {
 			errors = append(errors, err)
 			continue
 		}
 
+		if constraints, found := c.ParameterConstraints[name]; found {
+			if err := checkConstraints(constraints, param, resolveContext, val); err != nil {
+				errors = append(errors, err)
+				continue
+			}
+		}
+
 		if name == NameParameter {
 			properties[name] = strings.ToString(val)
 		} else {
@@ -74,6 +93,22 @@ func resolveValues(
 	return properties, nil
 }
 
+// checkConstraints checks the value of the given parameter against its constraints. As the resolved values of most
+// parameters are escaped to be placed into JSON templates, their raw value is checked instead, if they provide it.
+func checkConstraints(constraints parameter.Constraints, param parameter.Parameter, resolveContext parameter.ResolveContext, val interface{}) error {
+	if raw, ok := param.(parameter.RawValueResolver); ok {
+		var err error
+		if val, err = raw.ResolveRawValue(resolveContext); err != nil {
+			return err
+		}
+	}
+
+	if err := constraints.Check(val); err != nil {
+		return parameter.NewParameterResolveValueError(resolveContext, fmt.Sprintf("constraint violated: %s", err))
+	}
+	return nil
+}
+
 func validateParameterReferences(configCoordinates coordinate.Coordinate, group string, environment string, entityLookup EntityLookup, paramName string, param parameter.Parameter) (errs []error) {
 
 	for _, ref := range param.GetReferences() {
@@ -35,6 +35,8 @@ type FileBasedTemplate struct {
 	fs afero.Fs
 	// path of the template file
 	path string
+	// partialFolders are the folders partials included by the template are looked up in
+	partialFolders []string
 }
 
 func (t *FileBasedTemplate) ID() string {
@@ -46,7 +48,10 @@ func (t *FileBasedTemplate) Content() (string, error) {
 	if err != nil {
 		return "", fmt.Errorf("failed to read template content: %w", err)
 	}
-	return string(b), nil
+	if len(t.partialFolders) == 0 {
+		return string(b), nil
+	}
+	return resolveIncludes(t.fs, t.partialFolders, t.path, string(b), nil)
 }
 
 func (t *FileBasedTemplate) FilePath() string {
@@ -68,6 +73,14 @@ func (t *FileBasedTemplate) UpdateContent(newContent string) error {
 // NewFileTemplate creates a FileBasedTemplate for a given afero.Fs and filepath.
 // If the file can not be accessed an error will be returned.
 func NewFileTemplate(fs afero.Fs, path string) (Template, error) {
+	return NewFileTemplateWithPartials(fs, path, nil)
+}
+
+// NewFileTemplateWithPartials creates a FileBasedTemplate for a given afero.Fs and filepath, which may include partials
+// from the given folders, e.g. {{ include "tiles/markdown.json" }}. Included partials are rendered with the same
+// properties as the template.
+// If the file can not be accessed, or any included partial can not be resolved, an error will be returned.
+func NewFileTemplateWithPartials(fs afero.Fs, path string, partialFolders []string) (Template, error) {
 	sanitizedPath := filepath.Clean(strings.ReplaceAll(path, `\`, `/`))
 
 	log.Debug("Loading template for %s", sanitizedPath)
@@ -79,8 +92,16 @@ func NewFileTemplate(fs afero.Fs, path string) (Template, error) {
 	}
 
 	template := FileBasedTemplate{
-		fs:   fs,
-		path: sanitizedPath,
+		fs:             fs,
+		path:           sanitizedPath,
+		partialFolders: partialFolders,
+	}
+
+	// resolve includes once, so that missing partials and cyclic includes are reported when loading
+	if len(partialFolders) > 0 {
+		if _, err := template.Content(); err != nil {
+			return nil, fmt.Errorf("failed to load template: %w", err)
+		}
 	}
 
 	return &template, nil
@@ -0,0 +1,166 @@
+// @license
+// Copyright 2024 Dynatrace LLC
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package template
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	templ "text/template" // nosemgrep: go.lang.security.audit.xss.import-text-template.import-text-template
+)
+
+// Function is a function that can be used in config templates and compound parameter formats
+type Function struct {
+	// Name of the function, as used in templates
+	Name string `json:"name"`
+
+	// Usage shows how the function is called in templates
+	Usage string `json:"usage"`
+
+	// Description of what the function does
+	Description string `json:"description"`
+
+	fn any
+}
+
+// Functions are all functions available in templates. Note that the values of most parameters are already escaped for
+// use in JSON strings when they are passed to the template.
+var Functions = []Function{
+	{
+		Name:        "toJson",
+		Usage:       "{{ toJson .value }}",
+		Description: "Returns the JSON representation of the value, e.g. to insert list or object parameters",
+		fn:          toJSON,
+	},
+	{
+		Name:        "jsonEscape",
+		Usage:       "{{ jsonEscape .value }}",
+		Description: "Escapes the value for use within a JSON string",
+		fn:          jsonEscape,
+	},
+	{
+		Name:        "default",
+		Usage:       "{{ default \"fallback\" .value }}",
+		Description: "Returns the value, or the given default if the value is empty",
+		fn:          defaultValue,
+	},
+	{
+		Name:        "join",
+		Usage:       "{{ join \", \" .list }}",
+		Description: "Joins the elements of the list, separated by the given separator",
+		fn:          join,
+	},
+	{
+		Name:        "lower",
+		Usage:       "{{ lower .value }}",
+		Description: "Converts the value to lower case",
+		fn:          func(v any) string { return strings.ToLower(toString(v)) },
+	},
+	{
+		Name:        "upper",
+		Usage:       "{{ upper .value }}",
+		Description: "Converts the value to upper case",
+		fn:          func(v any) string { return strings.ToUpper(toString(v)) },
+	},
+	{
+		Name:        "replace",
+		Usage:       "{{ replace \"old\" \"new\" .value }}",
+		Description: "Replaces all occurrences of old by new in the value",
+		fn:          func(old, new string, v any) string { return strings.ReplaceAll(toString(v), old, new) },
+	},
+	{
+		Name:        "indent",
+		Usage:       "{{ indent 4 .value }}",
+		Description: "Indents every line of the value by the given number of spaces",
+		fn:          indent,
+	},
+	{
+		Name:        "b64enc",
+		Usage:       "{{ b64enc .value }}",
+		Description: "Encodes the value as standard base64",
+		fn:          func(v any) string { return base64.StdEncoding.EncodeToString([]byte(toString(v))) },
+	},
+}
+
+// FuncMap returns the Functions as a template.FuncMap
+func FuncMap() templ.FuncMap {
+	m := make(templ.FuncMap, len(Functions))
+	for _, f := range Functions {
+		m[f.Name] = f.fn
+	}
+	return m
+}
+
+// GenerateFunctionDocumentation returns the documentation of all Functions as JSON
+func GenerateFunctionDocumentation() ([]byte, error) {
+	b, err := json.MarshalIndent(Functions, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate documentation of template functions: %w", err)
+	}
+	return b, nil
+}
+
+func toString(v any) string {
+	if v == nil {
+		return ""
+	}
+	return fmt.Sprintf("%v", v)
+}
+
+func toJSON(v any) (string, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
+
+func jsonEscape(v any) (string, error) {
+	b, err := json.Marshal(toString(v))
+	if err != nil {
+		return "", err
+	}
+	return string(b[1 : len(b)-1]), nil
+}
+
+func defaultValue(def any, v any) any {
+	if v == nil {
+		return def
+	}
+	if rv := reflect.ValueOf(v); rv.IsZero() || ((rv.Kind() == reflect.Slice || rv.Kind() == reflect.Map) && rv.Len() == 0) {
+		return def
+	}
+	return v
+}
+
+func join(sep string, list any) (string, error) {
+	rv := reflect.ValueOf(list)
+	if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+		return "", fmt.Errorf("join expects a list, but got %T", list)
+	}
+
+	elems := make([]string, rv.Len())
+	for i := range rv.Len() {
+		elems[i] = toString(rv.Index(i).Interface())
+	}
+	return strings.Join(elems, sep), nil
+}
+
+func indent(spaces int, v any) string {
+	prefix := strings.Repeat(" ", spaces)
+	return prefix + strings.ReplaceAll(toString(v), "\n", "\n"+prefix)
+}
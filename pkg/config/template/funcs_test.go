@@ -0,0 +1,78 @@
+//go:build unit
+
+// @license
+// Copyright 2024 Dynatrace LLC
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package template
+
+import (
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestRender_Functions(t *testing.T) {
+	properties := map[string]interface{}{
+		"name":   "My Dashboard",
+		"quoted": `say "hi"`,
+		"empty":  "",
+		"list":   []interface{}{"a", "b", 3},
+		"object": map[string]interface{}{"key": "value"},
+		"text":   "line1\nline2",
+	}
+
+	tests := []struct {
+		template string
+		want     string
+	}{
+		{`{{ toJson .object }}`, `{"key":"value"}`},
+		{`{{ toJson .list }}`, `["a","b",3]`},
+		{`{{ jsonEscape .quoted }}`, `say \"hi\"`},
+		{`{{ default "fallback" .empty }}`, `fallback`},
+		{`{{ default "fallback" .name }}`, `My Dashboard`},
+		{`{{ join ", " .list }}`, `a, b, 3`},
+		{`{{ lower .name }}`, `my dashboard`},
+		{`{{ upper .name }}`, `MY DASHBOARD`},
+		{`{{ replace " " "-" .name }}`, `My-Dashboard`},
+		{`{{ indent 2 .text }}`, "  line1\n  line2"},
+		{`{{ b64enc .name }}`, `TXkgRGFzaGJvYXJk`},
+		{`{{ .name | lower | replace " " "_" }}`, `my_dashboard`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.template, func(t *testing.T) {
+			got, err := Render(&InMemoryTemplate{content: tt.template}, properties)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestRender_JoinFailsForNonList(t *testing.T) {
+	_, err := Render(&InMemoryTemplate{content: `{{ join "," .name }}`}, map[string]interface{}{"name": "value"})
+	assert.ErrorContains(t, err, "join expects a list")
+}
+
+func TestGenerateFunctionDocumentation(t *testing.T) {
+	b, err := GenerateFunctionDocumentation()
+	require.NoError(t, err)
+
+	var docs []map[string]string
+	require.NoError(t, json.Unmarshal(b, &docs))
+	require.Len(t, docs, len(Functions))
+	for i, f := range Functions {
+		assert.Equal(t, map[string]string{"name": f.Name, "usage": f.Usage, "description": f.Description}, docs[i])
+	}
+}
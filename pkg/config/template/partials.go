@@ -0,0 +1,89 @@
+// @license
+// Copyright 2024 Dynatrace LLC
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package template
+
+import (
+	"fmt"
+	"github.com/spf13/afero"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// PartialsFolderName is the name of the folder within a project that holds the template partials of the project
+const PartialsFolderName = "_partials"
+
+// includePattern matches includes of partials, e.g. {{ include "tiles/markdown.json" }}
+var includePattern = regexp.MustCompile(`\{\{\s*include\s+"([^"]+)"\s*}}`)
+
+// resolveIncludes replaces all includes in the content of the template at the given path by the content of the
+// included partials. Partials are looked up in the given folders in order, and may include other partials.
+// includeChain holds the paths of all templates including the current one and is used to detect cyclic includes.
+func resolveIncludes(fs afero.Fs, partialFolders []string, path string, content string, includeChain []string) (string, error) {
+	includeChain = append(includeChain, path)
+
+	var errs []error
+	resolved := includePattern.ReplaceAllStringFunc(content, func(include string) string {
+		name := includePattern.FindStringSubmatch(include)[1]
+
+		partialPath, err := findPartial(fs, partialFolders, name)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to include partial %q in %q: %w", name, path, err))
+			return include
+		}
+
+		if slices.Contains(includeChain, partialPath) {
+			errs = append(errs, fmt.Errorf("cyclic include of partials: %s", strings.Join(append(includeChain, partialPath), " -> ")))
+			return include
+		}
+
+		b, err := afero.ReadFile(fs, partialPath)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to read partial %q: %w", partialPath, err))
+			return include
+		}
+
+		partial, err := resolveIncludes(fs, partialFolders, partialPath, string(b), includeChain)
+		if err != nil {
+			errs = append(errs, err)
+			return include
+		}
+		return partial
+	})
+
+	if len(errs) > 0 {
+		return "", errs[0]
+	}
+	return resolved, nil
+}
+
+// findPartial returns the path of the partial with the given name, which is its path relative to a partials folder
+func findPartial(fs afero.Fs, partialFolders []string, name string) (string, error) {
+	name = filepath.FromSlash(name)
+	if !filepath.IsLocal(name) {
+		return "", fmt.Errorf("name must be a path within the partials folder")
+	}
+
+	for _, folder := range partialFolders {
+		p := filepath.Join(folder, name)
+		if exists, err := afero.Exists(fs, p); err != nil {
+			return "", err
+		} else if exists {
+			return p, nil
+		}
+	}
+	return "", fmt.Errorf("partial not found in any of the partials folders %q", partialFolders)
+}
@@ -0,0 +1,100 @@
+//go:build unit
+
+// @license
+// Copyright 2024 Dynatrace LLC
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package template_test
+
+import (
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/template"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"path/filepath"
+	"testing"
+)
+
+func partialsFs(t *testing.T, files map[string]string) afero.Fs {
+	fs := afero.NewMemMapFs()
+	for path, content := range files {
+		require.NoError(t, afero.WriteFile(fs, filepath.FromSlash(path), []byte(content), 0644))
+	}
+	return fs
+}
+
+var partialFolders = []string{filepath.FromSlash("proj/_partials"), "shared"}
+
+func TestLoadTemplateWithPartials(t *testing.T) {
+	fs := partialsFs(t, map[string]string{
+		"proj/dashboard/dashboard.json":      `{"tiles": [{{ include "tiles/markdown.json" }}, {{include "footer.json"}}]}`,
+		"proj/_partials/tiles/markdown.json": `{"name": "{{ .name }}", "text": {{ include "text.json" }}}`,
+		"proj/_partials/text.json":           `"project text"`,
+		"shared/text.json":                   `"shared text"`,
+		"shared/footer.json":                 `{"name": "footer"}`,
+	})
+
+	tmpl, err := template.NewFileTemplateWithPartials(fs, filepath.FromSlash("proj/dashboard/dashboard.json"), partialFolders)
+	require.NoError(t, err)
+
+	content, err := tmpl.Content()
+	require.NoError(t, err)
+	assert.Equal(t, `{"tiles": [{"name": "{{ .name }}", "text": "project text"}, {"name": "footer"}]}`, content)
+
+	rendered, err := template.Render(tmpl, map[string]interface{}{"name": "tile"})
+	require.NoError(t, err)
+	assert.Equal(t, `{"tiles": [{"name": "tile", "text": "project text"}, {"name": "footer"}]}`, rendered, "partials must be rendered with the properties of the template")
+}
+
+func TestLoadTemplateWithPartials_Errors(t *testing.T) {
+	tests := []struct {
+		name  string
+		files map[string]string
+		want  string
+	}{
+		{
+			"missing partial",
+			map[string]string{"proj/t.json": `{{ include "missing.json" }}`},
+			`failed to include partial "missing.json" in "proj/t.json": partial not found`,
+		},
+		{
+			"partial outside of partials folder",
+			map[string]string{"proj/t.json": `{{ include "../t.json" }}`},
+			"name must be a path within the partials folder",
+		},
+		{
+			"cyclic include",
+			map[string]string{
+				"proj/t.json":           `{{ include "a.json" }}`,
+				"proj/_partials/a.json": `{{ include "b.json" }}`,
+				"shared/b.json":         `{{ include "a.json" }}`,
+			},
+			"cyclic include of partials: proj/t.json -> proj/_partials/a.json -> shared/b.json -> proj/_partials/a.json",
+		},
+		{
+			"partial including itself",
+			map[string]string{
+				"proj/t.json":   `{{ include "a.json" }}`,
+				"shared/a.json": `{{ include "a.json" }}`,
+			},
+			"cyclic include of partials: proj/t.json -> shared/a.json -> shared/a.json",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := template.NewFileTemplateWithPartials(partialsFs(t, tt.files), filepath.FromSlash("proj/t.json"), partialFolders)
+			assert.ErrorContains(t, err, filepath.FromSlash(tt.want))
+		})
+	}
+}
@@ -49,8 +49,8 @@ func Render(template Template, properties map[string]interface{}) (string, error
 	return result.String(), nil
 }
 
-// ParseTemplate creates go Template with the given id from the given string content
-// in any error occurs creating the template, an erro is returned
+// ParseTemplate creates go Template with the given id from the given string content. All Functions can be used in the
+// template. in any error occurs creating the template, an erro is returned
 func ParseTemplate(id, content string) (*templ.Template, error) {
-	return templ.New(id).Option("missingkey=error").Parse(content)
+	return templ.New(id).Option("missingkey=error").Funcs(FuncMap()).Parse(content)
 }
@@ -19,6 +19,7 @@ package delete
 import (
 	"context"
 	"fmt"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/cancellation"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/log"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/log/field"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/api"
@@ -44,10 +45,23 @@ type configurationType = string
 // DeleteEntries is a map of configuration type to slice of delete pointers
 type DeleteEntries = map[configurationType][]pointer.DeletePointer
 
-// Configs removes all given entriesToDelete from the Dynatrace environment the given client connects to
+// Configs removes all given entriesToDelete from the Dynatrace environment the given client connects to.
+// Once ctx is cancelled, the deletion of the current type is given cancellation.DefaultGracePeriod to finish, but no
+// further types are deleted.
 func Configs(ctx context.Context, clients ClientSet, apis api.APIs, automationResources map[string]config.AutomationResource, entriesToDelete DeleteEntries) error {
+	cancelled := ctx.Done()
+	ctx, cancel := cancellation.WithGracePeriod(ctx, cancellation.DefaultGracePeriod)
+	defer cancel()
+
 	deleteErrors := 0
 	for entryType, entries := range entriesToDelete {
+		select {
+		case <-cancelled:
+			log.WithCtxFields(ctx).Warn("Deletion was cancelled - remaining configurations are not deleted")
+			return fmt.Errorf("deletion cancelled: %w", context.Canceled)
+		default:
+		}
+
 		if entryType == api.DashboardShareSettings {
 			log.Warn("Classic config of type %s cannot be deleted. Note, that they can be removed by deleting the associated dashboard.", api.DashboardShareSettings)
 			continue
@@ -46,6 +46,24 @@ var automationTypes = map[string]config.AutomationResource{
 	string(config.SchedulingRule):   config.SchedulingRule,
 }
 
+func TestConfigs_NothingIsDeletedOnceCancelled(t *testing.T) {
+	c := dtclient.NewMockClient(gomock.NewController(t))
+	entriesToDelete := DeleteEntries{
+		"builtin:alerting.profile": {
+			{
+				Type:       "builtin:alerting.profile",
+				Identifier: "id1",
+			},
+		},
+	}
+
+	ctx, cancel := context.WithCancel(context.TODO())
+	cancel()
+
+	err := Configs(ctx, ClientSet{Settings: c}, api.NewAPIs(), automationTypes, entriesToDelete)
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
 func TestDeleteSettings_LegacyExternalID(t *testing.T) {
 	t.Run("TestDeleteSettings_LegacyExternalID", func(t *testing.T) {
 		c := dtclient.NewMockClient(gomock.NewController(t))
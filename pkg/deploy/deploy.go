@@ -19,25 +19,37 @@ import (
 	"errors"
 	"fmt"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/cmd/monaco/dynatrace"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/cancellation"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/concurrency"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/log"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/log/field"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/mutlierror"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/api"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/checkpoint"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/client"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/client/dtclient"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/coordinate"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/entities"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/parameter/lookup"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/parameter/secret"
 	deployErrors "github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/deploy/errors"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/deploy/internal/automation"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/deploy/internal/bucket"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/deploy/internal/classic"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/deploy/internal/setting"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/deploy/internal/validate"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/deploy/report"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/graph"
 	project "github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/project/v2"
 	clientErrors "github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/rest"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/state"
 	gonum "gonum.org/v1/gonum/graph"
 	"gonum.org/v1/gonum/graph/simple"
+	"strings"
 	"sync"
+	"sync/atomic"
+	"time"
 )
 
 // DeployConfigsOptions defines additional options used by DeployConfigs
@@ -48,8 +60,45 @@ type DeployConfigsOptions struct {
 	// DryRun states that the deployment shall just run in dry-run mode, meaning
 	// that actual deployment of the configuration to a tenant will be skipped
 	DryRun bool
+	// MaxConcurrentEnvironments defines how many environments are deployed at the same time.
+	// A value <= 0 deploys all environments at once.
+	MaxConcurrentEnvironments int
+	// MaxConcurrentConfigs defines how many configurations are deployed at the same time to a single environment.
+	// A value <= 0 uses DefaultMaxConcurrentConfigs.
+	MaxConcurrentConfigs int
+	// State holds the remote objects configurations were deployed to by previous deployments. Known objects are
+	// updated directly instead of being searched for, and the state is updated with each successfully deployed
+	// configuration. If State is nil, no deployment state is used.
+	State *state.State
+	// Checkpoint records each configuration deployed successfully. Configurations it already holds an entry for are
+	// not deployed again, as long as they render to the same payload. Such configurations are reported as restored.
+	// If Checkpoint is nil, no checkpoint is used. If the Checkpoint autosaves, it is flushed after each environment.
+	Checkpoint *checkpoint.Checkpoint
+	// Report records the result of deploying each configuration. If Report is nil, no results are recorded.
+	Report *report.Recorder
+	// Configs limits the deployment to the configurations matching one of the given patterns, and all configurations
+	// they depend on. If Configs is empty, all configurations are deployed.
+	Configs []coordinate.Pattern
+	// WithDependents states that all configurations depending on a configuration matching Configs are deployed as well.
+	WithDependents bool
+	// SkipUnchanged states that configurations are compared with the objects they are deployed to, and are not
+	// written if the objects are equal to them. Such configurations are reported as unchanged.
+	SkipUnchanged bool
+	// Retries is the number of times configurations that failed to deploy, and all configurations skipped because of
+	// them, are deployed again after all other configurations of their environment were deployed. Configurations are
+	// not retried in dry-runs.
+	Retries int
+	// RetryBackoff is the time waited before the first retry. It doubles with each further retry.
+	RetryBackoff time.Duration
+	// CancellationGracePeriod is the time configurations that are being deployed when the deployment is cancelled are
+	// given to finish. If it is 0, cancellation.DefaultGracePeriod is used.
+	CancellationGracePeriod time.Duration
 }
 
+// DefaultMaxConcurrentConfigs is the number of configurations deployed at the same time to a single environment, if
+// DeployConfigsOptions.MaxConcurrentConfigs is not set
+const DefaultMaxConcurrentConfigs = 10
+
 type ClientSet struct {
 	Classic    dtclient.Client
 	Settings   dtclient.Client
@@ -64,14 +113,53 @@ var DummyClientSet = ClientSet{
 	Bucket:     &bucket.DummyClient{},
 }
 
-var (
-	lock sync.Mutex
+// environmentDeployment holds everything needed to deploy configurations to a single environment
+type environmentDeployment struct {
+	environment string
+	clients     ClientSet
+	// dryRun states that clients do not access the environment, so deploy policies can not be checked
+	dryRun bool
+	// lookupClient is used to look up monitored entities and existing objects. As lookups do not modify the
+	// environment, dry-runs use the client of the environment if one is configured.
+	lookupClient dtclient.Client
+	// state is nil if no deployment state is used
+	state *state.State
+	// report is nil if no results are recorded
+	report *report.Recorder
+	// cancelled is done once the deployment is cancelled and no further configurations shall be deployed
+	cancelled <-chan struct{}
+	// skipUnchanged states that configurations equal to the objects they are deployed to are not written
+	skipUnchanged bool
+	// checkpoint is nil if no checkpoint is used
+	checkpoint *checkpoint.Checkpoint
+	// maxConcurrentConfigs is the number of configurations deployed at the same time
+	maxConcurrentConfigs int
+	// retries is the number of times failed configurations are deployed again, waiting retryBackoff before the first
+	// retry
+	retries      int
+	retryBackoff time.Duration
+	// retry is the number of the current retry, or 0 during the first attempt
+	retry int
+}
 
-	skipError = errors.New("skip error")
-)
+var skipError = errors.New("skip error")
+
+// Deploy deploys all configurations of the given projects to the given environments.
+//
+// Once ctx is cancelled, no further configurations are deployed. Configurations that are being deployed are given
+// DeployConfigsOptions.CancellationGracePeriod to finish, all remaining configurations are reported as cancelled.
+func Deploy(ctx context.Context, projects []project.Project, environmentClients dynatrace.EnvironmentClients, opts DeployConfigsOptions) error {
+	// secrets are loaded once per deployment, even if they are used by many configs and environments
+	ctx = secret.WithCache(ctx)
 
-func Deploy(projects []project.Project, environmentClients dynatrace.EnvironmentClients, opts DeployConfigsOptions) error {
 	g := graph.New(projects, environmentClients.Names())
+	if len(opts.Configs) > 0 {
+		var err error
+		if g, err = selectConfigs(g, opts.Configs, opts.WithDependents); err != nil {
+			return err
+		}
+	}
+
 	deploymentErrors := make(deployErrors.EnvironmentDeploymentErrors)
 
 	if validationErrs := validate.Validate(projects); validationErrs != nil {
@@ -81,36 +169,62 @@ func Deploy(projects []project.Project, environmentClients dynatrace.Environment
 		errors.As(validationErrs, &deploymentErrors)
 	}
 
-	for env, clients := range environmentClients {
-		ctx := createContextWithEnvironment(env)
-		log.WithCtxFields(ctx).Info("Deploying configurations to environment %q...", env.Name)
-
+	sortedConfigsPerEnv := make(map[dynatrace.EnvironmentInfo][]graph.SortedComponent, len(environmentClients))
+	for env := range environmentClients {
 		sortedConfigs, err := g.GetIndependentlySortedConfigs(env.Name)
 		if err != nil {
 			return fmt.Errorf("failed to get independently sorted configs for environment %q: %w", env.Name, err)
 		}
+		sortedConfigsPerEnv[env] = sortedConfigs
+	}
 
-		var clientSet ClientSet
-		if opts.DryRun {
-			clientSet = DummyClientSet
-		} else {
-			clientSet = ClientSet{
-				Classic:    clients.DTClient,
-				Settings:   clients.DTClient,
-				Automation: clients.AutClient,
-				Bucket:     clients.BucketClient,
+	var (
+		errLock sync.Mutex
+		wg      sync.WaitGroup
+		// aborted is set once an environment failed and deployment shall not continue - environments which are
+		// already being deployed are finished, but no further environments are started
+		aborted atomic.Bool
+	)
+
+	limiter := concurrency.NewLimiter(opts.MaxConcurrentEnvironments)
+	for env, clients := range environmentClients {
+		wg.Add(1)
+		limiter.Execute(func() {
+			defer wg.Done()
+			if aborted.Load() {
+				log.WithFields(field.Environment(env.Name, env.Group)).Warn("Skipping deployment to environment %q, as deployment to another environment failed", env.Name)
+				return
 			}
-		}
 
-		if err = deployComponents(ctx, sortedConfigs, clientSet); err != nil {
-			log.WithFields(field.Environment(env.Name, env.Group), field.Error(err)).Error("Deployment failed for environment %q: %v", env.Name, err)
-			deploymentErrors = deploymentErrors.Append(env.Name, err)
-			if !opts.ContinueOnErr && !opts.DryRun {
-				return deploymentErrors
+			if ctx.Err() != nil {
+				log.WithFields(field.Environment(env.Name, env.Group)).Warn("Skipping deployment to environment %q, as the deployment was cancelled", env.Name)
+				d := environmentDeployment{environment: env.Name, report: opts.Report}
+				for _, component := range sortedConfigsPerEnv[env] {
+					d.recordCancelled(component.Graph)
+				}
+				return
 			}
-		} else {
-			log.WithFields(field.Environment(env.Name, env.Group)).Info("Deployment successful for environment %q", env.Name)
+
+			if err := deployEnvironment(ctx, env, clients, sortedConfigsPerEnv[env], opts); err != nil {
+				errLock.Lock()
+				deploymentErrors = deploymentErrors.Append(env.Name, err)
+				errLock.Unlock()
+
+				if !opts.ContinueOnErr && !opts.DryRun {
+					aborted.Store(true)
+				}
+			}
+		})
+	}
+	wg.Wait()
+	limiter.Close()
+
+	if err := ctx.Err(); err != nil {
+		log.Warn("Deployment was cancelled")
+		if len(deploymentErrors) != 0 {
+			return errors.Join(fmt.Errorf("deployment cancelled: %w", err), deploymentErrors)
 		}
+		return fmt.Errorf("deployment cancelled: %w", err)
 	}
 
 	if len(deploymentErrors) != 0 {
@@ -120,99 +234,301 @@ func Deploy(projects []project.Project, environmentClients dynatrace.Environment
 	return nil
 }
 
-func deployComponents(ctx context.Context, components []graph.SortedComponent, clients ClientSet) error {
-	log.WithCtxFields(ctx).Info("Deploying %d independent configuration sets in parallel...", len(components))
-	errCount := 0
-	errChan := make(chan error, len(components))
+// selectConfigs returns the sub-graphs containing only configurations matching one of the given patterns, and the
+// configurations needed to deploy them. It fails if a pattern matches no configuration in any environment.
+func selectConfigs(g graph.ConfigGraphPerEnvironment, patterns []coordinate.Pattern, withDependents bool) (graph.ConfigGraphPerEnvironment, error) {
+	matched := make(map[coordinate.Pattern]bool, len(patterns))
+	selected := g.Select(func(c coordinate.Coordinate) bool {
+		found := false
+		for _, p := range patterns {
+			if p.Matches(c) {
+				matched[p] = true
+				found = true
+			}
+		}
+		return found
+	}, withDependents)
 
-	resolvedEntities := entities.New()
-	// Iterate over components and launch a goroutine for each component deployment.
-	for i := range components {
-		go func(ctx context.Context, component graph.SortedComponent) {
-			errChan <- deployGraph(ctx, component.Graph, clients, resolvedEntities)
-		}(context.WithValue(ctx, log.CtxGraphComponentId{}, log.CtxValGraphComponentId(i)), components[i])
-	}
-
-	for range components {
-		err := <-errChan
-		var deploymentErrs deployErrors.DeploymentErrors
-		if errors.As(err, &deploymentErrs) {
-			errCount += deploymentErrs.ErrorCount
-		} else if err != nil {
-			errCount += 1
+	var unmatched []string
+	for _, p := range patterns {
+		if !matched[p] {
+			unmatched = append(unmatched, p.String())
+		}
+	}
+	if len(unmatched) > 0 {
+		return nil, fmt.Errorf("no configuration matches %s", strings.Join(unmatched, ", "))
+	}
+
+	for env, sub := range selected {
+		log.Info("Selected %d configurations to deploy to environment %q", sub.Nodes().Len(), env)
+	}
+	return selected, nil
+}
+
+func deployEnvironment(ctx context.Context, env dynatrace.EnvironmentInfo, clients *client.ClientSet, sortedConfigs []graph.SortedComponent, opts DeployConfigsOptions) error {
+	cancelled := ctx.Done()
+
+	gracePeriod := opts.CancellationGracePeriod
+	if gracePeriod == 0 {
+		gracePeriod = cancellation.DefaultGracePeriod
+	}
+	// configurations that are already being deployed when the deployment is cancelled are allowed to finish
+	ctx, cancel := cancellation.WithGracePeriod(createContextWithEnvironment(ctx, env), gracePeriod)
+	defer cancel()
+
+	log.WithCtxFields(ctx).Info("Deploying configurations to environment %q...", env.Name)
+
+	d := environmentDeployment{environment: env.Name, report: opts.Report, cancelled: cancelled, maxConcurrentConfigs: opts.MaxConcurrentConfigs}
+	if opts.DryRun {
+		d.clients = DummyClientSet
+		d.dryRun = true
+	} else {
+		d.clients = ClientSet{
+			Classic:    clients.DTClient,
+			Settings:   clients.DTClient,
+			Automation: clients.AutClient,
+			Bucket:     clients.BucketClient,
 		}
+		d.state = opts.State
+		d.skipUnchanged = opts.SkipUnchanged
+		d.retries = opts.Retries
+		d.checkpoint = opts.Checkpoint
+		d.retryBackoff = opts.RetryBackoff
 	}
 
-	close(errChan)
+	d.lookupClient = d.clients.Classic
+	if clients != nil && clients.DTClient != nil {
+		d.lookupClient = clients.DTClient
+	}
 
-	if errCount > 0 {
-		return deployErrors.DeploymentErrors{ErrorCount: errCount}
+	err := deployWithRetries(ctx, sortedConfigs, d)
+	if d.checkpoint != nil {
+		if flushErr := d.checkpoint.Flush(); flushErr != nil {
+			log.WithFields(field.Environment(env.Name, env.Group), field.Error(flushErr)).Warn("Failed to write deployment checkpoint: %v", flushErr)
+		}
+	}
+	if d.isCancelled() {
+		log.WithFields(field.Environment(env.Name, env.Group)).Warn("Deployment to environment %q was cancelled", env.Name)
+		return errors.Join(fmt.Errorf("deployment to environment %q was cancelled", env.Name), err)
+	}
+	if err != nil {
+		log.WithFields(field.Environment(env.Name, env.Group), field.Error(err)).Error("Deployment failed for environment %q: %v", env.Name, err)
+		return err
 	}
 
+	log.WithFields(field.Environment(env.Name, env.Group)).Info("Deployment successful for environment %q", env.Name)
 	return nil
 }
 
-func deployGraph(ctx context.Context, configGraph *simple.DirectedGraph, clients ClientSet, resolvedEntities *entities.EntityMap) error {
-	g := simple.NewDirectedGraph()
-	gonum.Copy(g, configGraph)
+// deployWithRetries deploys all configurations of the given components. Configurations that failed to deploy, and
+// all configurations that were skipped because of them, are deployed again after d.retryBackoff, up to d.retries times.
+// The backoff doubles with each retry.
+func deployWithRetries(ctx context.Context, components []graph.SortedComponent, d environmentDeployment) error {
+	graphs := make([]*simple.DirectedGraph, len(components))
+	for i := range components {
+		graphs[i] = components[i].Graph
+	}
 
-	errCount := 0
+	// entities are shared by all attempts, so that retried configurations can reference configurations deployed before
+	resolvedEntities := entities.New()
+
+	failed, err := deployComponents(ctx, graphs, d, resolvedEntities)
+	for d.retry = 1; d.retry <= d.retries && len(failed) > 0; d.retry++ {
+		backoff := d.retryBackoff << (d.retry - 1)
+		log.WithCtxFields(ctx).Warn("Retrying deployment of %d configurations in %v (retry %d of %d)", len(failed), backoff, d.retry, d.retries)
+
+		if !d.wait(backoff) {
+			// failed configurations keep their failed result, as they were not retried
+			break
+		}
+
+		retryGraphs := make([]*simple.DirectedGraph, 0, len(graphs))
+		for _, g := range graphs {
+			if sub := subGraph(g, failed); sub.Nodes().Len() > 0 {
+				retryGraphs = append(retryGraphs, sub)
+			}
+		}
+		failed, err = deployComponents(ctx, retryGraphs, d, resolvedEntities)
+	}
+	return err
+}
+
+// subGraph returns the graph containing the given nodes of g, and the edges between them
+func subGraph(g *simple.DirectedGraph, nodes map[int64]bool) *simple.DirectedGraph {
+	sub := simple.NewDirectedGraph()
+	for _, n := range gonum.NodesOf(g.Nodes()) {
+		if nodes[n.ID()] {
+			sub.AddNode(n)
+		}
+	}
+	for _, e := range gonum.EdgesOf(g.Edges()) {
+		if nodes[e.From().ID()] && nodes[e.To().ID()] {
+			sub.SetEdge(e)
+		}
+	}
+	return sub
+}
 
-	errChan := make(chan error)
-	for configGraph.Nodes().Len() != 0 {
-		roots := graph.Roots(configGraph)
+// deployComponents deploys all configurations of the given graphs using a pool of d.maxConcurrentConfigs workers.
+// A configuration is handed to the workers as soon as all configurations it depends on are deployed, regardless of
+// the progress of any other configuration. The IDs of all configurations that failed to deploy, or were skipped
+// because a configuration they depend on failed, are returned.
+func deployComponents(ctx context.Context, components []*simple.DirectedGraph, d environmentDeployment, resolvedEntities *entities.EntityMap) (map[int64]bool, error) {
+	workers := d.maxConcurrentConfigs
+	if workers <= 0 {
+		workers = DefaultMaxConcurrentConfigs
+	}
+
+	var ready []scheduledNode
+	remaining := make([]*simple.DirectedGraph, len(components))
+	for i := range components {
+		// deployed configurations are removed from the graph, which must not change the given one
+		remaining[i] = simple.NewDirectedGraph()
+		gonum.Copy(remaining[i], components[i])
 
-		for _, root := range roots {
-			node := root.(graph.ConfigNode)
-			go func(ctx context.Context, node graph.ConfigNode) {
-				errChan <- deployNode(ctx, node, configGraph, clients, resolvedEntities)
-			}(context.WithValue(ctx, log.CtxKeyCoord{}, node.Config.Coordinate), node)
+		componentCtx := context.WithValue(ctx, log.CtxGraphComponentId{}, log.CtxValGraphComponentId(i))
+		for _, root := range graph.Roots(remaining[i]) {
+			ready = append(ready, newScheduledNode(componentCtx, root.(graph.ConfigNode), remaining[i]))
 		}
+	}
+
+	log.WithCtxFields(ctx).Info("Deploying %d independent configuration sets using %d workers...", len(components), workers)
 
-		for range roots {
-			err := <-errChan
-			if err != nil {
-				errCount += 1
+	jobs := make(chan scheduledNode, workers)
+	results := make(chan scheduledResult, workers)
+	defer close(jobs)
+
+	for range workers {
+		go func() {
+			for n := range jobs {
+				results <- scheduledResult{node: n, err: deployNode(n.ctx, n.node, d, resolvedEntities)}
 			}
+		}()
+	}
+
+	failed := make(map[int64]bool)
+	errCount := 0
+	inProgress := 0
+	for {
+		for len(ready) > 0 && inProgress < workers && !d.isCancelled() {
+			jobs <- ready[0]
+			ready = ready[1:]
+			inProgress++
+		}
+		if inProgress == 0 {
+			break
 		}
 
-		// since all subroutines are done, we need not to lock here
-		for _, root := range roots {
-			configGraph.RemoveNode(root.ID())
+		res := <-results
+		inProgress--
+
+		if res.err != nil && !errors.Is(res.err, skipError) {
+			errCount++
+			failed[res.node.node.ID()] = true
+		}
+
+		nowReady, skipped := res.node.finish(res.err, d)
+		ready = append(ready, nowReady...)
+		if failed[res.node.node.ID()] {
+			for _, n := range skipped {
+				failed[n.ID()] = true
+			}
 		}
 	}
 
-	close(errChan)
+	if d.isCancelled() {
+		for _, c := range remaining {
+			d.recordCancelled(c)
+		}
+	}
 
 	if errCount > 0 {
-		return deployErrors.DeploymentErrors{ErrorCount: errCount}
+		return failed, deployErrors.DeploymentErrors{ErrorCount: errCount}
 	}
 
-	return nil
+	return failed, nil
 }
 
-func deployNode(ctx context.Context, n graph.ConfigNode, configGraph graph.ConfigGraph, clients ClientSet, resolvedEntities *entities.EntityMap) error {
-	resolvedEntity, err := deployConfig(ctx, n.Config, clients, resolvedEntities)
+// scheduledNode is a configuration ready to be deployed, as all configurations it depends on are deployed
+type scheduledNode struct {
+	ctx  context.Context
+	node graph.ConfigNode
+	// component is the graph containing the node. Deployed nodes are removed from it, so that a node is ready as soon
+	// as it has no incoming edges anymore. It must only be modified by the scheduler.
+	component *simple.DirectedGraph
+	// componentCtx is the context the node was scheduled with, without node specific values
+	componentCtx context.Context
+}
 
-	if err != nil {
-		failed := !errors.Is(err, skipError)
+func newScheduledNode(componentCtx context.Context, n graph.ConfigNode, component *simple.DirectedGraph) scheduledNode {
+	return scheduledNode{
+		ctx:          context.WithValue(componentCtx, log.CtxKeyCoord{}, n.Config.Coordinate),
+		node:         n,
+		component:    component,
+		componentCtx: componentCtx,
+	}
+}
 
-		lock.Lock()
-		removeChildren(ctx, n, n, configGraph, failed)
-		lock.Unlock()
+type scheduledResult struct {
+	node scheduledNode
+	// err is nil if the node was deployed, skipError if it was skipped
+	err error
+}
 
-		if failed {
-			return err
+// finish removes the deployed node from its component and returns all nodes that became ready by that. If the node was
+// not deployed, all nodes depending on it are skipped and removed as well, and returned as skipped.
+func (n scheduledNode) finish(deployErr error, d environmentDeployment) (ready []scheduledNode, skipped []graph.ConfigNode) {
+	if deployErr != nil {
+		skipped = removeChildren(n.ctx, n.node, n.node, n.component, !errors.Is(deployErr, skipError), d)
+		n.component.RemoveNode(n.node.ID())
+		return nil, skipped
+	}
+
+	dependents := gonum.NodesOf(n.component.From(n.node.ID()))
+	n.component.RemoveNode(n.node.ID())
+
+	for _, dependent := range dependents {
+		if n.component.To(dependent.ID()).Len() == 0 {
+			ready = append(ready, newScheduledNode(n.componentCtx, dependent.(graph.ConfigNode), n.component))
+		}
+	}
+	return ready, nil
+}
+
+// deployNode deploys the config of the given node and records the result. It returns skipError if the config is
+// marked as skipped, or its deploy policy prevents creating its object.
+func deployNode(ctx context.Context, n graph.ConfigNode, d environmentDeployment, resolvedEntities *entities.EntityMap) error {
+	start := time.Now()
+	resolvedEntity, status, err := deployConfig(ctx, n.Config, d, resolvedEntities)
+	duration := time.Since(start)
+
+	if err != nil {
+		if errors.Is(err, errObjectMissing) {
+			d.recordResult(report.Record{Coordinate: n.Config.Coordinate, Status: report.StatusSkipped, SkipReason: deployPolicySkipReason(n.Config), Retries: d.retry})
+		} else if errors.Is(err, skipError) {
+			d.recordResult(report.Record{Coordinate: n.Config.Coordinate, Status: report.StatusSkipped, SkipReason: "configuration is marked as skipped", Retries: d.retry})
+		} else {
+			d.recordResult(report.Record{Coordinate: n.Config.Coordinate, Status: report.StatusFailed, Duration: duration, Error: report.NewError(err), Retries: d.retry})
 		}
-		return nil
+		return err
 	}
 
 	resolvedEntities.Put(resolvedEntity)
-	log.WithCtxFields(ctx).WithFields(field.StatusDeployed()).Info("Deployment successful")
+	remoteID, _ := resolvedEntity.Properties[config.IdParameter].(string)
+	rec := report.Record{Coordinate: n.Config.Coordinate, Status: status, RemoteId: remoteID, Duration: duration, Retries: d.retry}
+	if status == report.StatusSkipped {
+		rec.SkipReason = deployPolicySkipReason(n.Config)
+	}
+	d.recordResult(rec)
+	if status == report.StatusDeployed {
+		log.WithCtxFields(ctx).WithFields(field.StatusDeployed()).Info("Deployment successful")
+	}
 	return nil
 }
 
-func removeChildren(ctx context.Context, parent, root graph.ConfigNode, configGraph graph.ConfigGraph, failed bool) {
+// removeChildren skips and removes all configurations depending on parent from configGraph, and returns them
+func removeChildren(ctx context.Context, parent, root graph.ConfigNode, configGraph graph.ConfigGraph, failed bool, d environmentDeployment) []graph.ConfigNode {
+	var removed []graph.ConfigNode
 
 	children := configGraph.From(parent.ID())
 	for children.Next() {
@@ -238,46 +554,117 @@ func removeChildren(ctx context.Context, parent, root graph.ConfigNode, configGr
 			l.Warn("Skipping deployment of %v, as it depends on %v which %s", childCfg.Coordinate, parent.Config.Coordinate, reason)
 		}
 
-		removeChildren(ctx, child, root, configGraph, failed)
+		rootCause := root.Config.Coordinate
+		d.recordResult(report.Record{
+			Coordinate: childCfg.Coordinate,
+			Status:     report.StatusSkipped,
+			SkipReason: fmt.Sprintf("depends on %v which was not deployed, as %v %s", parent.Config.Coordinate, rootCause, reason),
+			RootCause:  &rootCause,
+			Retries:    d.retry,
+		})
+
+		removed = append(removed, child)
+		removed = append(removed, removeChildren(ctx, child, root, configGraph, failed, d)...)
 
 		configGraph.RemoveNode(child.ID())
 	}
+	return removed
 }
 
-func deployConfig(ctx context.Context, c *config.Config, clients ClientSet, resolvedEntities config.EntityLookup) (entities.ResolvedEntity, error) {
+// deployConfig deploys the given config. The returned status is report.StatusDeployed if the config was written,
+// report.StatusUnchanged if it was not written as it is equal to the object it is deployed to, or
+// report.StatusRestored if it was not written as it was already deployed by the deployment that is resumed.
+// If the deploy policy of the config prevents updating its existing object, the status is report.StatusSkipped and the
+// entity of the existing object is returned. If the policy prevents creating the object, errObjectMissing is returned.
+// Configs defining a config.Merge are merged into their existing object before they are written.
+func deployConfig(ctx context.Context, c *config.Config, d environmentDeployment, resolvedEntities config.EntityLookup) (resolvedEntity entities.ResolvedEntity, status report.Status, err error) {
 	if c.Skip {
 		log.WithCtxFields(ctx).WithFields(field.StatusDeploymentSkipped()).Info("Skipping deployment of config")
-		return entities.ResolvedEntity{}, skipError //fake resolved entity that "old" deploy creates is never needed, as we don't even try to deploy dependencies of skipped configs (so no reference will ever be attempted to resolve)
+		return entities.ResolvedEntity{}, "", skipError //fake resolved entity that "old" deploy creates is never needed, as we don't even try to deploy dependencies of skipped configs (so no reference will ever be attempted to resolve)
 	}
 
-	properties, errs := c.ResolveParameterValues(resolvedEntities)
+	properties, errs := c.ResolveParameterValues(ctx, newEnvironmentLookup(ctx, resolvedEntities, d.lookupClient))
 	if len(errs) > 0 {
 		err := mutlierror.New(errs...)
 		log.WithCtxFields(ctx).WithFields(field.Error(err), field.StatusDeploymentFailed()).Error("Invalid configuration - failed to resolve parameter values: %v", err)
-		return entities.ResolvedEntity{}, err
+		return entities.ResolvedEntity{}, "", err
+	}
+
+	if d.dryRun {
+		for name, p := range c.Parameters {
+			if p.GetType() == lookup.LookupParameterType {
+				log.WithCtxFields(ctx).Info("Lookup parameter %q resolved to %v", name, properties[name])
+			}
+		}
 	}
 
 	renderedConfig, err := c.Render(properties)
 	if err != nil {
 		log.WithCtxFields(ctx).WithFields(field.Error(err), field.StatusDeploymentFailed()).Error("Invalid configuration - failed to render JSON template: %v", err)
-		return entities.ResolvedEntity{}, err
+		return entities.ResolvedEntity{}, "", err
+	}
+
+	if restored, found := d.restoreFromCheckpoint(ctx, c, renderedConfig); found {
+		return restored, report.StatusRestored, nil
+	}
+
+	knownID := d.knownRemoteID(c)
+
+	// the payload written differs from the rendered config if the config is merged into its existing object
+	payload := renderedConfig
+	if d.dryRun {
+		if policy := c.EffectiveDeployPolicy(); policy != config.DeployPolicyUpsert {
+			log.WithCtxFields(ctx).WithFields(field.F("deployPolicy", policy)).Info("Deploy policy %q is not checked in dry-run", policy)
+		}
+	} else {
+		existing, exists, err := checkDeployPolicy(ctx, c, d.clients, properties, knownID)
+		if errors.Is(err, errObjectMissing) {
+			log.WithCtxFields(ctx).WithFields(field.F("deployPolicy", c.EffectiveDeployPolicy()), field.StatusDeploymentSkipped()).Info("Skipping deployment of config, as its object does not exist and its deploy policy only allows updates")
+			return entities.ResolvedEntity{}, "", err
+		}
+		if err != nil {
+			log.WithCtxFields(ctx).WithFields(field.Error(err), field.StatusDeploymentFailed()).Error("Failed to check deploy policy: %v", err)
+			return entities.ResolvedEntity{}, "", err
+		}
+		if exists {
+			log.WithCtxFields(ctx).WithFields(field.F("deployPolicy", c.EffectiveDeployPolicy()), field.StatusDeploymentSkipped()).Info("Skipping deployment of config, as its object already exists and its deploy policy only allows creating it")
+			d.rememberDeployment(c, existing, renderedConfig)
+			return existing, report.StatusSkipped, nil
+		}
+
+		payload, err = mergeWithRemote(ctx, c, d.clients, properties, renderedConfig, knownID)
+		if err != nil {
+			log.WithCtxFields(ctx).WithFields(field.Error(err), field.StatusDeploymentFailed()).Error("Failed to merge config into its existing object: %v", err)
+			return entities.ResolvedEntity{}, "", err
+		}
+	}
+
+	if d.skipUnchanged {
+		existing, isUnchanged, err := findUnchanged(ctx, c, d.clients, properties, payload, knownID)
+		if err != nil {
+			log.WithCtxFields(ctx).WithFields(field.Error(err)).Warn("Failed to compare config with the object it is deployed to - deploying it: %v", err)
+		} else if isUnchanged {
+			log.WithCtxFields(ctx).WithFields(field.StatusDeploymentUnchanged()).Info("Skipping deployment of config, as it is unchanged")
+			d.rememberDeployment(c, existing, renderedConfig)
+			return existing, report.StatusUnchanged, nil
+		}
 	}
 
 	log.WithCtxFields(ctx).WithFields(field.StatusDeploying()).Info("Deploying config")
-	var resolvedEntity entities.ResolvedEntity
 	var deployErr error
 	switch c.Type.(type) {
 	case config.SettingsType:
-		resolvedEntity, deployErr = setting.Deploy(ctx, clients.Settings, properties, renderedConfig, c)
+		resolvedEntity, deployErr = setting.DeployToKnownID(ctx, d.clients.Settings, properties, payload, c, knownID)
 
 	case config.ClassicApiType:
-		resolvedEntity, deployErr = classic.Deploy(ctx, clients.Classic, api.NewAPIs(), properties, renderedConfig, c)
+		resolvedEntity, deployErr = classic.DeployToKnownID(ctx, d.clients.Classic, api.NewAPIs(), properties, payload, c, knownID)
 
+	// automation objects and buckets are deployed with IDs derived from their coordinate, which are always known
 	case config.AutomationType:
-		resolvedEntity, deployErr = automation.Deploy(ctx, clients.Automation, properties, renderedConfig, c)
+		resolvedEntity, deployErr = automation.Deploy(ctx, d.clients.Automation, properties, renderedConfig, c)
 
 	case config.BucketType:
-		resolvedEntity, deployErr = bucket.Deploy(ctx, clients.Bucket, properties, renderedConfig, c)
+		resolvedEntity, deployErr = bucket.Deploy(ctx, d.clients.Bucket, properties, renderedConfig, c)
 
 	default:
 		deployErr = fmt.Errorf("unknown config-type (ID: %q)", c.Type.ID())
@@ -287,13 +674,127 @@ func deployConfig(ctx context.Context, c *config.Config, clients ClientSet, reso
 		var responseErr clientErrors.RespError
 		if errors.As(deployErr, &responseErr) {
 			logResponseError(ctx, responseErr)
-			return entities.ResolvedEntity{}, responseErr
+			return entities.ResolvedEntity{}, "", responseErr
 		}
 
 		log.WithCtxFields(ctx).WithFields(field.Error(deployErr)).Error("Deployment failed - Monaco Error: %v", deployErr)
-		return entities.ResolvedEntity{}, deployErr
+		return entities.ResolvedEntity{}, "", deployErr
+	}
+
+	d.rememberDeployment(c, resolvedEntity, renderedConfig)
+	return resolvedEntity, report.StatusDeployed, nil
+}
+
+// knownRemoteID returns the ID of the object the given config was deployed to by a previous deployment, or an empty
+// string if it is not known
+func (d environmentDeployment) knownRemoteID(c *config.Config) string {
+	return knownRemoteID(d.state, d.environment, c)
+}
+
+// knownRemoteID returns the ID of the object the given config was deployed to in the given environment according to
+// the deployment state, or an empty string if the state is nil or does not know the config
+func knownRemoteID(s *state.State, environment string, c *config.Config) string {
+	if s == nil {
+		return ""
+	}
+
+	entry, found := s.Get(environment, c.Coordinate)
+	if !found || entry.ConfigType != string(c.Type.ID()) {
+		return ""
+	}
+	return entry.RemoteId
+}
+
+// restoreFromCheckpoint returns the entity the given config was deployed as by the deployment that is resumed. The
+// returned bool is false if the config was not deployed, or its payload changed since and it needs to be deployed again.
+func (d environmentDeployment) restoreFromCheckpoint(ctx context.Context, c *config.Config, renderedConfig string) (entities.ResolvedEntity, bool) {
+	if d.checkpoint == nil {
+		return entities.ResolvedEntity{}, false
+	}
+
+	entry, found := d.checkpoint.Get(d.environment, c.Coordinate)
+	if !found {
+		return entities.ResolvedEntity{}, false
+	}
+	if entry.PayloadHash != state.HashPayload([]byte(renderedConfig)) {
+		log.WithCtxFields(ctx).Info("Deploying config again, as its payload changed since it was checkpointed")
+		d.checkpoint.Remove(d.environment, c.Coordinate)
+		return entities.ResolvedEntity{}, false
+	}
+
+	log.WithCtxFields(ctx).WithFields(field.StatusDeploymentRestored()).Info("Skipping deployment of config, as it was already deployed according to the checkpoint")
+	return entry.ResolvedEntity(), true
+}
+
+// rememberDeployment stores the object the given config was deployed to in the deployment state and the checkpoint
+func (d environmentDeployment) rememberDeployment(c *config.Config, resolvedEntity entities.ResolvedEntity, renderedConfig string) {
+	if d.checkpoint != nil {
+		d.checkpoint.Set(d.environment, checkpoint.Entry{
+			Coordinate:  c.Coordinate,
+			EntityName:  resolvedEntity.EntityName,
+			Properties:  resolvedEntity.Properties,
+			PayloadHash: state.HashPayload([]byte(renderedConfig)),
+		})
+	}
+
+	if d.state == nil {
+		return
 	}
-	return resolvedEntity, nil
+
+	id, ok := resolvedEntity.Properties[config.IdParameter].(string)
+	if !ok || id == "" {
+		return
+	}
+
+	d.state.Set(d.environment, state.Entry{
+		Coordinate:  c.Coordinate,
+		ConfigType:  string(c.Type.ID()),
+		RemoteId:    id,
+		PayloadHash: state.HashPayload([]byte(renderedConfig)),
+	})
+}
+
+// isCancelled returns whether the deployment was cancelled, and no further configurations shall be deployed
+func (d environmentDeployment) isCancelled() bool {
+	select {
+	case <-d.cancelled:
+		return true
+	default:
+		return false
+	}
+}
+
+// wait waits for the given duration. It returns false if the deployment was cancelled while waiting.
+func (d environmentDeployment) wait(duration time.Duration) bool {
+	timer := time.NewTimer(duration)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return true
+	case <-d.cancelled:
+		return false
+	}
+}
+
+// recordCancelled logs and reports all configurations of the given graph as cancelled
+func (d environmentDeployment) recordCancelled(configGraph gonum.Graph) {
+	nodes := configGraph.Nodes()
+	for nodes.Next() {
+		c := nodes.Node().(graph.ConfigNode).Config
+		log.WithFields(field.Environment(d.environment, c.Group), field.Coordinate(c.Coordinate), field.StatusDeploymentCancelled()).Warn("Deployment of %v was cancelled", c.Coordinate)
+		d.recordResult(report.Record{Coordinate: c.Coordinate, Status: report.StatusCancelled})
+	}
+}
+
+// recordResult adds the given result of a config deployed to the environment to the report
+func (d environmentDeployment) recordResult(rec report.Record) {
+	if d.report == nil {
+		return
+	}
+
+	rec.Environment = d.environment
+	d.report.Add(rec)
 }
 
 // logResponseError prints user-friendly messages based on the response errors status
@@ -311,6 +812,6 @@ func logResponseError(ctx context.Context, responseErr clientErrors.RespError) {
 	log.WithCtxFields(ctx).WithFields(field.Error(responseErr), field.StatusDeploymentFailed()).Error("Deployment failed - Dynatrace API call unsuccessful: %v", responseErr)
 }
 
-func createContextWithEnvironment(env dynatrace.EnvironmentInfo) context.Context {
-	return context.WithValue(context.TODO(), log.CtxKeyEnv{}, log.CtxValEnv{Name: env.Name, Group: env.Group})
+func createContextWithEnvironment(ctx context.Context, env dynatrace.EnvironmentInfo) context.Context {
+	return context.WithValue(ctx, log.CtxKeyEnv{}, log.CtxValEnv{Name: env.Name, Group: env.Group})
 }
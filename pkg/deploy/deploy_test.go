@@ -17,25 +17,36 @@
 package deploy_test
 
 import (
+	"context"
 	"fmt"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/cmd/monaco/dynatrace"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/idutils"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/api"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/checkpoint"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/client"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/client/dtclient"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/coordinate"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/parameter"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/parameter/entity"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/parameter/environment"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/parameter/lookup"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/parameter/reference"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/parameter/value"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/template"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/deploy"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/deploy/errors"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/deploy/internal/testutils"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/deploy/report"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/graph"
 	project "github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/project/v2"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/state"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"go.uber.org/mock/gomock"
+	"sync"
 	"testing"
+	"time"
 )
 
 var dashboardApi = api.API{ID: "dashboard", URLPath: "dashboard", DeprecatedBy: "dashboard-v2"}
@@ -99,7 +110,7 @@ func TestDeployConfigGraph_SingleConfig(t *testing.T) {
 		dynatrace.EnvironmentInfo{Name: "env"}: clientSet,
 	}
 
-	errors := deploy.Deploy(p, c, deploy.DeployConfigsOptions{})
+	errors := deploy.Deploy(context.TODO(), p, c, deploy.DeployConfigsOptions{})
 
 	assert.Emptyf(t, errors, "errors: %v", errors)
 
@@ -162,7 +173,7 @@ func TestDeployConfigGraph_SettingShouldFailUpsert(t *testing.T) {
 		dynatrace.EnvironmentInfo{Name: "env"}: &client.ClientSet{DTClient: c},
 	}
 
-	errors := deploy.Deploy(p, clients, deploy.DeployConfigsOptions{})
+	errors := deploy.Deploy(context.TODO(), p, clients, deploy.DeployConfigsOptions{})
 	assert.NotEmpty(t, errors)
 }
 
@@ -186,7 +197,7 @@ func TestDeployConfigGraph_DoesNotFailOnEmptyConfigs(t *testing.T) {
 		dynatrace.EnvironmentInfo{Name: "env"}: &clientSet,
 	}
 
-	errors := deploy.Deploy(p, c, deploy.DeployConfigsOptions{})
+	errors := deploy.Deploy(context.TODO(), p, c, deploy.DeployConfigsOptions{})
 	assert.Emptyf(t, errors, "there should be no errors (errors: %v)", errors)
 }
 
@@ -201,7 +212,7 @@ func TestDeployConfigGraph_DoesNotFailOnEmptyProject(t *testing.T) {
 		dynatrace.EnvironmentInfo{Name: "env"}: &clientSet,
 	}
 
-	errors := deploy.Deploy(p, c, deploy.DeployConfigsOptions{})
+	errors := deploy.Deploy(context.TODO(), p, c, deploy.DeployConfigsOptions{})
 	assert.Emptyf(t, errors, "there should be no errors (errors: %v)", errors)
 }
 
@@ -213,7 +224,7 @@ func TestDeployConfigGraph_DoesNotFailNilProject(t *testing.T) {
 		dynatrace.EnvironmentInfo{Name: "env"}: &clientSet,
 	}
 
-	errors := deploy.Deploy(nil, c, deploy.DeployConfigsOptions{})
+	errors := deploy.Deploy(context.TODO(), nil, c, deploy.DeployConfigsOptions{})
 	assert.Emptyf(t, errors, "there should be no errors (errors: %v)", errors)
 }
 
@@ -239,7 +250,7 @@ func TestDeployConfigGraph_DoesNotDeploySkippedConfig(t *testing.T) {
 		dynatrace.EnvironmentInfo{Name: "env"}: &clientSet,
 	}
 
-	errors := deploy.Deploy(p, c, deploy.DeployConfigsOptions{})
+	errors := deploy.Deploy(context.TODO(), p, c, deploy.DeployConfigsOptions{})
 	assert.Emptyf(t, errors, "there should be no errors (errors: %v)", errors)
 	createdEntities, found := dummyClient.GetEntries(api.NewAPIs()["dashboard"])
 	assert.False(t, found, "expected NO entries for dashboard API to exist")
@@ -288,7 +299,7 @@ func TestDeployConfigGraph_DeploysSetting(t *testing.T) {
 		dynatrace.EnvironmentInfo{Name: "env"}: &clientSet,
 	}
 
-	errors := deploy.Deploy(p, clients, deploy.DeployConfigsOptions{})
+	errors := deploy.Deploy(context.TODO(), p, clients, deploy.DeployConfigsOptions{})
 	assert.Emptyf(t, errors, "there should be no errors (errors: %v)", errors)
 }
 
@@ -334,7 +345,7 @@ func TestDeployConfigsTargetingClassicConfigUnique(t *testing.T) {
 		dynatrace.EnvironmentInfo{Name: "env"}: &clientSet,
 	}
 
-	errors := deploy.Deploy(p, clients, deploy.DeployConfigsOptions{})
+	errors := deploy.Deploy(context.TODO(), p, clients, deploy.DeployConfigsOptions{})
 	assert.Emptyf(t, errors, "there should be no errors (errors: %v)", errors)
 }
 
@@ -380,7 +391,7 @@ func TestDeployConfigsTargetingClassicConfigNonUniqueWithExistingCfgsOfSameName(
 		dynatrace.EnvironmentInfo{Name: "env"}: &clientSet,
 	}
 
-	errors := deploy.Deploy(p, clients, deploy.DeployConfigsOptions{})
+	errors := deploy.Deploy(context.TODO(), p, clients, deploy.DeployConfigsOptions{})
 	assert.Emptyf(t, errors, "there should be no errors (errors: %v)", errors)
 }
 
@@ -435,7 +446,7 @@ func TestDeployConfigsWithDeploymentErrors(t *testing.T) {
 
 	t.Run("deployment error - always continues on error", func(t *testing.T) {
 
-		err := deploy.Deploy(p, c, deploy.DeployConfigsOptions{}) // continues even without option set
+		err := deploy.Deploy(context.TODO(), p, c, deploy.DeployConfigsOptions{}) // continues even without option set
 		assert.Error(t, err)
 
 		envErrs := make(errors.EnvironmentDeploymentErrors)
@@ -560,7 +571,7 @@ func TestDeployConfigGraph_DoesNotDeployConfigsDependingOnSkippedConfigs(t *test
 		dynatrace.EnvironmentInfo{Name: environmentName}: &clientSet,
 	}
 
-	errs := deploy.Deploy(projects, clients, deploy.DeployConfigsOptions{})
+	errs := deploy.Deploy(context.TODO(), projects, clients, deploy.DeployConfigsOptions{})
 	assert.NoError(t, errs)
 	assert.Zero(t, dummyClient.CreatedObjects())
 }
@@ -674,7 +685,7 @@ func TestDeployConfigGraph_DeploysIndependentConfigurations(t *testing.T) {
 		dynatrace.EnvironmentInfo{Name: environmentName}: &clientSet,
 	}
 
-	errs := deploy.Deploy(projects, clients, deploy.DeployConfigsOptions{})
+	errs := deploy.Deploy(context.TODO(), projects, clients, deploy.DeployConfigsOptions{})
 	assert.NoError(t, errs)
 
 	dashboards, found := dummyClient.GetEntries(api.NewAPIs()["dashboard"])
@@ -793,7 +804,7 @@ func TestDeployConfigGraph_DeploysIndependentConfigurations_IfContinuingAfterFai
 		dynatrace.EnvironmentInfo{Name: environmentName}: &clientSet,
 	}
 
-	errs := deploy.Deploy(projects, clients, deploy.DeployConfigsOptions{ContinueOnErr: true})
+	errs := deploy.Deploy(context.TODO(), projects, clients, deploy.DeployConfigsOptions{ContinueOnErr: true})
 	assert.Len(t, errs, 1)
 
 	dashboards, found := dummyClient.GetEntries(api.NewAPIs()["dashboard"])
@@ -1179,7 +1190,7 @@ func TestDeployConfigsValidatesClassicAPINames(t *testing.T) {
 				dynatrace.EnvironmentInfo{Name: "env2"}: &clientSet,
 			}
 
-			err := deploy.Deploy(tc.given, c, deploy.DeployConfigsOptions{})
+			err := deploy.Deploy(context.TODO(), tc.given, c, deploy.DeployConfigsOptions{})
 			if len(tc.wantErrsContain) == 0 {
 				assert.NoError(t, err)
 			} else {
@@ -1270,7 +1281,7 @@ func TestDeployConfigGraph_CollectsAllErrors(t *testing.T) {
 	}
 
 	t.Run("stop on error - returns validation errors", func(t *testing.T) {
-		errs := deploy.Deploy(p, c, deploy.DeployConfigsOptions{})
+		errs := deploy.Deploy(context.TODO(), p, c, deploy.DeployConfigsOptions{})
 		assert.Error(t, errs)
 
 		var envErrs errors.EnvironmentDeploymentErrors
@@ -1281,7 +1292,7 @@ func TestDeployConfigGraph_CollectsAllErrors(t *testing.T) {
 	})
 
 	t.Run("continue on error - returns validation and deployment", func(t *testing.T) {
-		errs := deploy.Deploy(p, c, deploy.DeployConfigsOptions{ContinueOnErr: true})
+		errs := deploy.Deploy(context.TODO(), p, c, deploy.DeployConfigsOptions{ContinueOnErr: true})
 		assert.Error(t, errs)
 
 		var envErrs errors.EnvironmentDeploymentErrors
@@ -1294,3 +1305,1152 @@ func TestDeployConfigGraph_CollectsAllErrors(t *testing.T) {
 	})
 
 }
+
+func TestDeploy_DeploysEnvironmentsConcurrently(t *testing.T) {
+	envNames := []string{"env1", "env2", "env3"}
+
+	var started sync.WaitGroup
+	started.Add(len(envNames))
+	allStarted := make(chan struct{})
+	go func() {
+		started.Wait()
+		close(allStarted)
+	}()
+
+	clients := dynatrace.EnvironmentClients{}
+	for _, env := range envNames {
+		c := dtclient.NewMockClient(gomock.NewController(t))
+		c.EXPECT().UpsertSettings(gomock.Any(), gomock.Any(), gomock.Any()).Times(1).DoAndReturn(func(_ context.Context, _ dtclient.SettingsObject, _ dtclient.UpsertSettingsOptions) (dtclient.DynatraceEntity, error) {
+			// only return once all environments are being deployed - this blocks forever if environments are deployed one after another
+			started.Done()
+			select {
+			case <-allStarted:
+				return dtclient.DynatraceEntity{Id: "id"}, nil
+			case <-time.After(5 * time.Second):
+				return dtclient.DynatraceEntity{}, fmt.Errorf("environments were not deployed concurrently")
+			}
+		})
+		clients[dynatrace.EnvironmentInfo{Name: env}] = &client.ClientSet{DTClient: c}
+	}
+
+	err := deploy.Deploy(context.TODO(), settingsProjectForEnvironments(t, envNames...), clients, deploy.DeployConfigsOptions{MaxConcurrentEnvironments: len(envNames)})
+	assert.NoError(t, err)
+}
+
+func TestDeploy_ConcurrentEnvironmentErrorsAreCollectedPerEnvironment(t *testing.T) {
+	envNames := []string{"env1", "env2", "env3"}
+
+	tests := []struct {
+		name                   string
+		opts                   deploy.DeployConfigsOptions
+		expectedEnvsWithErrors int
+	}{
+		{
+			name:                   "stops starting new environments after failure",
+			opts:                   deploy.DeployConfigsOptions{MaxConcurrentEnvironments: 1},
+			expectedEnvsWithErrors: 1,
+		},
+		{
+			name:                   "deploys all environments if continuing on error",
+			opts:                   deploy.DeployConfigsOptions{MaxConcurrentEnvironments: 1, ContinueOnErr: true},
+			expectedEnvsWithErrors: 3,
+		},
+		{
+			name:                   "deploys all environments at once",
+			opts:                   deploy.DeployConfigsOptions{MaxConcurrentEnvironments: 0, ContinueOnErr: true},
+			expectedEnvsWithErrors: 3,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clients := dynatrace.EnvironmentClients{}
+			for _, env := range envNames {
+				c := dtclient.NewMockClient(gomock.NewController(t))
+				c.EXPECT().UpsertSettings(gomock.Any(), gomock.Any(), gomock.Any()).MaxTimes(1).Return(dtclient.DynatraceEntity{}, fmt.Errorf("upsert failed"))
+				clients[dynatrace.EnvironmentInfo{Name: env}] = &client.ClientSet{DTClient: c}
+			}
+
+			err := deploy.Deploy(context.TODO(), settingsProjectForEnvironments(t, envNames...), clients, tt.opts)
+
+			var envErrs errors.EnvironmentDeploymentErrors
+			assert.ErrorAs(t, err, &envErrs)
+			assert.Len(t, envErrs, tt.expectedEnvsWithErrors)
+		})
+	}
+}
+
+func settingsProjectForEnvironments(t *testing.T, envs ...string) []project.Project {
+	configs := project.ConfigsPerTypePerEnvironments{}
+	for _, env := range envs {
+		configs[env] = project.ConfigsPerType{
+			"builtin:test": []config.Config{
+				{
+					Coordinate:  coordinate.Coordinate{Project: "proj", Type: "builtin:test", ConfigId: "config"},
+					Type:        config.SettingsType{SchemaId: "builtin:test"},
+					Template:    testutils.GenerateDummyTemplate(t),
+					Environment: env,
+					Parameters: config.Parameters{
+						config.NameParameter:  &parameter.DummyParameter{Value: "name"},
+						config.ScopeParameter: &parameter.DummyParameter{Value: "environment"},
+					},
+				},
+			},
+		}
+	}
+	return []project.Project{{Id: "proj", Configs: configs}}
+}
+
+func TestDeploy_UsesAndUpdatesDeploymentState(t *testing.T) {
+	known := coordinate.Coordinate{Project: "proj", Type: "auto-tag", ConfigId: "known"}
+	unknown := coordinate.Coordinate{Project: "proj", Type: "auto-tag", ConfigId: "unknown"}
+	autoTagConfig := func(c coordinate.Coordinate, name string) config.Config {
+		return config.Config{
+			Coordinate:  c,
+			Type:        config.ClassicApiType{Api: "auto-tag"},
+			Template:    template.NewInMemoryTemplate(c.ConfigId, `{"name": "{{ .name }}"}`),
+			Environment: "env",
+			Parameters:  config.Parameters{config.NameParameter: value.New(name)},
+		}
+	}
+
+	p := []project.Project{
+		{
+			Id: "proj",
+			Configs: project.ConfigsPerTypePerEnvironments{"env": project.ConfigsPerType{
+				"auto-tag": []config.Config{autoTagConfig(known, "renamed"), autoTagConfig(unknown, "new")},
+			}},
+		},
+	}
+
+	s := state.New()
+	s.Set("env", state.Entry{Coordinate: known, ConfigType: string(config.ClassicApiType{}.ID()), RemoteId: "known-id"})
+
+	c := dtclient.NewMockClient(gomock.NewController(t))
+	c.EXPECT().ListConfigs(gomock.Any(), gomock.Any()).AnyTimes().Return([]dtclient.Value{{Id: "known-id", Name: "old-name"}}, nil)
+	c.EXPECT().UpdateConfigById(gomock.Any(), gomock.Any(), "known-id", "renamed", gomock.Any()).Times(1).Return(dtclient.DynatraceEntity{Id: "known-id", Name: "renamed"}, nil)
+	c.EXPECT().UpsertConfigByName(gomock.Any(), gomock.Any(), "new", gomock.Any()).Times(1).Return(dtclient.DynatraceEntity{Id: "new-id", Name: "new"}, nil)
+
+	clients := dynatrace.EnvironmentClients{
+		dynatrace.EnvironmentInfo{Name: "env"}: &client.ClientSet{DTClient: c},
+	}
+
+	err := deploy.Deploy(context.TODO(), p, clients, deploy.DeployConfigsOptions{State: s})
+	assert.NoError(t, err)
+
+	assert.Equal(t, []state.Entry{
+		{Coordinate: known, ConfigType: "classic", RemoteId: "known-id", PayloadHash: state.HashPayload([]byte(`{"name": "renamed"}`))},
+		{Coordinate: unknown, ConfigType: "classic", RemoteId: "new-id", PayloadHash: state.HashPayload([]byte(`{"name": "new"}`))},
+	}, s.Entries("env"))
+}
+
+func TestDeploy_DoesNotUpdateDeploymentStateInDryRun(t *testing.T) {
+	s := state.New()
+
+	err := deploy.Deploy(context.TODO(), settingsProjectForEnvironments(t, "env"), dynatrace.EnvironmentClients{
+		dynatrace.EnvironmentInfo{Name: "env"}: &client.ClientSet{},
+	}, deploy.DeployConfigsOptions{DryRun: true, State: s})
+	assert.NoError(t, err)
+	assert.Empty(t, s.Environments())
+}
+
+func TestDeploy_RecordsReport(t *testing.T) {
+	deployed := coordinate.Coordinate{Project: "proj", Type: "auto-tag", ConfigId: "deployed"}
+	failing := coordinate.Coordinate{Project: "proj", Type: "auto-tag", ConfigId: "failing"}
+	child := coordinate.Coordinate{Project: "proj", Type: "auto-tag", ConfigId: "child"}
+	grandchild := coordinate.Coordinate{Project: "proj", Type: "auto-tag", ConfigId: "grandchild"}
+	skipped := coordinate.Coordinate{Project: "proj", Type: "auto-tag", ConfigId: "skipped"}
+
+	autoTagConfig := func(c coordinate.Coordinate, params config.Parameters) config.Config {
+		params[config.NameParameter] = value.New(c.ConfigId)
+		return config.Config{
+			Coordinate:  c,
+			Type:        config.ClassicApiType{Api: "auto-tag"},
+			Template:    template.NewInMemoryTemplate(c.ConfigId, `{"name": "{{ .name }}"}`),
+			Environment: "env",
+			Parameters:  params,
+		}
+	}
+	skippedConfig := autoTagConfig(skipped, config.Parameters{})
+	skippedConfig.Skip = true
+
+	p := []project.Project{
+		{
+			Id: "proj",
+			Configs: project.ConfigsPerTypePerEnvironments{"env": project.ConfigsPerType{
+				"auto-tag": []config.Config{
+					autoTagConfig(deployed, config.Parameters{}),
+					autoTagConfig(failing, config.Parameters{}),
+					autoTagConfig(child, config.Parameters{"ref": reference.New("proj", "auto-tag", "failing", "id")}),
+					autoTagConfig(grandchild, config.Parameters{"ref": reference.New("proj", "auto-tag", "child", "id")}),
+					skippedConfig,
+				},
+			}},
+		},
+	}
+
+	c := dtclient.NewMockClient(gomock.NewController(t))
+	c.EXPECT().UpsertConfigByName(gomock.Any(), gomock.Any(), "deployed", gomock.Any()).Times(1).Return(dtclient.DynatraceEntity{Id: "deployed-id", Name: "deployed"}, nil)
+	c.EXPECT().UpsertConfigByName(gomock.Any(), gomock.Any(), "failing", gomock.Any()).Times(1).Return(dtclient.DynatraceEntity{}, fmt.Errorf("upsert failed"))
+
+	clients := dynatrace.EnvironmentClients{
+		dynatrace.EnvironmentInfo{Name: "env"}: &client.ClientSet{DTClient: c},
+	}
+
+	r := report.NewRecorder()
+	err := deploy.Deploy(context.TODO(), p, clients, deploy.DeployConfigsOptions{ContinueOnErr: true, Report: r})
+	assert.Error(t, err)
+
+	records := r.Records()
+	require.Len(t, records, 5)
+	for i := range records {
+		assert.Equal(t, "env", records[i].Environment)
+		records[i].Duration = 0
+	}
+
+	assert.Equal(t, report.Record{Coordinate: child, Environment: "env", Status: report.StatusSkipped, SkipReason: "depends on proj:auto-tag:failing which was not deployed, as proj:auto-tag:failing failed to deploy", RootCause: &failing}, records[0])
+	assert.Equal(t, report.Record{Coordinate: deployed, Environment: "env", Status: report.StatusDeployed, RemoteId: "deployed-id"}, records[1])
+	assert.Equal(t, failing, records[2].Coordinate)
+	assert.Equal(t, report.StatusFailed, records[2].Status)
+	require.NotNil(t, records[2].Error)
+	assert.Contains(t, records[2].Error.Message, "upsert failed")
+	assert.Equal(t, report.Record{Coordinate: grandchild, Environment: "env", Status: report.StatusSkipped, SkipReason: "depends on proj:auto-tag:child which was not deployed, as proj:auto-tag:failing failed to deploy", RootCause: &failing}, records[3])
+	assert.Equal(t, report.Record{Coordinate: skipped, Environment: "env", Status: report.StatusSkipped, SkipReason: "configuration is marked as skipped"}, records[4])
+}
+
+func TestDeploy_Cancellation(t *testing.T) {
+	parent := coordinate.Coordinate{Project: "proj", Type: "auto-tag", ConfigId: "parent"}
+	child := coordinate.Coordinate{Project: "proj", Type: "auto-tag", ConfigId: "child"}
+
+	autoTagConfig := func(c coordinate.Coordinate, params config.Parameters) config.Config {
+		params[config.NameParameter] = value.New(c.ConfigId)
+		return config.Config{
+			Coordinate:  c,
+			Type:        config.ClassicApiType{Api: "auto-tag"},
+			Template:    template.NewInMemoryTemplate(c.ConfigId, `{"name": "{{ .name }}"}`),
+			Environment: "env",
+			Parameters:  params,
+		}
+	}
+
+	p := []project.Project{
+		{
+			Id: "proj",
+			Configs: project.ConfigsPerTypePerEnvironments{"env": project.ConfigsPerType{
+				"auto-tag": []config.Config{
+					autoTagConfig(parent, config.Parameters{}),
+					autoTagConfig(child, config.Parameters{"ref": reference.New("proj", "auto-tag", "parent", "id")}),
+				},
+			}},
+		},
+	}
+
+	t.Run("nothing is deployed if the context is already cancelled", func(t *testing.T) {
+		c := dtclient.NewMockClient(gomock.NewController(t))
+		clients := dynatrace.EnvironmentClients{
+			dynatrace.EnvironmentInfo{Name: "env"}: &client.ClientSet{DTClient: c},
+		}
+
+		ctx, cancel := context.WithCancel(context.TODO())
+		cancel()
+
+		r := report.NewRecorder()
+		err := deploy.Deploy(ctx, p, clients, deploy.DeployConfigsOptions{Report: r})
+		assert.ErrorIs(t, err, context.Canceled)
+
+		records := r.Records()
+		require.Len(t, records, 2)
+		assert.Equal(t, report.Record{Coordinate: child, Environment: "env", Status: report.StatusCancelled}, records[0])
+		assert.Equal(t, report.Record{Coordinate: parent, Environment: "env", Status: report.StatusCancelled}, records[1])
+	})
+
+	t.Run("configurations in progress are finished, remaining ones are cancelled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.TODO())
+		defer cancel()
+
+		c := dtclient.NewMockClient(gomock.NewController(t))
+		c.EXPECT().UpsertConfigByName(gomock.Any(), gomock.Any(), "parent", gomock.Any()).Times(1).DoAndReturn(
+			func(reqCtx context.Context, _ api.API, _ string, _ []byte) (dtclient.DynatraceEntity, error) {
+				cancel()
+				assert.NoError(t, reqCtx.Err(), "requests in progress must not be cancelled")
+				return dtclient.DynatraceEntity{Id: "parent-id", Name: "parent"}, nil
+			})
+		clients := dynatrace.EnvironmentClients{
+			dynatrace.EnvironmentInfo{Name: "env"}: &client.ClientSet{DTClient: c},
+		}
+
+		r := report.NewRecorder()
+		err := deploy.Deploy(ctx, p, clients, deploy.DeployConfigsOptions{Report: r})
+		assert.ErrorIs(t, err, context.Canceled)
+
+		records := r.Records()
+		require.Len(t, records, 2)
+		assert.Equal(t, report.Record{Coordinate: child, Environment: "env", Status: report.StatusCancelled}, records[0])
+		assert.Equal(t, parent, records[1].Coordinate)
+		assert.Equal(t, report.StatusDeployed, records[1].Status)
+	})
+}
+
+func TestDeploy_SkipUnchanged(t *testing.T) {
+	unchangedSetting := coordinate.Coordinate{Project: "proj", Type: "builtin:alerting.profile", ConfigId: "unchanged"}
+	movedSetting := coordinate.Coordinate{Project: "proj", Type: "builtin:alerting.profile", ConfigId: "moved"}
+	unchangedAutoTag := coordinate.Coordinate{Project: "proj", Type: "auto-tag", ConfigId: "unchanged"}
+	changedAutoTag := coordinate.Coordinate{Project: "proj", Type: "auto-tag", ConfigId: "changed"}
+
+	unchangedExternalID, _ := idutils.GenerateExternalID(unchangedSetting)
+	movedExternalID, _ := idutils.GenerateExternalID(movedSetting)
+
+	settingConfig := func(c coordinate.Coordinate) config.Config {
+		return config.Config{
+			Coordinate:  c,
+			Type:        config.SettingsType{SchemaId: "builtin:alerting.profile"},
+			Template:    template.NewInMemoryTemplate(c.ConfigId, `{"name": "{{ .name }}"}`),
+			Environment: "env",
+			Parameters: config.Parameters{
+				config.NameParameter:  value.New(c.ConfigId),
+				config.ScopeParameter: value.New("environment"),
+			},
+		}
+	}
+	autoTagConfig := func(c coordinate.Coordinate) config.Config {
+		return config.Config{
+			Coordinate:  c,
+			Type:        config.ClassicApiType{Api: "auto-tag"},
+			Template:    template.NewInMemoryTemplate(c.ConfigId, `{"name": "{{ .name }}", "profile": "{{ .profile }}"}`),
+			Environment: "env",
+			Parameters: config.Parameters{
+				config.NameParameter: value.New(c.ConfigId),
+				"profile":            reference.NewWithCoordinate(unchangedSetting, config.IdParameter),
+			},
+		}
+	}
+
+	p := []project.Project{
+		{
+			Id: "proj",
+			Configs: project.ConfigsPerTypePerEnvironments{"env": project.ConfigsPerType{
+				"builtin:alerting.profile": []config.Config{settingConfig(unchangedSetting), settingConfig(movedSetting)},
+				"auto-tag":                 []config.Config{autoTagConfig(unchangedAutoTag), autoTagConfig(changedAutoTag)},
+			}},
+		},
+	}
+
+	c := dtclient.NewMockClient(gomock.NewController(t))
+	c.EXPECT().ListSettings(gomock.Any(), "builtin:alerting.profile", gomock.Any()).AnyTimes().DoAndReturn(
+		func(_ context.Context, _ string, opts dtclient.ListSettingsOptions) ([]dtclient.DownloadSettingsObject, error) {
+			var found []dtclient.DownloadSettingsObject
+			for _, o := range []dtclient.DownloadSettingsObject{
+				{ExternalId: unchangedExternalID, ObjectId: "unchanged-id", Scope: "environment", Value: []byte(`{"name": "unchanged"}`)},
+				{ExternalId: movedExternalID, ObjectId: "moved-id", Scope: "HOST-1234", Value: []byte(`{"name": "moved"}`)},
+			} {
+				if opts.Filter(o) {
+					found = append(found, o)
+				}
+			}
+			return found, nil
+		})
+	c.EXPECT().UpsertSettings(gomock.Any(), gomock.Any(), gomock.Any()).Times(1).DoAndReturn(
+		func(_ context.Context, obj dtclient.SettingsObject, _ dtclient.UpsertSettingsOptions) (dtclient.DynatraceEntity, error) {
+			assert.Equal(t, movedSetting, obj.Coordinate, "only the setting with a changed scope must be written")
+			return dtclient.DynatraceEntity{Id: "moved-id"}, nil
+		})
+	c.EXPECT().ConfigExistsByName(gomock.Any(), gomock.Any(), "unchanged").Times(1).Return(true, "unchanged-auto-tag-id", nil)
+	c.EXPECT().ReadConfigById(gomock.Any(), "unchanged-auto-tag-id").Times(1).Return([]byte(`{"id": "unchanged-auto-tag-id", "name": "unchanged", "profile": "unchanged-id"}`), nil)
+	c.EXPECT().ConfigExistsByName(gomock.Any(), gomock.Any(), "changed").Times(1).Return(true, "changed-auto-tag-id", nil)
+	c.EXPECT().ReadConfigById(gomock.Any(), "changed-auto-tag-id").Times(1).Return([]byte(`{"id": "changed-auto-tag-id", "name": "changed", "profile": "other-id"}`), nil)
+	c.EXPECT().UpsertConfigByName(gomock.Any(), gomock.Any(), "changed", gomock.Any()).Times(1).Return(dtclient.DynatraceEntity{Id: "changed-auto-tag-id", Name: "changed"}, nil)
+
+	clients := dynatrace.EnvironmentClients{
+		dynatrace.EnvironmentInfo{Name: "env"}: &client.ClientSet{DTClient: c},
+	}
+
+	r := report.NewRecorder()
+	err := deploy.Deploy(context.TODO(), p, clients, deploy.DeployConfigsOptions{SkipUnchanged: true, Report: r})
+	require.NoError(t, err)
+
+	statuses := make(map[coordinate.Coordinate]report.Status)
+	remoteIDs := make(map[coordinate.Coordinate]string)
+	for _, rec := range r.Records() {
+		statuses[rec.Coordinate] = rec.Status
+		remoteIDs[rec.Coordinate] = rec.RemoteId
+	}
+	assert.Equal(t, map[coordinate.Coordinate]report.Status{
+		unchangedSetting: report.StatusUnchanged,
+		movedSetting:     report.StatusDeployed,
+		unchangedAutoTag: report.StatusUnchanged,
+		changedAutoTag:   report.StatusDeployed,
+	}, statuses)
+	assert.Equal(t, "unchanged-id", remoteIDs[unchangedSetting])
+	assert.Equal(t, "unchanged-auto-tag-id", remoteIDs[unchangedAutoTag])
+}
+
+func TestDeploy_SkipUnchangedComparesOnlyPropertiesManagedByConfig(t *testing.T) {
+	defaulted := coordinate.Coordinate{Project: "proj", Type: "builtin:alerting.profile", ConfigId: "defaulted"}
+	merged := coordinate.Coordinate{Project: "proj", Type: "builtin:alerting.profile", ConfigId: "merged"}
+
+	defaultedExternalID, _ := idutils.GenerateExternalID(defaulted)
+	mergedExternalID, _ := idutils.GenerateExternalID(merged)
+
+	settingConfig := func(c coordinate.Coordinate, tmpl string, merge *config.Merge) config.Config {
+		return config.Config{
+			Coordinate:  c,
+			Type:        config.SettingsType{SchemaId: "builtin:alerting.profile"},
+			Template:    template.NewInMemoryTemplate(c.ConfigId, tmpl),
+			Environment: "env",
+			Parameters: config.Parameters{
+				config.NameParameter:  value.New(c.ConfigId),
+				config.ScopeParameter: value.New("environment"),
+			},
+			Merge: merge,
+		}
+	}
+
+	p := []project.Project{
+		{
+			Id: "proj",
+			Configs: project.ConfigsPerTypePerEnvironments{"env": project.ConfigsPerType{
+				"builtin:alerting.profile": []config.Config{
+					settingConfig(defaulted, `{"name": "{{ .name }}", "rules": [{"key": "a"}]}`, nil),
+					settingConfig(merged, `{"name": "{{ .name }}", "rules": []}`, &config.Merge{IgnoredPaths: []string{"/rules"}}),
+				},
+			}},
+		},
+	}
+
+	// the server fills in defaults of properties the configs do not define, and rules of the merged config are managed in the UI
+	c := dtclient.NewMockClient(gomock.NewController(t))
+	c.EXPECT().ListSettings(gomock.Any(), "builtin:alerting.profile", gomock.Any()).AnyTimes().DoAndReturn(
+		func(_ context.Context, _ string, opts dtclient.ListSettingsOptions) ([]dtclient.DownloadSettingsObject, error) {
+			var found []dtclient.DownloadSettingsObject
+			for _, o := range []dtclient.DownloadSettingsObject{
+				{ExternalId: defaultedExternalID, ObjectId: "defaulted-id", Scope: "environment", Value: []byte(`{"name": "defaulted", "description": "", "rules": [{"key": "a", "enabled": true}]}`)},
+				{ExternalId: mergedExternalID, ObjectId: "merged-id", Scope: "environment", Value: []byte(`{"name": "merged", "description": "", "rules": [{"key": "b"}]}`)},
+			} {
+				if opts.Filter(o) {
+					found = append(found, o)
+				}
+			}
+			return found, nil
+		})
+	c.EXPECT().UpsertSettings(gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes().DoAndReturn(
+		func(_ context.Context, obj dtclient.SettingsObject, _ dtclient.UpsertSettingsOptions) (dtclient.DynatraceEntity, error) {
+			assert.Fail(t, "unchanged setting must not be written", "wrote %s", obj.Coordinate)
+			return dtclient.DynatraceEntity{Id: obj.Coordinate.ConfigId + "-id"}, nil
+		})
+
+	r := report.NewRecorder()
+	err := deploy.Deploy(context.TODO(), p, dynatrace.EnvironmentClients{
+		dynatrace.EnvironmentInfo{Name: "env"}: &client.ClientSet{DTClient: c},
+	}, deploy.DeployConfigsOptions{SkipUnchanged: true, Report: r})
+	require.NoError(t, err)
+
+	statuses := make(map[coordinate.Coordinate]report.Status)
+	for _, rec := range r.Records() {
+		statuses[rec.Coordinate] = rec.Status
+	}
+	assert.Equal(t, map[coordinate.Coordinate]report.Status{
+		defaulted: report.StatusUnchanged,
+		merged:    report.StatusUnchanged,
+	}, statuses)
+}
+
+func TestDeploy_SelectedConfigs(t *testing.T) {
+	autoTagConfig := func(id string, params config.Parameters) config.Config {
+		params[config.NameParameter] = value.New(id)
+		return config.Config{
+			Coordinate:  coordinate.Coordinate{Project: "proj", Type: "auto-tag", ConfigId: id},
+			Type:        config.ClassicApiType{Api: "auto-tag"},
+			Template:    template.NewInMemoryTemplate(id, `{"name": "{{ .name }}"}`),
+			Environment: "env",
+			Parameters:  params,
+		}
+	}
+
+	// grandparent <- parent <- child, unrelated
+	p := []project.Project{
+		{
+			Id: "proj",
+			Configs: project.ConfigsPerTypePerEnvironments{"env": project.ConfigsPerType{
+				"auto-tag": []config.Config{
+					autoTagConfig("grandparent", config.Parameters{}),
+					autoTagConfig("parent", config.Parameters{"ref": reference.New("proj", "auto-tag", "grandparent", "id")}),
+					autoTagConfig("child", config.Parameters{"ref": reference.New("proj", "auto-tag", "parent", "id")}),
+					autoTagConfig("unrelated", config.Parameters{}),
+				},
+			}},
+		},
+	}
+
+	deployed := func(t *testing.T, opts deploy.DeployConfigsOptions) ([]string, error) {
+		var mu sync.Mutex
+		var names []string
+
+		c := dtclient.NewMockClient(gomock.NewController(t))
+		c.EXPECT().UpsertConfigByName(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes().DoAndReturn(
+			func(_ context.Context, _ api.API, name string, _ []byte) (dtclient.DynatraceEntity, error) {
+				mu.Lock()
+				defer mu.Unlock()
+				names = append(names, name)
+				return dtclient.DynatraceEntity{Id: name + "-id", Name: name}, nil
+			})
+		clients := dynatrace.EnvironmentClients{
+			dynatrace.EnvironmentInfo{Name: "env"}: &client.ClientSet{DTClient: c},
+		}
+
+		err := deploy.Deploy(context.TODO(), p, clients, opts)
+		return names, err
+	}
+
+	t.Run("selected configs are deployed with their dependencies", func(t *testing.T) {
+		names, err := deployed(t, deploy.DeployConfigsOptions{
+			Configs: []coordinate.Pattern{{Project: "proj", Type: "auto-tag", ConfigId: "parent"}},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"grandparent", "parent"}, names)
+	})
+
+	t.Run("dependents are deployed if requested", func(t *testing.T) {
+		names, err := deployed(t, deploy.DeployConfigsOptions{
+			Configs:        []coordinate.Pattern{{Project: "proj", Type: "auto-tag", ConfigId: "parent"}},
+			WithDependents: true,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"grandparent", "parent", "child"}, names)
+	})
+
+	t.Run("config ids are matched as glob patterns", func(t *testing.T) {
+		names, err := deployed(t, deploy.DeployConfigsOptions{
+			Configs: []coordinate.Pattern{{Project: "proj", Type: "auto-tag", ConfigId: "un*"}},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"unrelated"}, names)
+	})
+
+	t.Run("fails if a pattern does not match any config", func(t *testing.T) {
+		names, err := deployed(t, deploy.DeployConfigsOptions{
+			Configs: []coordinate.Pattern{
+				{Project: "proj", Type: "auto-tag", ConfigId: "parent"},
+				{Project: "proj", Type: "dashboard", ConfigId: "parent"},
+			},
+		})
+		assert.ErrorContains(t, err, "no configuration matches proj:dashboard:parent")
+		assert.Empty(t, names)
+	})
+}
+
+func TestDeploy_ConcurrentConfigs(t *testing.T) {
+	autoTagConfig := func(id string, params config.Parameters) config.Config {
+		params[config.NameParameter] = value.New(id)
+		return config.Config{
+			Coordinate:  coordinate.Coordinate{Project: "proj", Type: "auto-tag", ConfigId: id},
+			Type:        config.ClassicApiType{Api: "auto-tag"},
+			Template:    template.NewInMemoryTemplate(id, `{"name": "{{ .name }}"}`),
+			Environment: "env",
+			Parameters:  params,
+		}
+	}
+
+	t.Run("number of configurations deployed at the same time is limited", func(t *testing.T) {
+		var configs []config.Config
+		for i := range 20 {
+			configs = append(configs, autoTagConfig(fmt.Sprintf("config-%d", i), config.Parameters{}))
+		}
+		p := []project.Project{{Id: "proj", Configs: project.ConfigsPerTypePerEnvironments{"env": project.ConfigsPerType{"auto-tag": configs}}}}
+
+		var inProgress, maxInProgress, deployed int
+		var mu sync.Mutex
+
+		c := dtclient.NewMockClient(gomock.NewController(t))
+		c.EXPECT().UpsertConfigByName(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(20).DoAndReturn(
+			func(_ context.Context, _ api.API, name string, _ []byte) (dtclient.DynatraceEntity, error) {
+				mu.Lock()
+				inProgress++
+				maxInProgress = max(maxInProgress, inProgress)
+				mu.Unlock()
+
+				time.Sleep(5 * time.Millisecond)
+
+				mu.Lock()
+				inProgress--
+				deployed++
+				mu.Unlock()
+				return dtclient.DynatraceEntity{Id: name + "-id", Name: name}, nil
+			})
+		clients := dynatrace.EnvironmentClients{
+			dynatrace.EnvironmentInfo{Name: "env"}: &client.ClientSet{DTClient: c},
+		}
+
+		err := deploy.Deploy(context.TODO(), p, clients, deploy.DeployConfigsOptions{MaxConcurrentConfigs: 3})
+		require.NoError(t, err)
+		assert.Equal(t, 20, deployed)
+		assert.LessOrEqual(t, maxInProgress, 3)
+	})
+
+	t.Run("configurations are deployed as soon as their dependencies are deployed", func(t *testing.T) {
+		// slow <- joined -> fast <- child: child must not wait for slow, although both slow and fast are roots
+		p := []project.Project{
+			{
+				Id: "proj",
+				Configs: project.ConfigsPerTypePerEnvironments{"env": project.ConfigsPerType{
+					"auto-tag": []config.Config{
+						autoTagConfig("slow", config.Parameters{}),
+						autoTagConfig("fast", config.Parameters{}),
+						autoTagConfig("child", config.Parameters{"ref": reference.New("proj", "auto-tag", "fast", "id")}),
+						autoTagConfig("joined", config.Parameters{
+							"slow": reference.New("proj", "auto-tag", "slow", "id"),
+							"fast": reference.New("proj", "auto-tag", "fast", "id"),
+						}),
+					},
+				}},
+			},
+		}
+
+		childDeployed := make(chan struct{})
+
+		c := dtclient.NewMockClient(gomock.NewController(t))
+		c.EXPECT().UpsertConfigByName(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(4).DoAndReturn(
+			func(_ context.Context, _ api.API, name string, _ []byte) (dtclient.DynatraceEntity, error) {
+				switch name {
+				case "slow":
+					select {
+					case <-childDeployed:
+					case <-time.After(5 * time.Second):
+						assert.Fail(t, "child was not deployed while slow was still being deployed")
+					}
+				case "child":
+					close(childDeployed)
+				}
+				return dtclient.DynatraceEntity{Id: name + "-id", Name: name}, nil
+			})
+		clients := dynatrace.EnvironmentClients{
+			dynatrace.EnvironmentInfo{Name: "env"}: &client.ClientSet{DTClient: c},
+		}
+
+		err := deploy.Deploy(context.TODO(), p, clients, deploy.DeployConfigsOptions{MaxConcurrentConfigs: 2})
+		require.NoError(t, err)
+	})
+}
+
+func TestDeploy_RetriesFailedConfigs(t *testing.T) {
+	parent := coordinate.Coordinate{Project: "proj", Type: "auto-tag", ConfigId: "parent"}
+	child := coordinate.Coordinate{Project: "proj", Type: "auto-tag", ConfigId: "child"}
+	other := coordinate.Coordinate{Project: "proj", Type: "auto-tag", ConfigId: "other"}
+
+	autoTagConfig := func(c coordinate.Coordinate, params config.Parameters) config.Config {
+		params[config.NameParameter] = value.New(c.ConfigId)
+		return config.Config{
+			Coordinate:  c,
+			Type:        config.ClassicApiType{Api: "auto-tag"},
+			Template:    template.NewInMemoryTemplate(c.ConfigId, `{"name": "{{ .name }}"}`),
+			Environment: "env",
+			Parameters:  params,
+		}
+	}
+
+	p := []project.Project{
+		{
+			Id: "proj",
+			Configs: project.ConfigsPerTypePerEnvironments{"env": project.ConfigsPerType{
+				"auto-tag": []config.Config{
+					autoTagConfig(parent, config.Parameters{}),
+					autoTagConfig(child, config.Parameters{
+						"parent": reference.New("proj", "auto-tag", "parent", "id"),
+						"other":  reference.New("proj", "auto-tag", "other", "id"),
+					}),
+					autoTagConfig(other, config.Parameters{}),
+				},
+			}},
+		},
+	}
+
+	t.Run("failed configs and their dependents are deployed again", func(t *testing.T) {
+		c := dtclient.NewMockClient(gomock.NewController(t))
+		gomock.InOrder(
+			c.EXPECT().UpsertConfigByName(gomock.Any(), gomock.Any(), "parent", gomock.Any()).Times(1).Return(dtclient.DynatraceEntity{}, fmt.Errorf("entity not yet available")),
+			c.EXPECT().UpsertConfigByName(gomock.Any(), gomock.Any(), "parent", gomock.Any()).Times(1).Return(dtclient.DynatraceEntity{Id: "parent-id", Name: "parent"}, nil),
+		)
+		c.EXPECT().UpsertConfigByName(gomock.Any(), gomock.Any(), "other", gomock.Any()).Times(1).Return(dtclient.DynatraceEntity{Id: "other-id", Name: "other"}, nil)
+		c.EXPECT().UpsertConfigByName(gomock.Any(), gomock.Any(), "child", gomock.Any()).Times(1).Return(dtclient.DynatraceEntity{Id: "child-id", Name: "child"}, nil)
+		clients := dynatrace.EnvironmentClients{
+			dynatrace.EnvironmentInfo{Name: "env"}: &client.ClientSet{DTClient: c},
+		}
+
+		r := report.NewRecorder()
+		err := deploy.Deploy(context.TODO(), p, clients, deploy.DeployConfigsOptions{Retries: 2, RetryBackoff: time.Millisecond, Report: r})
+		require.NoError(t, err)
+
+		records := r.Records()
+		require.Len(t, records, 3)
+		assert.Equal(t, report.Record{Coordinate: child, Environment: "env", Status: report.StatusDeployed, RemoteId: "child-id", Duration: records[0].Duration, Retries: 1}, records[0])
+		assert.Equal(t, report.Record{Coordinate: other, Environment: "env", Status: report.StatusDeployed, RemoteId: "other-id", Duration: records[1].Duration}, records[1])
+		assert.Equal(t, report.Record{Coordinate: parent, Environment: "env", Status: report.StatusDeployed, RemoteId: "parent-id", Duration: records[2].Duration, Retries: 1}, records[2])
+	})
+
+	t.Run("deployment fails once all retries failed", func(t *testing.T) {
+		c := dtclient.NewMockClient(gomock.NewController(t))
+		c.EXPECT().UpsertConfigByName(gomock.Any(), gomock.Any(), "parent", gomock.Any()).Times(3).Return(dtclient.DynatraceEntity{}, fmt.Errorf("internal server error"))
+		c.EXPECT().UpsertConfigByName(gomock.Any(), gomock.Any(), "other", gomock.Any()).Times(1).Return(dtclient.DynatraceEntity{Id: "other-id", Name: "other"}, nil)
+		clients := dynatrace.EnvironmentClients{
+			dynatrace.EnvironmentInfo{Name: "env"}: &client.ClientSet{DTClient: c},
+		}
+
+		r := report.NewRecorder()
+		err := deploy.Deploy(context.TODO(), p, clients, deploy.DeployConfigsOptions{Retries: 2, RetryBackoff: time.Millisecond, Report: r})
+		assert.Error(t, err)
+
+		records := r.Records()
+		require.Len(t, records, 3)
+		assert.Equal(t, report.StatusSkipped, records[0].Status)
+		assert.Equal(t, 2, records[0].Retries)
+		assert.Equal(t, report.StatusFailed, records[2].Status)
+		assert.Equal(t, 2, records[2].Retries)
+	})
+
+	t.Run("failed configs are not retried by default", func(t *testing.T) {
+		c := dtclient.NewMockClient(gomock.NewController(t))
+		c.EXPECT().UpsertConfigByName(gomock.Any(), gomock.Any(), "parent", gomock.Any()).Times(1).Return(dtclient.DynatraceEntity{}, fmt.Errorf("internal server error"))
+		c.EXPECT().UpsertConfigByName(gomock.Any(), gomock.Any(), "other", gomock.Any()).Times(1).Return(dtclient.DynatraceEntity{Id: "other-id", Name: "other"}, nil)
+		clients := dynatrace.EnvironmentClients{
+			dynatrace.EnvironmentInfo{Name: "env"}: &client.ClientSet{DTClient: c},
+		}
+
+		err := deploy.Deploy(context.TODO(), p, clients, deploy.DeployConfigsOptions{})
+		assert.Error(t, err)
+	})
+}
+
+func TestDeploy_ResumeFromCheckpoint(t *testing.T) {
+	parent := coordinate.Coordinate{Project: "proj", Type: "auto-tag", ConfigId: "parent"}
+	changed := coordinate.Coordinate{Project: "proj", Type: "auto-tag", ConfigId: "changed"}
+	child := coordinate.Coordinate{Project: "proj", Type: "auto-tag", ConfigId: "child"}
+
+	p := []project.Project{
+		{
+			Id: "proj",
+			Configs: project.ConfigsPerTypePerEnvironments{"env": project.ConfigsPerType{
+				"auto-tag": []config.Config{
+					{
+						Coordinate:  parent,
+						Type:        config.ClassicApiType{Api: "auto-tag"},
+						Template:    template.NewInMemoryTemplate("parent", `{"name": "{{ .name }}"}`),
+						Environment: "env",
+						Parameters:  config.Parameters{config.NameParameter: value.New("parent")},
+					},
+					{
+						Coordinate:  changed,
+						Type:        config.ClassicApiType{Api: "auto-tag"},
+						Template:    template.NewInMemoryTemplate("changed", `{"name": "{{ .name }}"}`),
+						Environment: "env",
+						Parameters:  config.Parameters{config.NameParameter: value.New("changed")},
+					},
+					{
+						Coordinate:  child,
+						Type:        config.ClassicApiType{Api: "auto-tag"},
+						Template:    template.NewInMemoryTemplate("child", `{"name": "{{ .name }}", "parent": "{{ .parent }}"}`),
+						Environment: "env",
+						Parameters: config.Parameters{
+							config.NameParameter: value.New("child"),
+							"parent":             reference.NewWithCoordinate(parent, config.IdParameter),
+						},
+					},
+				},
+			}},
+		},
+	}
+
+	cp := checkpoint.New()
+	cp.Set("env", checkpoint.Entry{
+		Coordinate:  parent,
+		EntityName:  "parent",
+		Properties:  parameter.Properties{config.IdParameter: "parent-id", config.NameParameter: "parent"},
+		PayloadHash: state.HashPayload([]byte(`{"name": "parent"}`)),
+	})
+	cp.Set("env", checkpoint.Entry{
+		Coordinate:  changed,
+		EntityName:  "changed",
+		Properties:  parameter.Properties{config.IdParameter: "changed-id", config.NameParameter: "changed"},
+		PayloadHash: state.HashPayload([]byte(`{"name": "previous name"}`)),
+	})
+
+	c := dtclient.NewMockClient(gomock.NewController(t))
+	c.EXPECT().UpsertConfigByName(gomock.Any(), gomock.Any(), "changed", gomock.Any()).Times(1).Return(dtclient.DynatraceEntity{Id: "changed-id", Name: "changed"}, nil)
+	c.EXPECT().UpsertConfigByName(gomock.Any(), gomock.Any(), "child", gomock.Any()).Times(1).DoAndReturn(
+		func(_ context.Context, _ api.API, _ string, payload []byte) (dtclient.DynatraceEntity, error) {
+			assert.JSONEq(t, `{"name": "child", "parent": "parent-id"}`, string(payload), "references must be resolved from the checkpoint")
+			return dtclient.DynatraceEntity{Id: "child-id", Name: "child"}, nil
+		})
+	clients := dynatrace.EnvironmentClients{
+		dynatrace.EnvironmentInfo{Name: "env"}: &client.ClientSet{DTClient: c},
+	}
+
+	r := report.NewRecorder()
+	err := deploy.Deploy(context.TODO(), p, clients, deploy.DeployConfigsOptions{Checkpoint: cp, Report: r})
+	require.NoError(t, err)
+
+	statuses := make(map[coordinate.Coordinate]report.Status)
+	for _, rec := range r.Records() {
+		statuses[rec.Coordinate] = rec.Status
+	}
+	assert.Equal(t, map[coordinate.Coordinate]report.Status{
+		parent:  report.StatusRestored,
+		changed: report.StatusDeployed,
+		child:   report.StatusDeployed,
+	}, statuses)
+
+	for _, coord := range []coordinate.Coordinate{parent, changed, child} {
+		_, found := cp.Get("env", coord)
+		assert.True(t, found, "checkpoint must contain %v", coord)
+	}
+	got, _ := cp.Get("env", changed)
+	assert.Equal(t, state.HashPayload([]byte(`{"name": "changed"}`)), got.PayloadHash, "outdated checkpoint entry must be replaced")
+}
+
+func TestDeploy_DeployPolicy(t *testing.T) {
+	createOnly := coordinate.Coordinate{Project: "proj", Type: "auto-tag", ConfigId: "create-only"}
+	updateOnly := coordinate.Coordinate{Project: "proj", Type: "auto-tag", ConfigId: "update-only"}
+	missing := coordinate.Coordinate{Project: "proj", Type: "auto-tag", ConfigId: "missing"}
+	child := coordinate.Coordinate{Project: "proj", Type: "auto-tag", ConfigId: "child"}
+	orphan := coordinate.Coordinate{Project: "proj", Type: "auto-tag", ConfigId: "orphan"}
+
+	autoTagConfig := func(c coordinate.Coordinate, policy config.DeployPolicy, parent *coordinate.Coordinate) config.Config {
+		cfg := config.Config{
+			Coordinate:   c,
+			Type:         config.ClassicApiType{Api: "auto-tag"},
+			Template:     template.NewInMemoryTemplate(c.ConfigId, `{"name": "{{ .name }}"}`),
+			Environment:  "env",
+			Parameters:   config.Parameters{config.NameParameter: value.New(c.ConfigId)},
+			DeployPolicy: policy,
+		}
+		if parent != nil {
+			cfg.Template = template.NewInMemoryTemplate(c.ConfigId, `{"name": "{{ .name }}", "parent": "{{ .parent }}"}`)
+			cfg.Parameters["parent"] = reference.NewWithCoordinate(*parent, config.IdParameter)
+		}
+		return cfg
+	}
+
+	p := []project.Project{
+		{
+			Id: "proj",
+			Configs: project.ConfigsPerTypePerEnvironments{"env": project.ConfigsPerType{
+				"auto-tag": []config.Config{
+					autoTagConfig(createOnly, config.DeployPolicyCreateOnly, nil),
+					autoTagConfig(updateOnly, config.DeployPolicyUpdateOnly, nil),
+					autoTagConfig(missing, config.DeployPolicyUpdateOnly, nil),
+					autoTagConfig(child, "", &createOnly),
+					autoTagConfig(orphan, "", &missing),
+				},
+			}},
+		},
+	}
+
+	c := dtclient.NewMockClient(gomock.NewController(t))
+	c.EXPECT().ConfigExistsByName(gomock.Any(), gomock.Any(), "create-only").Return(true, "create-only-id", nil)
+	c.EXPECT().ConfigExistsByName(gomock.Any(), gomock.Any(), "update-only").Return(true, "update-only-id", nil)
+	c.EXPECT().ConfigExistsByName(gomock.Any(), gomock.Any(), "missing").Return(false, "", nil)
+	c.EXPECT().ReadConfigById(gomock.Any(), gomock.Any()).AnyTimes().Return([]byte(`{"name": "remote"}`), nil)
+	c.EXPECT().UpsertConfigByName(gomock.Any(), gomock.Any(), "update-only", gomock.Any()).Times(1).Return(dtclient.DynatraceEntity{Id: "update-only-id", Name: "update-only"}, nil)
+	c.EXPECT().UpsertConfigByName(gomock.Any(), gomock.Any(), "child", gomock.Any()).Times(1).DoAndReturn(
+		func(_ context.Context, _ api.API, _ string, payload []byte) (dtclient.DynatraceEntity, error) {
+			assert.JSONEq(t, `{"name": "child", "parent": "create-only-id"}`, string(payload), "references must be resolved to the existing object")
+			return dtclient.DynatraceEntity{Id: "child-id", Name: "child"}, nil
+		})
+	clients := dynatrace.EnvironmentClients{
+		dynatrace.EnvironmentInfo{Name: "env"}: &client.ClientSet{DTClient: c},
+	}
+
+	r := report.NewRecorder()
+	err := deploy.Deploy(context.TODO(), p, clients, deploy.DeployConfigsOptions{Report: r})
+	require.NoError(t, err)
+
+	records := make(map[coordinate.Coordinate]report.Record)
+	for _, rec := range r.Records() {
+		records[rec.Coordinate] = rec
+	}
+	assert.Equal(t, report.StatusSkipped, records[createOnly].Status)
+	assert.Equal(t, "create-only-id", records[createOnly].RemoteId)
+	assert.Contains(t, records[createOnly].SkipReason, `deploy policy "createOnly" prevents updating the object`)
+	assert.Equal(t, report.StatusDeployed, records[updateOnly].Status)
+	assert.Equal(t, report.StatusSkipped, records[missing].Status)
+	assert.Contains(t, records[missing].SkipReason, `deploy policy "updateOnly" prevents creating the object`)
+	assert.Equal(t, report.StatusDeployed, records[child].Status)
+	assert.Equal(t, report.StatusSkipped, records[orphan].Status)
+	assert.Equal(t, &missing, records[orphan].RootCause)
+}
+
+func TestDeploy_DeployPolicyLooksUpKnownObjectByID(t *testing.T) {
+	createOnly := coordinate.Coordinate{Project: "proj", Type: "auto-tag", ConfigId: "create-only"}
+
+	p := []project.Project{
+		{
+			Id: "proj",
+			Configs: project.ConfigsPerTypePerEnvironments{"env": project.ConfigsPerType{
+				"auto-tag": []config.Config{
+					{
+						Coordinate:   createOnly,
+						Type:         config.ClassicApiType{Api: "auto-tag"},
+						Template:     template.NewInMemoryTemplate("create-only", `{"name": "{{ .name }}"}`),
+						Environment:  "env",
+						Parameters:   config.Parameters{config.NameParameter: value.New("renamed")},
+						DeployPolicy: config.DeployPolicyCreateOnly,
+					},
+				},
+			}},
+		},
+	}
+
+	s := state.New()
+	s.Set("env", state.Entry{Coordinate: createOnly, ConfigType: string(config.ClassicApiType{}.ID()), RemoteId: "known-id"})
+
+	// the renamed config must neither be looked up nor be deployed by its new name
+	c := dtclient.NewMockClient(gomock.NewController(t))
+	c.EXPECT().ListConfigs(gomock.Any(), gomock.Any()).AnyTimes().Return([]dtclient.Value{{Id: "known-id", Name: "old-name"}}, nil)
+	c.EXPECT().ReadConfigById(gomock.Any(), "known-id").Return([]byte(`{"name": "old-name"}`), nil)
+	clients := dynatrace.EnvironmentClients{
+		dynatrace.EnvironmentInfo{Name: "env"}: &client.ClientSet{DTClient: c},
+	}
+
+	r := report.NewRecorder()
+	err := deploy.Deploy(context.TODO(), p, clients, deploy.DeployConfigsOptions{State: s, Report: r})
+	require.NoError(t, err)
+
+	records := r.Records()
+	require.Len(t, records, 1)
+	assert.Equal(t, report.StatusSkipped, records[0].Status)
+	assert.Equal(t, "known-id", records[0].RemoteId)
+}
+
+func TestDeploy_MergesIntoExistingObject(t *testing.T) {
+	autoTag := coordinate.Coordinate{Project: "proj", Type: "auto-tag", ConfigId: "auto-tag"}
+	setting := coordinate.Coordinate{Project: "proj", Type: "builtin:alerting.profile", ConfigId: "setting"}
+	externalID, _ := idutils.GenerateExternalID(setting)
+
+	p := []project.Project{
+		{
+			Id: "proj",
+			Configs: project.ConfigsPerTypePerEnvironments{"env": project.ConfigsPerType{
+				"auto-tag": []config.Config{
+					{
+						Coordinate:  autoTag,
+						Type:        config.ClassicApiType{Api: "auto-tag"},
+						Template:    template.NewInMemoryTemplate("auto-tag", `{"name": "{{ .name }}", "rules": [{"key": "managed"}], "description": "not owned"}`),
+						Environment: "env",
+						Parameters:  config.Parameters{config.NameParameter: value.New("auto-tag")},
+						Merge:       &config.Merge{OwnedPaths: []string{"/name", "/rules"}},
+					},
+				},
+				"builtin:alerting.profile": []config.Config{
+					{
+						Coordinate:  setting,
+						Type:        config.SettingsType{SchemaId: "builtin:alerting.profile"},
+						Template:    template.NewInMemoryTemplate("setting", `{"name": "{{ .name }}", "severityRules": []}`),
+						Environment: "env",
+						Parameters: config.Parameters{
+							config.NameParameter:  value.New("setting"),
+							config.ScopeParameter: value.New("environment"),
+						},
+						Merge: &config.Merge{IgnoredPaths: []string{"/severityRules"}},
+					},
+				},
+			}},
+		},
+	}
+
+	c := dtclient.NewMockClient(gomock.NewController(t))
+	c.EXPECT().ConfigExistsByName(gomock.Any(), gomock.Any(), "auto-tag").Return(true, "auto-tag-id", nil)
+	c.EXPECT().ReadConfigById(gomock.Any(), "auto-tag-id").Return([]byte(`{"id": "auto-tag-id", "name": "auto-tag", "rules": [{"key": "added in UI"}], "description": "set in UI"}`), nil)
+	c.EXPECT().UpsertConfigByName(gomock.Any(), gomock.Any(), "auto-tag", gomock.Any()).Times(1).DoAndReturn(
+		func(_ context.Context, _ api.API, _ string, payload []byte) (dtclient.DynatraceEntity, error) {
+			assert.JSONEq(t, `{"name": "auto-tag", "rules": [{"key": "managed"}], "description": "set in UI"}`, string(payload))
+			return dtclient.DynatraceEntity{Id: "auto-tag-id", Name: "auto-tag"}, nil
+		})
+	c.EXPECT().ListSettings(gomock.Any(), "builtin:alerting.profile", gomock.Any()).AnyTimes().Return([]dtclient.DownloadSettingsObject{
+		{ExternalId: externalID, ObjectId: "setting-id", Scope: "environment", Value: []byte(`{"name": "old", "severityRules": [{"added": "in UI"}]}`)},
+	}, nil)
+	c.EXPECT().UpsertSettings(gomock.Any(), gomock.Any(), gomock.Any()).Times(1).DoAndReturn(
+		func(_ context.Context, obj dtclient.SettingsObject, _ dtclient.UpsertSettingsOptions) (dtclient.DynatraceEntity, error) {
+			assert.JSONEq(t, `{"name": "setting", "severityRules": [{"added": "in UI"}]}`, string(obj.Content))
+			return dtclient.DynatraceEntity{Id: "setting-id", Name: "setting-id"}, nil
+		})
+	clients := dynatrace.EnvironmentClients{
+		dynatrace.EnvironmentInfo{Name: "env"}: &client.ClientSet{DTClient: c},
+	}
+
+	err := deploy.Deploy(context.TODO(), p, clients, deploy.DeployConfigsOptions{})
+	assert.NoError(t, err)
+}
+
+func TestDeploy_MergesIntoKnownObject(t *testing.T) {
+	autoTag := coordinate.Coordinate{Project: "proj", Type: "auto-tag", ConfigId: "auto-tag"}
+
+	p := []project.Project{
+		{
+			Id: "proj",
+			Configs: project.ConfigsPerTypePerEnvironments{"env": project.ConfigsPerType{
+				"auto-tag": []config.Config{
+					{
+						Coordinate:  autoTag,
+						Type:        config.ClassicApiType{Api: "auto-tag"},
+						Template:    template.NewInMemoryTemplate("auto-tag", `{"name": "{{ .name }}", "rules": [{"key": "managed"}]}`),
+						Environment: "env",
+						Parameters:  config.Parameters{config.NameParameter: value.New("renamed")},
+						Merge:       &config.Merge{OwnedPaths: []string{"/name", "/rules"}},
+					},
+				},
+			}},
+		},
+	}
+
+	newState := func() *state.State {
+		s := state.New()
+		s.Set("env", state.Entry{Coordinate: autoTag, ConfigType: string(config.ClassicApiType{}.ID()), RemoteId: "known-id"})
+		return s
+	}
+
+	t.Run("config is merged into known object", func(t *testing.T) {
+		c := dtclient.NewMockClient(gomock.NewController(t))
+		c.EXPECT().ListConfigs(gomock.Any(), gomock.Any()).AnyTimes().Return([]dtclient.Value{{Id: "known-id", Name: "old-name"}}, nil)
+		c.EXPECT().ReadConfigById(gomock.Any(), "known-id").Return([]byte(`{"name": "old-name", "rules": [], "description": "set in UI"}`), nil)
+		c.EXPECT().UpdateConfigById(gomock.Any(), gomock.Any(), "known-id", "renamed", gomock.Any()).Times(1).DoAndReturn(
+			func(_ context.Context, _ api.API, _ string, _ string, payload []byte) (dtclient.DynatraceEntity, error) {
+				assert.JSONEq(t, `{"name": "renamed", "rules": [{"key": "managed"}], "description": "set in UI"}`, string(payload))
+				return dtclient.DynatraceEntity{Id: "known-id", Name: "renamed"}, nil
+			})
+		clients := dynatrace.EnvironmentClients{
+			dynatrace.EnvironmentInfo{Name: "env"}: &client.ClientSet{DTClient: c},
+		}
+
+		err := deploy.Deploy(context.TODO(), p, clients, deploy.DeployConfigsOptions{State: newState()})
+		assert.NoError(t, err)
+	})
+
+	t.Run("deployment fails if known object can not be found", func(t *testing.T) {
+		c := dtclient.NewMockClient(gomock.NewController(t))
+		c.EXPECT().ListConfigs(gomock.Any(), gomock.Any()).AnyTimes().Return([]dtclient.Value{}, nil)
+		c.EXPECT().ConfigExistsByName(gomock.Any(), gomock.Any(), "renamed").Return(false, "", nil)
+		clients := dynatrace.EnvironmentClients{
+			dynatrace.EnvironmentInfo{Name: "env"}: &client.ClientSet{DTClient: c},
+		}
+
+		err := deploy.Deploy(context.TODO(), p, clients, deploy.DeployConfigsOptions{State: newState()})
+		assert.Error(t, err)
+	})
+}
+
+func TestDeploy_ResolvesEntityScope(t *testing.T) {
+	selector := `type(HOST_GROUP),entityName("payments")`
+
+	newProject := func() []project.Project {
+		return []project.Project{
+			{
+				Id: "proj",
+				Configs: project.ConfigsPerTypePerEnvironments{
+					"env": project.ConfigsPerType{
+						"builtin:test": {
+							{
+								Template:    testutils.GenerateDummyTemplate(t),
+								Coordinate:  coordinate.Coordinate{Project: "proj", Type: "builtin:test", ConfigId: "setting"},
+								Environment: "env",
+								Type:        config.SettingsType{SchemaId: "builtin:test"},
+								Parameters: config.Parameters{
+									config.ScopeParameter: entity.New(selector),
+								},
+							},
+						},
+					},
+				},
+			},
+		}
+	}
+
+	t.Run("scope is the ID of the matching entity", func(t *testing.T) {
+		c := dtclient.NewMockClient(gomock.NewController(t))
+		c.EXPECT().ListEntities(gomock.Any(), selector).Return([]dtclient.MonitoredEntity{{EntityId: "HOST_GROUP-1234", Type: "HOST_GROUP", DisplayName: "payments"}}, nil)
+		c.EXPECT().UpsertSettings(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, obj dtclient.SettingsObject, _ dtclient.UpsertSettingsOptions) (dtclient.DynatraceEntity, error) {
+			assert.Equal(t, "HOST_GROUP-1234", obj.Scope)
+			return dtclient.DynatraceEntity{Id: "42"}, nil
+		})
+
+		clients := dynatrace.EnvironmentClients{dynatrace.EnvironmentInfo{Name: "env"}: &client.ClientSet{DTClient: c}}
+
+		err := deploy.Deploy(context.TODO(), newProject(), clients, deploy.DeployConfigsOptions{})
+		assert.NoError(t, err)
+	})
+
+	t.Run("fails if multiple entities match", func(t *testing.T) {
+		c := dtclient.NewMockClient(gomock.NewController(t))
+		c.EXPECT().ListEntities(gomock.Any(), selector).Return([]dtclient.MonitoredEntity{{EntityId: "HOST_GROUP-1"}, {EntityId: "HOST_GROUP-2"}}, nil)
+
+		clients := dynatrace.EnvironmentClients{dynatrace.EnvironmentInfo{Name: "env"}: &client.ClientSet{DTClient: c}}
+
+		err := deploy.Deploy(context.TODO(), newProject(), clients, deploy.DeployConfigsOptions{})
+		assert.Error(t, err)
+	})
+
+	t.Run("dry-run resolves selectors without the environment", func(t *testing.T) {
+		clients := dynatrace.EnvironmentClients{dynatrace.EnvironmentInfo{Name: "env"}: &client.ClientSet{}}
+
+		err := deploy.Deploy(context.TODO(), newProject(), clients, deploy.DeployConfigsOptions{DryRun: true})
+		assert.NoError(t, err)
+	})
+}
+
+func TestDeploy_ResolvesLookupParameters(t *testing.T) {
+	newProject := func() []project.Project {
+		return []project.Project{
+			{
+				Id: "proj",
+				Configs: project.ConfigsPerTypePerEnvironments{
+					"env": project.ConfigsPerType{
+						"builtin:test": {
+							{
+								Template:    template.NewInMemoryTemplate("setting", `{"profile": "{{ .profile }}", "zone": "{{ .zone }}"}`),
+								Coordinate:  coordinate.Coordinate{Project: "proj", Type: "builtin:test", ConfigId: "setting"},
+								Environment: "env",
+								Type:        config.SettingsType{SchemaId: "builtin:test"},
+								Parameters: config.Parameters{
+									config.ScopeParameter: &value.ValueParameter{Value: "environment"},
+									"profile":             &lookup.LookupParameter{Api: api.AlertingProfile, Match: map[string]string{"name": "Default"}, Property: lookup.IdProperty},
+									"zone":                &lookup.LookupParameter{Schema: "builtin:management-zones", Match: map[string]string{"name": "Payments"}, Property: lookup.IdProperty},
+								},
+							},
+						},
+					},
+				},
+			},
+		}
+	}
+
+	expectLookups := func(c *dtclient.MockClient) {
+		c.EXPECT().ListConfigs(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, a api.API) ([]dtclient.Value, error) {
+			assert.Equal(t, api.AlertingProfile, a.ID)
+			return []dtclient.Value{{Id: "profile-1", Name: "Default"}, {Id: "profile-2", Name: "Other"}}, nil
+		})
+		c.EXPECT().ListSettings(gomock.Any(), "builtin:management-zones", gomock.Any()).Return([]dtclient.DownloadSettingsObject{
+			{ObjectId: "zone-1", Scope: "environment", Value: []byte(`{"name": "Payments"}`)},
+		}, nil)
+	}
+
+	t.Run("lookups resolve to the IDs of existing objects", func(t *testing.T) {
+		c := dtclient.NewMockClient(gomock.NewController(t))
+		expectLookups(c)
+		c.EXPECT().UpsertSettings(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, obj dtclient.SettingsObject, _ dtclient.UpsertSettingsOptions) (dtclient.DynatraceEntity, error) {
+			assert.JSONEq(t, `{"profile": "profile-1", "zone": "zone-1"}`, string(obj.Content))
+			return dtclient.DynatraceEntity{Id: "42"}, nil
+		})
+
+		clients := dynatrace.EnvironmentClients{dynatrace.EnvironmentInfo{Name: "env"}: &client.ClientSet{DTClient: c}}
+
+		err := deploy.Deploy(context.TODO(), newProject(), clients, deploy.DeployConfigsOptions{})
+		assert.NoError(t, err)
+	})
+
+	t.Run("dry-run looks up objects in the environment without writing", func(t *testing.T) {
+		c := dtclient.NewMockClient(gomock.NewController(t))
+		expectLookups(c)
+
+		clients := dynatrace.EnvironmentClients{dynatrace.EnvironmentInfo{Name: "env"}: &client.ClientSet{DTClient: c}}
+
+		err := deploy.Deploy(context.TODO(), newProject(), clients, deploy.DeployConfigsOptions{DryRun: true})
+		assert.NoError(t, err)
+	})
+}
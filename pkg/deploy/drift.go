@@ -0,0 +1,123 @@
+// @license
+// Copyright 2024 Dynatrace LLC
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deploy
+
+import (
+	"context"
+	"fmt"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/cmd/monaco/dynatrace"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/json"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/log"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/log/field"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/coordinate"
+	project "github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/project/v2"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/state"
+)
+
+// DriftStatus describes how the object present in an environment relates to the configuration it was deployed from
+type DriftStatus string
+
+const (
+	// DriftStatusInSync states that the object present in the environment is equal to the configuration
+	DriftStatusInSync DriftStatus = "inSync"
+	// DriftStatusDrifted states that the object present in the environment was changed and differs from the configuration
+	DriftStatusDrifted DriftStatus = "drifted"
+	// DriftStatusMissing states that no object exists in the environment for the configuration, either because it was
+	// deleted, or never deployed
+	DriftStatusMissing DriftStatus = "missing"
+	// DriftStatusSkipped states that the configuration was not checked, either because it is marked as skipped, or
+	// because it depends on a configuration that is skipped or failed to be checked
+	DriftStatusSkipped DriftStatus = "skipped"
+)
+
+// DriftResult describes whether the object of a single configuration in an environment differs from the configuration
+type DriftResult struct {
+	Coordinate  coordinate.Coordinate `json:"coordinate"`
+	Environment string                `json:"environment"`
+	Status      DriftStatus           `json:"status"`
+	// RemoteId is the ID of the object present in the environment. It is empty if the object is missing.
+	RemoteId string `json:"remoteId,omitempty"`
+	// Diff contains all values of the object in the environment that differ from the configuration
+	Diff []json.Difference `json:"diff,omitempty"`
+	// Additional contains all properties only present on the object in the environment, e.g. defaults filled in by the
+	// Dynatrace server or properties added manually. They are not considered drift.
+	Additional []json.Difference `json:"additional,omitempty"`
+}
+
+// Drift renders all configurations for the given environments and reports whether the objects currently present in
+// each environment differ from them, e.g. because they were changed manually. Nothing is modified.
+//
+// Drift compares configurations in the same way as Plan - properties managed by the Dynatrace server are ignored, and
+// properties only present on the object are reported as additional properties, but not as drift. Errors for single
+// configurations are aggregated and returned after all environments have been checked.
+// If deploymentState is not nil, configurations known from previous deployments are compared with the objects they were
+// deployed to, even if their names changed.
+func Drift(ctx context.Context, projects []project.Project, environmentClients dynatrace.EnvironmentClients, deploymentState *state.State) ([]DriftResult, error) {
+	changes, err := plan(ctx, projects, environmentClients, deploymentState, logDrift)
+
+	results := make([]DriftResult, 0, len(changes))
+	for _, c := range changes {
+		results = append(results, DriftResult{
+			Coordinate:  c.Coordinate,
+			Environment: c.Environment,
+			Status:      driftStatus(c.Action),
+			RemoteId:    c.RemoteId,
+			Diff:        driftDiff(c),
+			Additional:  c.Additional,
+		})
+	}
+
+	return results, err
+}
+
+func driftStatus(a PlanAction) DriftStatus {
+	switch a {
+	case PlanActionCreate:
+		return DriftStatusMissing
+	case PlanActionUpdate:
+		return DriftStatusDrifted
+	case PlanActionUnchanged:
+		return DriftStatusInSync
+	default:
+		return DriftStatusSkipped
+	}
+}
+
+// driftDiff returns the differences of a drifted object. Missing objects have no differences, as the whole
+// configuration is missing.
+func driftDiff(c PlannedChange) []json.Difference {
+	if c.Action != PlanActionUpdate {
+		return nil
+	}
+	return c.Diff
+}
+
+func logDrift(ctx context.Context, change PlannedChange) {
+	status := driftStatus(change.Action)
+	l := log.WithCtxFields(ctx).WithFields(field.F("driftStatus", status), field.F("remoteId", change.RemoteId))
+
+	switch status {
+	case DriftStatusMissing:
+		l.Warn("Object is missing in environment")
+	case DriftStatusInSync:
+		l.WithFields(field.F("additional", change.Additional)).Debug("Object %q is in sync", change.RemoteId)
+	case DriftStatusDrifted:
+		diffs := ""
+		for _, d := range change.Diff {
+			diffs += fmt.Sprintf("\n\t%s", d)
+		}
+		l.WithFields(field.F("diff", change.Diff)).Warn("Object %q drifted from its configuration:%s", change.RemoteId, diffs)
+	}
+}
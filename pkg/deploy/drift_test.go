@@ -0,0 +1,183 @@
+//go:build unit
+
+// @license
+// Copyright 2024 Dynatrace LLC
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deploy_test
+
+import (
+	"context"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/cmd/monaco/dynatrace"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/idutils"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/json"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/client"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/client/dtclient"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/coordinate"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/parameter/value"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/template"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/deploy"
+	project "github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/project/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"testing"
+)
+
+func TestDrift(t *testing.T) {
+	inSync := coordinate.Coordinate{Project: "proj", Type: "network-zone", ConfigId: "in-sync"}
+	drifted := coordinate.Coordinate{Project: "proj", Type: "auto-tag", ConfigId: "drifted"}
+	missing := coordinate.Coordinate{Project: "proj", Type: "auto-tag", ConfigId: "missing"}
+	skipped := coordinate.Coordinate{Project: "proj", Type: "auto-tag", ConfigId: "skipped"}
+
+	p := []project.Project{
+		{
+			Id: "proj",
+			Configs: project.ConfigsPerTypePerEnvironments{"env": project.ConfigsPerType{
+				"network-zone": []config.Config{
+					{
+						Coordinate:  inSync,
+						Type:        config.ClassicApiType{Api: "network-zone"},
+						Template:    template.NewInMemoryTemplate("in-sync", `{"id": "{{ .name }}", "description": "zone"}`),
+						Environment: "env",
+						Parameters:  config.Parameters{config.NameParameter: value.New("zone")},
+					},
+				},
+				"auto-tag": []config.Config{
+					{
+						Coordinate:  drifted,
+						Type:        config.ClassicApiType{Api: "auto-tag"},
+						Template:    template.NewInMemoryTemplate("drifted", `{"name": "{{ .name }}", "description": "from config"}`),
+						Environment: "env",
+						Parameters:  config.Parameters{config.NameParameter: value.New("drifted")},
+					},
+					{
+						Coordinate:  missing,
+						Type:        config.ClassicApiType{Api: "auto-tag"},
+						Template:    template.NewInMemoryTemplate("missing", `{"name": "{{ .name }}"}`),
+						Environment: "env",
+						Parameters:  config.Parameters{config.NameParameter: value.New("missing")},
+					},
+					{
+						Coordinate:  skipped,
+						Type:        config.ClassicApiType{Api: "auto-tag"},
+						Template:    template.NewInMemoryTemplate("skipped", `{}`),
+						Environment: "env",
+						Parameters:  config.Parameters{config.NameParameter: value.New("skipped")},
+						Skip:        true,
+					},
+				},
+			}},
+		},
+	}
+
+	c := dtclient.NewMockClient(gomock.NewController(t))
+	c.EXPECT().ConfigExistsByName(gomock.Any(), gomock.Any(), "zone").Times(1).Return(true, "zone", nil)
+	c.EXPECT().ReadConfigById(gomock.Any(), "zone").Times(1).Return([]byte(`{"id": "zone", "description": "zone", "numOfOneAgentsUsing": 42}`), nil)
+	c.EXPECT().ConfigExistsByName(gomock.Any(), gomock.Any(), "drifted").Times(1).Return(true, "drifted-id", nil)
+	c.EXPECT().ReadConfigById(gomock.Any(), "drifted-id").Times(1).Return([]byte(`{"id": "drifted-id", "name": "drifted", "description": "changed in UI", "metadata": {"clusterVersion": "1.0"}}`), nil)
+	c.EXPECT().ConfigExistsByName(gomock.Any(), gomock.Any(), "missing").Times(1).Return(false, "", nil)
+
+	clients := dynatrace.EnvironmentClients{
+		dynatrace.EnvironmentInfo{Name: "env"}: &client.ClientSet{DTClient: c},
+	}
+
+	results, err := deploy.Drift(context.TODO(), p, clients, nil)
+	require.NoError(t, err)
+
+	assert.ElementsMatch(t, []deploy.DriftResult{
+		{
+			Coordinate:  inSync,
+			Environment: "env",
+			Status:      deploy.DriftStatusInSync,
+			RemoteId:    "zone",
+		},
+		{
+			Coordinate:  drifted,
+			Environment: "env",
+			Status:      deploy.DriftStatusDrifted,
+			RemoteId:    "drifted-id",
+			Diff:        []json.Difference{{Path: "/description", Current: "changed in UI", Desired: "from config"}},
+		},
+		{
+			Coordinate:  missing,
+			Environment: "env",
+			Status:      deploy.DriftStatusMissing,
+		},
+		{
+			Coordinate:  skipped,
+			Environment: "env",
+			Status:      deploy.DriftStatusSkipped,
+		},
+	}, results)
+}
+
+func TestDrift_DoesNotReportPropertiesOnlyPresentOnObjectAsDrift(t *testing.T) {
+	defaulted := coordinate.Coordinate{Project: "proj", Type: "builtin:alerting.profile", ConfigId: "defaulted"}
+	externalID, _ := idutils.GenerateExternalID(defaulted)
+
+	p := []project.Project{
+		{
+			Id: "proj",
+			Configs: project.ConfigsPerTypePerEnvironments{"env": project.ConfigsPerType{
+				"builtin:alerting.profile": []config.Config{
+					{
+						Coordinate:  defaulted,
+						Type:        config.SettingsType{SchemaId: "builtin:alerting.profile"},
+						Template:    template.NewInMemoryTemplate("defaulted", `{"name": "{{ .name }}", "rules": [{"key": "a"}]}`),
+						Environment: "env",
+						Parameters: config.Parameters{
+							config.NameParameter:  value.New("defaulted"),
+							config.ScopeParameter: value.New("environment"),
+						},
+					},
+				},
+			}},
+		},
+	}
+
+	c := dtclient.NewMockClient(gomock.NewController(t))
+	c.EXPECT().ListSettings(gomock.Any(), "builtin:alerting.profile", gomock.Any()).AnyTimes().DoAndReturn(
+		func(_ context.Context, _ string, opts dtclient.ListSettingsOptions) ([]dtclient.DownloadSettingsObject, error) {
+			o := dtclient.DownloadSettingsObject{
+				ExternalId: externalID,
+				ObjectId:   "defaulted-object-id",
+				Value:      []byte(`{"name": "defaulted", "description": "", "rules": [{"key": "a", "enabled": true}]}`),
+			}
+			if opts.Filter(o) {
+				return []dtclient.DownloadSettingsObject{o}, nil
+			}
+			return nil, nil
+		})
+
+	clients := dynatrace.EnvironmentClients{
+		dynatrace.EnvironmentInfo{Name: "env"}: &client.ClientSet{DTClient: c},
+	}
+
+	results, err := deploy.Drift(context.TODO(), p, clients, nil)
+	require.NoError(t, err)
+
+	assert.Equal(t, []deploy.DriftResult{
+		{
+			Coordinate:  defaulted,
+			Environment: "env",
+			Status:      deploy.DriftStatusInSync,
+			RemoteId:    "defaulted-object-id",
+			Additional: []json.Difference{
+				{Path: "/description", Current: "", Desired: nil},
+				{Path: "/rules/0/enabled", Current: true, Desired: nil},
+			},
+		},
+	}, results)
+}
@@ -0,0 +1,99 @@
+// @license
+// Copyright 2024 Dynatrace LLC
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deploy
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/api"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/client/dtclient"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/parameter"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/parameter/lookup"
+)
+
+// environmentLookup is a config.EntityLookup that also resolves the entity selectors of entity parameters and the
+// existing objects of lookup parameters with the APIs of the environment. Results are cached by the client of the
+// environment.
+type environmentLookup struct {
+	config.EntityLookup
+	ctx    context.Context
+	client dtclient.Client
+}
+
+var (
+	_ parameter.EntitySelectorResolver = environmentLookup{}
+	_ parameter.ObjectLookup           = environmentLookup{}
+)
+
+func newEnvironmentLookup(ctx context.Context, resolvedEntities config.EntityLookup, client dtclient.Client) environmentLookup {
+	return environmentLookup{EntityLookup: resolvedEntities, ctx: ctx, client: client}
+}
+
+func (l environmentLookup) ResolveEntitySelector(entitySelector string) ([]string, error) {
+	entities, err := l.client.ListEntities(l.ctx, entitySelector)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]string, len(entities))
+	for i, e := range entities {
+		ids[i] = e.EntityId
+	}
+	return ids, nil
+}
+
+func (l environmentLookup) ListClassicObjects(apiID string) ([]parameter.Properties, error) {
+	a, found := api.NewAPIs()[apiID]
+	if !found {
+		return nil, fmt.Errorf("unknown API %q", apiID)
+	}
+	if a.HasParent() {
+		return nil, fmt.Errorf("objects of API %q can not be looked up, as it is a sub-path API", apiID)
+	}
+
+	values, err := l.client.ListConfigs(l.ctx, a)
+	if err != nil {
+		return nil, err
+	}
+
+	objects := make([]parameter.Properties, len(values))
+	for i, v := range values {
+		objects[i] = parameter.Properties{lookup.IdProperty: v.Id, "name": v.Name}
+	}
+	return objects, nil
+}
+
+func (l environmentLookup) ListSettingsObjects(schemaID string) ([]parameter.Properties, error) {
+	settings, err := l.client.ListSettings(l.ctx, schemaID, dtclient.ListSettingsOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	objects := make([]parameter.Properties, len(settings))
+	for i, s := range settings {
+		o := make(parameter.Properties)
+		// values that are not JSON objects provide no properties besides the metadata of the object
+		_ = json.Unmarshal(s.Value, &o)
+
+		o[lookup.IdProperty] = s.ObjectId
+		o["scope"] = s.Scope
+		o["externalId"] = s.ExternalId
+		o["schemaVersion"] = s.SchemaVersion
+		objects[i] = o
+	}
+	return objects, nil
+}
@@ -29,12 +29,15 @@ import (
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/parameter"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/deploy/errors"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/deploy/internal/extract"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/deploy/internal/remote"
+	"net/http"
 	"time"
 )
 
 //go:generate mockgen -source=automation.go -destination=automation_mock.go -package=automation automationClient
 type Client interface {
 	Upsert(ctx context.Context, resourceType automationAPI.ResourceType, id string, data []byte) (result automation.Response, err error)
+	Get(ctx context.Context, resourceType automationAPI.ResourceType, id string) (automation.Response, error)
 }
 
 var _ Client = (*DummyClient)(nil)
@@ -49,6 +52,12 @@ func (c *DummyClient) Upsert(_ context.Context, _ automationAPI.ResourceType, id
 	}, nil
 }
 
+func (c *DummyClient) Get(_ context.Context, _ automationAPI.ResourceType, _ string) (automation.Response, error) {
+	return automation.Response{
+		StatusCode: http.StatusNotFound,
+	}, nil
+}
+
 func Deploy(ctx context.Context, client Client, properties parameter.Properties, renderedConfig string, c *config.Config) (entities.ResolvedEntity, error) {
 	ctx, cancel := context.WithTimeout(ctx, time.Minute)
 	defer cancel()
@@ -58,13 +67,7 @@ func Deploy(ctx context.Context, client Client, properties parameter.Properties,
 		return entities.ResolvedEntity{}, errors.NewConfigDeployErr(c, fmt.Sprintf("config was not of expected type %q, but %q", config.AutomationType{}.ID(), c.Type.ID()))
 	}
 
-	var id string
-
-	if c.OriginObjectId != "" {
-		id = c.OriginObjectId
-	} else {
-		id = idutils.GenerateUUIDFromCoordinate(c.Coordinate)
-	}
+	id := objectID(c)
 
 	resourceType, err := automationutils.ClientResourceTypeFromConfigType(t.Resource)
 	if err != nil {
@@ -84,6 +87,11 @@ func Deploy(ctx context.Context, client Client, properties parameter.Properties,
 		return entities.ResolvedEntity{}, errors.NewConfigDeployErr(c, fmt.Sprintf("failed to decode automation object response of type %s with id %s", t.Resource, id)).WithError(err)
 	}
 
+	return ResolveEntity(ctx, properties, c, remote.Object{ID: obj.ID}), nil
+}
+
+// ResolveEntity returns the entity of the given config, which is deployed to the given existing automation object
+func ResolveEntity(ctx context.Context, properties parameter.Properties, c *config.Config, obj remote.Object) entities.ResolvedEntity {
 	name := fmt.Sprintf("[UNKNOWN NAME]%s", obj.ID)
 	if configName, err := extract.ConfigName(c, properties); err == nil {
 		name = configName
@@ -92,12 +100,46 @@ func Deploy(ctx context.Context, client Client, properties parameter.Properties,
 	}
 
 	properties[config.IdParameter] = obj.ID
-	resolved := entities.ResolvedEntity{
+	return entities.ResolvedEntity{
 		EntityName: name,
 		Coordinate: c.Coordinate,
 		Properties: properties,
 		Skip:       false,
 	}
-	return resolved, nil
+}
+
+// Get fetches the automation object the given config is deployed to. If no such object exists, found is false.
+func Get(ctx context.Context, client Client, c *config.Config) (obj remote.Object, found bool, err error) {
+	t, ok := c.Type.(config.AutomationType)
+	if !ok {
+		return remote.Object{}, false, errors.NewConfigDeployErr(c, fmt.Sprintf("config was not of expected type %q, but %q", config.AutomationType{}.ID(), c.Type.ID()))
+	}
 
+	id := objectID(c)
+
+	resourceType, err := automationutils.ClientResourceTypeFromConfigType(t.Resource)
+	if err != nil {
+		return remote.Object{}, false, errors.NewConfigDeployErr(c, fmt.Sprintf("failed to get automation object of type %s with id %s", t.Resource, id)).WithError(err)
+	}
+
+	resp, err := client.Get(ctx, resourceType, id)
+	if err != nil {
+		return remote.Object{}, false, errors.NewConfigDeployErr(c, fmt.Sprintf("failed to get automation object of type %s with id %s", t.Resource, id)).WithError(err)
+	}
+	if resp.StatusCode == http.StatusNotFound {
+		return remote.Object{}, false, nil
+	}
+	if err, isErr := resp.AsAPIError(); isErr {
+		return remote.Object{}, false, errors.NewConfigDeployErr(c, fmt.Sprintf("failed to get automation object of type %s with id %s", t.Resource, id)).WithError(err)
+	}
+
+	return remote.Object{ID: id, Content: resp.Data}, true, nil
+}
+
+// objectID returns the ID of the automation object the given config is deployed to
+func objectID(c *config.Config) string {
+	if c.OriginObjectId != "" {
+		return c.OriginObjectId
+	}
+	return idutils.GenerateUUIDFromCoordinate(c.Coordinate)
 }
@@ -22,7 +22,9 @@ import (
 	"context"
 	"errors"
 	"github.com/dynatrace/dynatrace-configuration-as-code-core/api"
+	automationAPI "github.com/dynatrace/dynatrace-configuration-as-code-core/api/clients/automation"
 	"github.com/dynatrace/dynatrace-configuration-as-code-core/clients/automation"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/idutils"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/coordinate"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/parameter"
@@ -168,3 +170,68 @@ func TestDeployAutomation(t *testing.T) {
 	assert.False(t, resolvedEntity.Skip)
 	assert.Empty(t, errors)
 }
+
+func TestGetAutomation(t *testing.T) {
+	conf := &config.Config{
+		Coordinate: coordinate.Coordinate{
+			Project:  "project",
+			Type:     "workflow",
+			ConfigId: "config-id",
+		},
+		Type: config.AutomationType{
+			Resource: config.Workflow,
+		},
+	}
+
+	t.Run("returns object if it exists", func(t *testing.T) {
+		client := NewMockClient(gomock.NewController(t))
+		client.EXPECT().Get(gomock.Any(), automationAPI.Workflows, idutils.GenerateUUIDFromCoordinate(conf.Coordinate)).Times(1).Return(automation.Response{
+			StatusCode: 200,
+			Data:       []byte(`{"id": "some-id"}`),
+		}, nil)
+
+		obj, found, err := Get(context.TODO(), client, conf)
+		assert.NoError(t, err)
+		assert.True(t, found)
+		assert.Equal(t, idutils.GenerateUUIDFromCoordinate(conf.Coordinate), obj.ID)
+		assert.Equal(t, []byte(`{"id": "some-id"}`), obj.Content)
+	})
+
+	t.Run("uses origin object ID if set", func(t *testing.T) {
+		c := *conf
+		c.OriginObjectId = "origin-id"
+
+		client := NewMockClient(gomock.NewController(t))
+		client.EXPECT().Get(gomock.Any(), automationAPI.Workflows, "origin-id").Times(1).Return(automation.Response{StatusCode: 200, Data: []byte(`{}`)}, nil)
+
+		obj, found, err := Get(context.TODO(), client, &c)
+		assert.NoError(t, err)
+		assert.True(t, found)
+		assert.Equal(t, "origin-id", obj.ID)
+	})
+
+	t.Run("not found if object does not exist", func(t *testing.T) {
+		client := NewMockClient(gomock.NewController(t))
+		client.EXPECT().Get(gomock.Any(), gomock.Any(), gomock.Any()).Times(1).Return(automation.Response{StatusCode: 404}, nil)
+
+		_, found, err := Get(context.TODO(), client, conf)
+		assert.NoError(t, err)
+		assert.False(t, found)
+	})
+
+	t.Run("returns error on HTTP error", func(t *testing.T) {
+		client := NewMockClient(gomock.NewController(t))
+		client.EXPECT().Get(gomock.Any(), gomock.Any(), gomock.Any()).Times(1).Return(automation.Response{StatusCode: 500}, nil)
+
+		_, _, err := Get(context.TODO(), client, conf)
+		assert.Error(t, err)
+	})
+
+	t.Run("returns error if client fails", func(t *testing.T) {
+		client := NewMockClient(gomock.NewController(t))
+		client.EXPECT().Get(gomock.Any(), gomock.Any(), gomock.Any()).Times(1).Return(automation.Response{}, errors.New("GET_FAIL"))
+
+		_, _, err := Get(context.TODO(), client, conf)
+		assert.Error(t, err)
+	})
+}
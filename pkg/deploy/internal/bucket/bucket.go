@@ -26,6 +26,7 @@ import (
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/entities"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/parameter"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/deploy/errors"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/deploy/internal/remote"
 	clientErrors "github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/rest"
 	"github.com/go-logr/logr"
 	"net/http"
@@ -33,6 +34,7 @@ import (
 
 type Client interface {
 	Upsert(ctx context.Context, bucketName string, data []byte) (buckets.Response, error)
+	Get(ctx context.Context, bucketName string) (buckets.Response, error)
 }
 
 var _ Client = (*DummyClient)(nil)
@@ -46,14 +48,14 @@ func (c DummyClient) Upsert(_ context.Context, id string, data []byte) (response
 	}, nil
 }
 
-func Deploy(ctx context.Context, client Client, properties parameter.Properties, renderedConfig string, c *config.Config) (entities.ResolvedEntity, error) {
-	var bucketName string
+func (c DummyClient) Get(_ context.Context, _ string) (buckets.Response, error) {
+	return buckets.Response{
+		StatusCode: http.StatusNotFound,
+	}, nil
+}
 
-	if c.OriginObjectId != "" {
-		bucketName = c.OriginObjectId
-	} else {
-		bucketName = idutils.GenerateBucketName(c.Coordinate)
-	}
+func Deploy(ctx context.Context, client Client, properties parameter.Properties, renderedConfig string, c *config.Config) (entities.ResolvedEntity, error) {
+	bucketName := name(c)
 
 	// create new context to carry logger
 	ctx = logr.NewContext(ctx, log.WithCtxFields(ctx).GetLogr())
@@ -65,11 +67,43 @@ func Deploy(ctx context.Context, client Client, properties parameter.Properties,
 		return entities.ResolvedEntity{}, clientErrors.NewRespErr(fmt.Sprintf("failed to upsert bucket with bucketName %q", bucketName), clientErrors.Response{Body: resp.Data, StatusCode: resp.StatusCode})
 	}
 
-	properties[config.IdParameter] = bucketName
+	return ResolveEntity(properties, c, remote.Object{ID: bucketName}), nil
+}
+
+// ResolveEntity returns the entity of the given config, which is deployed to the given existing bucket
+func ResolveEntity(properties parameter.Properties, c *config.Config, obj remote.Object) entities.ResolvedEntity {
+	properties[config.IdParameter] = obj.ID
 
 	return entities.ResolvedEntity{
-		EntityName: bucketName,
+		EntityName: obj.ID,
 		Coordinate: c.Coordinate,
 		Properties: properties,
-	}, nil
+	}
+}
+
+// Get fetches the bucket the given config is deployed to. If no such bucket exists, found is false.
+func Get(ctx context.Context, client Client, c *config.Config) (obj remote.Object, found bool, err error) {
+	bucketName := name(c)
+
+	ctx = logr.NewContext(ctx, log.WithCtxFields(ctx).GetLogr())
+	resp, err := client.Get(ctx, bucketName)
+	if err != nil {
+		return remote.Object{}, false, errors.NewConfigDeployErr(c, fmt.Sprintf("failed to get bucket with bucketName %q", bucketName)).WithError(err)
+	}
+	if resp.StatusCode == http.StatusNotFound {
+		return remote.Object{}, false, nil
+	}
+	if !resp.IsSuccess() {
+		return remote.Object{}, false, clientErrors.NewRespErr(fmt.Sprintf("failed to get bucket with bucketName %q", bucketName), clientErrors.Response{Body: resp.Data, StatusCode: resp.StatusCode})
+	}
+
+	return remote.Object{ID: bucketName, Content: resp.Data}, true, nil
+}
+
+// name returns the name of the bucket the given config is deployed to
+func name(c *config.Config) string {
+	if c.OriginObjectId != "" {
+		return c.OriginObjectId
+	}
+	return idutils.GenerateBucketName(c.Coordinate)
 }
@@ -28,6 +28,7 @@ import (
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/parameter"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/template"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/deploy/internal/bucket"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/deploy/internal/remote"
 	"github.com/stretchr/testify/assert"
 	"testing"
 )
@@ -43,6 +44,10 @@ func (c testClient) Upsert(_ context.Context, bucketName string, data []byte) (b
 	return c.assertAndRespondFunc(c.t, bucketName, data)
 }
 
+func (c testClient) Get(_ context.Context, bucketName string) (buckets.Response, error) {
+	return c.assertAndRespondFunc(c.t, bucketName, nil)
+}
+
 func TestDeploy(t *testing.T) {
 
 	testCoord := coordinate.Coordinate{
@@ -152,7 +157,7 @@ func TestDeploy(t *testing.T) {
 				tt.assertAndRespond,
 			}
 
-			props, errs := tt.givenConfig.ResolveParameterValues(entities.New())
+			props, errs := tt.givenConfig.ResolveParameterValues(context.TODO(), entities.New())
 			assert.Empty(t, errs)
 			templ, err := tt.givenConfig.Render(props)
 			assert.NoError(t, err)
@@ -167,3 +172,91 @@ func TestDeploy(t *testing.T) {
 		})
 	}
 }
+
+func TestGet(t *testing.T) {
+
+	testCoord := coordinate.Coordinate{
+		Project:  "proj",
+		Type:     "bucket",
+		ConfigId: "my-bucket",
+	}
+
+	tests := []struct {
+		name             string
+		givenConfig      config.Config
+		assertAndRespond assertAndRespond
+		wantFound        bool
+		wantObject       remote.Object
+		wantErr          bool
+	}{
+		{
+			"gets by generated coordinate ID",
+			config.Config{Coordinate: testCoord, Type: config.BucketType{}},
+			func(t *testing.T, bucketName string, _ []byte) (buckets.Response, error) {
+				assert.Equal(t, "proj_my-bucket", bucketName)
+				return buckets.Response{StatusCode: 200, Data: []byte(`{"bucketName": "proj_my-bucket"}`)}, nil
+			},
+			true,
+			remote.Object{ID: "proj_my-bucket", Content: []byte(`{"bucketName": "proj_my-bucket"}`)},
+			false,
+		},
+		{
+			"gets by OriginObjectId if set",
+			config.Config{Coordinate: testCoord, Type: config.BucketType{}, OriginObjectId: "PreExistingBucket"},
+			func(t *testing.T, bucketName string, _ []byte) (buckets.Response, error) {
+				assert.Equal(t, "PreExistingBucket", bucketName)
+				return buckets.Response{StatusCode: 200, Data: []byte(`{}`)}, nil
+			},
+			true,
+			remote.Object{ID: "PreExistingBucket", Content: []byte(`{}`)},
+			false,
+		},
+		{
+			"not found if bucket does not exist",
+			config.Config{Coordinate: testCoord, Type: config.BucketType{}},
+			func(t *testing.T, bucketName string, _ []byte) (buckets.Response, error) {
+				return buckets.Response{StatusCode: 404}, nil
+			},
+			false,
+			remote.Object{},
+			false,
+		},
+		{
+			"returns error if HTTP request failed",
+			config.Config{Coordinate: testCoord, Type: config.BucketType{}},
+			func(t *testing.T, bucketName string, _ []byte) (buckets.Response, error) {
+				return buckets.Response{StatusCode: 500}, nil
+			},
+			false,
+			remote.Object{},
+			true,
+		},
+		{
+			"returns error on get error",
+			config.Config{Coordinate: testCoord, Type: config.BucketType{}},
+			func(t *testing.T, bucketName string, _ []byte) (buckets.Response, error) {
+				return buckets.Response{}, errors.New("fail")
+			},
+			false,
+			remote.Object{},
+			true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := testClient{
+				t,
+				tt.assertAndRespond,
+			}
+
+			got, found, err := bucket.Get(context.Background(), c, &tt.givenConfig)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tt.wantFound, found)
+			assert.Equal(t, tt.wantObject, got)
+		})
+	}
+}
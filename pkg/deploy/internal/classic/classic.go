@@ -28,26 +28,15 @@ import (
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/parameter"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/deploy/errors"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/deploy/internal/extract"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/deploy/internal/remote"
+	"slices"
 	"strings"
 )
 
 func Deploy(ctx context.Context, configClient dtclient.ConfigClient, apis api.APIs, properties parameter.Properties, renderedConfig string, conf *config.Config) (entities.ResolvedEntity, error) {
-	t, ok := conf.Type.(config.ClassicApiType)
-	if !ok {
-		return entities.ResolvedEntity{}, fmt.Errorf("config was not of expected type %q, but %q", config.ClassicApiTypeId, conf.Type.ID())
-	}
-
-	apiToDeploy, found := apis[t.Api]
-	if !found {
-		return entities.ResolvedEntity{}, fmt.Errorf("unknown api `%s`. this is most likely a bug", t.Api)
-	}
-
-	if apiToDeploy.HasParent() {
-		scope, err := extract.Scope(properties)
-		if err != nil {
-			return entities.ResolvedEntity{}, fmt.Errorf("failed to extract scope for config %q", conf.Type.ID())
-		}
-		apiToDeploy = apiToDeploy.Resolve(scope)
+	apiToDeploy, err := resolveAPI(apis, properties, conf)
+	if err != nil {
+		return entities.ResolvedEntity{}, err
 	}
 
 	configName, err := extract.ConfigName(conf, properties)
@@ -81,7 +70,199 @@ func Deploy(ctx context.Context, configClient dtclient.ConfigClient, apis api.AP
 	}, nil
 }
 
+// DeployToKnownID deploys the given config by updating the object with the given ID, e.g. an ID remembered from a
+// previous deployment. This allows to find the object even if its name changed. If the API does not support updating
+// objects by ID, or no object with the given ID exists, the config is deployed like by Deploy.
+func DeployToKnownID(ctx context.Context, configClient dtclient.ConfigClient, apis api.APIs, properties parameter.Properties, renderedConfig string, conf *config.Config, knownID string) (entities.ResolvedEntity, error) {
+	apiToDeploy, err := resolveAPI(apis, properties, conf)
+	if err != nil {
+		return entities.ResolvedEntity{}, err
+	}
+
+	if knownID == "" || apiToDeploy.SingleConfiguration || apiToDeploy.HasParent() {
+		return Deploy(ctx, configClient, apis, properties, renderedConfig, conf)
+	}
+
+	values, err := configClient.ListConfigs(ctx, apiToDeploy)
+	if err != nil {
+		return entities.ResolvedEntity{}, errors.NewConfigDeployErr(conf, err.Error()).WithError(err)
+	}
+
+	if !slices.ContainsFunc(values, func(v dtclient.Value) bool { return v.Id == knownID }) {
+		log.WithCtxFields(ctx).Debug("Known object %q does not exist anymore - searching for matching object", knownID)
+		return Deploy(ctx, configClient, apis, properties, renderedConfig, conf)
+	}
+
+	configName, err := extract.ConfigName(conf, properties)
+	if err != nil {
+		return entities.ResolvedEntity{}, err
+	}
+
+	if apiToDeploy.DeprecatedBy != "" {
+		log.WithCtxFields(ctx).Warn("API for \"%s\" is deprecated! Please consider migrating to \"%s\"!", apiToDeploy.ID, apiToDeploy.DeprecatedBy)
+	}
+
+	dtEntity, err := configClient.UpdateConfigById(ctx, apiToDeploy, knownID, configName, []byte(renderedConfig))
+	if err != nil {
+		return entities.ResolvedEntity{}, errors.NewConfigDeployErr(conf, err.Error()).WithError(err)
+	}
+
+	properties[config.IdParameter] = dtEntity.Id
+	properties[config.NameParameter] = dtEntity.Name
+
+	return entities.ResolvedEntity{
+		EntityName: dtEntity.Name,
+		Coordinate: conf.Coordinate,
+		Properties: properties,
+		Skip:       false,
+	}, nil
+}
+
+// ResolveEntity returns the entity of the given config, which is deployed to the given existing object
+func ResolveEntity(properties parameter.Properties, conf *config.Config, obj remote.Object) (entities.ResolvedEntity, error) {
+	configName, err := extract.ConfigName(conf, properties)
+	if err != nil {
+		return entities.ResolvedEntity{}, err
+	}
+
+	properties[config.IdParameter] = obj.ID
+	properties[config.NameParameter] = configName
+
+	return entities.ResolvedEntity{
+		EntityName: configName,
+		Coordinate: conf.Coordinate,
+		Properties: properties,
+		Skip:       false,
+	}, nil
+}
+
+// Get fetches the Dynatrace object the given config is deployed to. If no such object exists, found is false.
+func Get(ctx context.Context, configClient dtclient.ConfigClient, apis api.APIs, properties parameter.Properties, conf *config.Config) (obj remote.Object, found bool, err error) {
+	apiToDeploy, err := resolveAPI(apis, properties, conf)
+	if err != nil {
+		return remote.Object{}, false, err
+	}
+
+	configName, err := extract.ConfigName(conf, properties)
+	if err != nil {
+		return remote.Object{}, false, err
+	}
+
+	var id string
+	switch {
+	case apiToDeploy.SingleConfiguration:
+		id = apiToDeploy.ID
+	case apiToDeploy.NonUniqueName:
+		entityUuid := nonUniqueNameEntityID(apiToDeploy, conf)
+		values, err := configClient.ListConfigs(ctx, apiToDeploy)
+		if err != nil {
+			return remote.Object{}, false, errors.NewConfigDeployErr(conf, err.Error()).WithError(err)
+		}
+		for _, v := range values {
+			if v.Id == entityUuid {
+				id = v.Id
+				break
+			}
+		}
+	default:
+		exists, existingID, err := configClient.ConfigExistsByName(ctx, apiToDeploy, configName)
+		if err != nil {
+			return remote.Object{}, false, errors.NewConfigDeployErr(conf, err.Error()).WithError(err)
+		}
+		if exists {
+			id = existingID
+		}
+	}
+
+	if id == "" {
+		return remote.Object{}, false, nil
+	}
+
+	content, err := configClient.ReadConfigById(apiToDeploy, id)
+	if err != nil {
+		return remote.Object{}, false, errors.NewConfigDeployErr(conf, err.Error()).WithError(err)
+	}
+
+	return remote.Object{ID: id, Content: content}, true, nil
+}
+
+// GetByKnownID fetches the Dynatrace object with the given ID, e.g. an ID remembered from a previous deployment, as
+// DeployToKnownID would update it. If the API does not support updating objects by ID, or no object with the given ID
+// exists, the object is fetched like by Get.
+func GetByKnownID(ctx context.Context, configClient dtclient.ConfigClient, apis api.APIs, properties parameter.Properties, conf *config.Config, knownID string) (obj remote.Object, found bool, err error) {
+	apiToDeploy, err := resolveAPI(apis, properties, conf)
+	if err != nil {
+		return remote.Object{}, false, err
+	}
+
+	if knownID == "" || apiToDeploy.SingleConfiguration || apiToDeploy.HasParent() {
+		return Get(ctx, configClient, apis, properties, conf)
+	}
+
+	values, err := configClient.ListConfigs(ctx, apiToDeploy)
+	if err != nil {
+		return remote.Object{}, false, errors.NewConfigDeployErr(conf, err.Error()).WithError(err)
+	}
+
+	if !slices.ContainsFunc(values, func(v dtclient.Value) bool { return v.Id == knownID }) {
+		log.WithCtxFields(ctx).Debug("Known object %q does not exist anymore - searching for matching object", knownID)
+		return Get(ctx, configClient, apis, properties, conf)
+	}
+
+	content, err := configClient.ReadConfigById(apiToDeploy, knownID)
+	if err != nil {
+		return remote.Object{}, false, errors.NewConfigDeployErr(conf, err.Error()).WithError(err)
+	}
+
+	return remote.Object{ID: knownID, Content: content}, true, nil
+}
+
+// resolveAPI returns the API the given config is deployed to, resolved with the config's scope for sub-path APIs
+func resolveAPI(apis api.APIs, properties parameter.Properties, conf *config.Config) (api.API, error) {
+	t, ok := conf.Type.(config.ClassicApiType)
+	if !ok {
+		return api.API{}, fmt.Errorf("config was not of expected type %q, but %q", config.ClassicApiTypeId, conf.Type.ID())
+	}
+
+	apiToDeploy, found := apis[t.Api]
+	if !found {
+		return api.API{}, fmt.Errorf("unknown api `%s`. this is most likely a bug", t.Api)
+	}
+
+	if apiToDeploy.HasParent() {
+		scope, err := extract.Scope(properties)
+		if err != nil {
+			return api.API{}, fmt.Errorf("failed to extract scope for config %q", conf.Type.ID())
+		}
+		apiToDeploy = apiToDeploy.Resolve(scope)
+	}
+
+	return apiToDeploy, nil
+}
+
 func upsertNonUniqueNameConfig(ctx context.Context, client dtclient.ConfigClient, apiToDeploy api.API, conf *config.Config, configName string, renderedConfig string) (dtclient.DynatraceEntity, error) {
+	entityUuid := nonUniqueNameEntityID(apiToDeploy, conf)
+
+	// check if we are dealing with a non-unique name configuration that appears multiple times
+	// in a monaco project. if that's the case, we need to handle it differently, by setting the
+	// duplicate parameter accordingly
+	var duplicate bool
+	if val, exists := conf.Parameters[config.NonUniqueNameConfigDuplicationParameter]; exists {
+		resolvedVal, err := val.ResolveValue(parameter.ResolveContext{})
+		if err != nil {
+			return dtclient.DynatraceEntity{}, err
+		}
+		resolvedValBool, ok := resolvedVal.(bool)
+		if !ok {
+			return dtclient.DynatraceEntity{}, err
+		}
+		duplicate = resolvedValBool
+	}
+	return client.UpsertConfigByNonUniqueNameAndId(ctx, apiToDeploy, entityUuid, configName, []byte(renderedConfig), duplicate)
+}
+
+// nonUniqueNameEntityID returns the known ID a config of a non-unique-name API is deployed with
+func nonUniqueNameEntityID(apiToDeploy api.API, conf *config.Config) string {
 	configID := conf.Coordinate.ConfigId
 	projectId := conf.Coordinate.Project
 
@@ -105,20 +286,5 @@ func upsertNonUniqueNameConfig(ctx context.Context, client dtclient.ConfigClient
 		}
 	}
 
-	// check if we are dealing with a non-unique name configuration that appears multiple times
-	// in a monaco project. if that's the case, we need to handle it differently, by setting the
-	// duplicate parameter accordingly
-	var duplicate bool
-	if val, exists := conf.Parameters[config.NonUniqueNameConfigDuplicationParameter]; exists {
-		resolvedVal, err := val.ResolveValue(parameter.ResolveContext{})
-		if err != nil {
-			return dtclient.DynatraceEntity{}, err
-		}
-		resolvedValBool, ok := resolvedVal.(bool)
-		if !ok {
-			return dtclient.DynatraceEntity{}, err
-		}
-		duplicate = resolvedValBool
-	}
-	return client.UpsertConfigByNonUniqueNameAndId(ctx, apiToDeploy, entityUuid, configName, []byte(renderedConfig), duplicate)
+	return entityUuid
 }
@@ -20,6 +20,7 @@ package classic
 
 import (
 	"context"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/idutils"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/api"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/client/dtclient"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config"
@@ -212,3 +213,139 @@ func TestDeployConfigShouldFailOnReferenceOnSkipConfig(t *testing.T) {
 	_, errors := Deploy(context.TODO(), client, testApiMap, nil, "", &conf)
 	assert.NotEmpty(t, errors)
 }
+
+func TestGet(t *testing.T) {
+	parameters := []parameter.NamedParameter{
+		{
+			Name: config.NameParameter,
+			Parameter: &parameter.DummyParameter{
+				Value: "my-dashboard",
+			},
+		},
+	}
+
+	conf := config.Config{
+		Type:     config.ClassicApiType{Api: "dashboard"},
+		Template: testutils.GenerateDummyTemplate(t),
+		Coordinate: coordinate.Coordinate{
+			Project:  "project1",
+			Type:     "dashboard",
+			ConfigId: "dashboard-1",
+		},
+		Environment: "development",
+		Parameters:  testutils.ToParameterMap(parameters),
+	}
+
+	t.Run("returns existing object found by name", func(t *testing.T) {
+		client := &dtclient.DummyClient{}
+		_, err := client.UpsertConfigByName(context.TODO(), dashboardApi, "my-dashboard", []byte(`{"name": "my-dashboard"}`))
+		assert.NoError(t, err)
+
+		obj, found, err := Get(context.TODO(), client, testApiMap, parameter.Properties{config.NameParameter: "my-dashboard"}, &conf)
+		assert.NoError(t, err)
+		assert.True(t, found)
+		assert.NotEmpty(t, obj.ID)
+	})
+
+	t.Run("not found if no object with the name exists", func(t *testing.T) {
+		client := &dtclient.DummyClient{}
+		_, err := client.UpsertConfigByName(context.TODO(), dashboardApi, "other-dashboard", []byte(`{"name": "other-dashboard"}`))
+		assert.NoError(t, err)
+
+		_, found, err := Get(context.TODO(), client, testApiMap, parameter.Properties{config.NameParameter: "my-dashboard"}, &conf)
+		assert.NoError(t, err)
+		assert.False(t, found)
+	})
+
+	t.Run("non-unique name objects are found by their known ID", func(t *testing.T) {
+		nonUniqueApi := api.API{ID: "non-unique", URLPath: "non-unique", NonUniqueName: true}
+		nonUniqueConf := conf
+		nonUniqueConf.Type = config.ClassicApiType{Api: "non-unique"}
+		apis := api.APIs{"non-unique": nonUniqueApi}
+
+		client := &dtclient.DummyClient{}
+		_, err := client.UpsertConfigByNonUniqueNameAndId(context.TODO(), nonUniqueApi, "some-other-id", "my-dashboard", []byte(`{}`), false)
+		assert.NoError(t, err)
+
+		_, found, err := Get(context.TODO(), client, apis, parameter.Properties{config.NameParameter: "my-dashboard"}, &nonUniqueConf)
+		assert.NoError(t, err)
+		assert.False(t, found)
+
+		id := idutils.GenerateUUIDFromConfigId("project1", "dashboard-1")
+		_, err = client.UpsertConfigByNonUniqueNameAndId(context.TODO(), nonUniqueApi, id, "my-dashboard", []byte(`{"name": "my-dashboard"}`), false)
+		assert.NoError(t, err)
+
+		obj, found, err := Get(context.TODO(), client, apis, parameter.Properties{config.NameParameter: "my-dashboard"}, &nonUniqueConf)
+		assert.NoError(t, err)
+		assert.True(t, found)
+		assert.Equal(t, id, obj.ID)
+	})
+}
+
+func TestDeployToKnownID(t *testing.T) {
+	autoTagApi := api.API{ID: "auto-tag", URLPath: "auto-tag"}
+	apis := api.APIs{"auto-tag": autoTagApi}
+	conf := &config.Config{
+		Type:       config.ClassicApiType{Api: "auto-tag"},
+		Coordinate: coordinate.Coordinate{Project: "project1", Type: "auto-tag", ConfigId: "tag"},
+		Parameters: config.Parameters{config.NameParameter: &parameter.DummyParameter{Value: "renamed"}},
+	}
+
+	t.Run("updates known object even if it was renamed", func(t *testing.T) {
+		client := &dtclient.DummyClient{}
+		_, err := client.UpsertConfigByNonUniqueNameAndId(context.TODO(), autoTagApi, "known-id", "old-name", []byte("{}"), false)
+		assert.NoError(t, err)
+
+		resolved, err := DeployToKnownID(context.TODO(), client, apis, parameter.Properties{config.NameParameter: "renamed"}, "{}", conf, "known-id")
+		assert.NoError(t, err)
+		assert.Equal(t, "known-id", resolved.Properties[config.IdParameter])
+
+		entries, _ := client.GetEntries(autoTagApi)
+		assert.Len(t, entries, 1)
+	})
+
+	t.Run("deploys by name if known object does not exist anymore", func(t *testing.T) {
+		client := &dtclient.DummyClient{}
+		_, err := client.UpsertConfigByName(context.TODO(), autoTagApi, "other", []byte("{}"))
+		assert.NoError(t, err)
+
+		resolved, err := DeployToKnownID(context.TODO(), client, apis, parameter.Properties{config.NameParameter: "renamed"}, "{}", conf, "known-id")
+		assert.NoError(t, err)
+		assert.NotEqual(t, "known-id", resolved.Properties[config.IdParameter])
+
+		entries, _ := client.GetEntries(autoTagApi)
+		assert.Len(t, entries, 2)
+	})
+}
+
+func TestGetByKnownID(t *testing.T) {
+	autoTagApi := api.API{ID: "auto-tag", URLPath: "auto-tag"}
+	apis := api.APIs{"auto-tag": autoTagApi}
+	conf := &config.Config{
+		Type:       config.ClassicApiType{Api: "auto-tag"},
+		Coordinate: coordinate.Coordinate{Project: "project1", Type: "auto-tag", ConfigId: "tag"},
+		Parameters: config.Parameters{config.NameParameter: &parameter.DummyParameter{Value: "renamed"}},
+	}
+
+	t.Run("returns known object even if it was renamed", func(t *testing.T) {
+		client := &dtclient.DummyClient{}
+		_, err := client.UpsertConfigByNonUniqueNameAndId(context.TODO(), autoTagApi, "known-id", "old-name", []byte(`{"name": "old-name"}`), false)
+		assert.NoError(t, err)
+
+		obj, found, err := GetByKnownID(context.TODO(), client, apis, parameter.Properties{config.NameParameter: "renamed"}, conf, "known-id")
+		assert.NoError(t, err)
+		assert.True(t, found)
+		assert.Equal(t, "known-id", obj.ID)
+	})
+
+	t.Run("returns object found by name if known object does not exist anymore", func(t *testing.T) {
+		client := &dtclient.DummyClient{}
+		other, err := client.UpsertConfigByName(context.TODO(), autoTagApi, "renamed", []byte(`{"name": "renamed"}`))
+		assert.NoError(t, err)
+
+		obj, found, err := GetByKnownID(context.TODO(), client, apis, parameter.Properties{config.NameParameter: "renamed"}, conf, "known-id")
+		assert.NoError(t, err)
+		assert.True(t, found)
+		assert.Equal(t, other.Id, obj.ID)
+	})
+}
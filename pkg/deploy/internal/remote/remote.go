@@ -0,0 +1,27 @@
+/*
+ * @license
+ * Copyright 2024 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package remote
+
+// Object is a configuration object as it is currently present in a Dynatrace environment.
+type Object struct {
+	// ID is the identifier of the object in the environment
+	ID string
+	// Content is the JSON payload of the object as returned by the API
+	Content []byte
+	// Scope is the scope of the object, if its type is scoped like Settings objects
+	Scope string
+}
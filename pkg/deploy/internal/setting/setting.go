@@ -28,6 +28,7 @@ import (
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/parameter"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/deploy/errors"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/deploy/internal/extract"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/deploy/internal/remote"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/rest"
 	"time"
 )
@@ -58,14 +59,20 @@ func Deploy(ctx context.Context, settingsClient dtclient.SettingsClient, propert
 		return entities.ResolvedEntity{}, errors.NewConfigDeployErr(c, err.Error()).WithError(err)
 	}
 
-	name := fmt.Sprintf("[UNKNOWN NAME]%s", dtEntity.Id)
+	return ResolveEntity(ctx, properties, c, remote.Object{ID: dtEntity.Id})
+}
+
+// ResolveEntity returns the entity of the given config, which is deployed to the given existing Settings object
+func ResolveEntity(ctx context.Context, properties parameter.Properties, c *config.Config, obj remote.Object) (entities.ResolvedEntity, error) {
+	name := fmt.Sprintf("[UNKNOWN NAME]%s", obj.ID)
 	if configName, err := extract.ConfigName(c, properties); err == nil {
 		name = configName
 	} else {
-		log.WithCtxFields(ctx).Debug("failed to extract name for Settings 2.0 object %q - ID will be used", dtEntity.Id)
+		log.WithCtxFields(ctx).Debug("failed to extract name for Settings 2.0 object %q - ID will be used", obj.ID)
 	}
 
-	properties[config.IdParameter], err = getEntityID(c, dtEntity)
+	var err error
+	properties[config.IdParameter], err = getEntityID(c, dtclient.DynatraceEntity{Id: obj.ID})
 	if err != nil {
 		return entities.ResolvedEntity{}, errors.NewConfigDeployErr(c, err.Error()).WithError(err)
 	}
@@ -78,7 +85,103 @@ func Deploy(ctx context.Context, settingsClient dtclient.SettingsClient, propert
 		Properties: properties,
 		Skip:       false,
 	}, nil
+}
+
+// DeployToKnownID deploys the given config by updating the Settings object with the given object ID, e.g. an ID
+// remembered from a previous deployment. If the config already defines an origin object ID, or no object with the
+// given ID exists, the config is deployed like by Deploy.
+func DeployToKnownID(ctx context.Context, settingsClient dtclient.SettingsClient, properties parameter.Properties, renderedConfig string, c *config.Config, knownID string) (entities.ResolvedEntity, error) {
+	t, ok := c.Type.(config.SettingsType)
+	if !ok {
+		return entities.ResolvedEntity{}, errors.NewConfigDeployErr(c, fmt.Sprintf("config was not of expected type %q, but %q", config.SettingsTypeId, c.Type.ID()))
+	}
+
+	if knownID == "" || c.OriginObjectId != "" {
+		return Deploy(ctx, settingsClient, properties, renderedConfig, c)
+	}
+
+	objects, err := settingsClient.ListSettings(ctx, t.SchemaId, dtclient.ListSettingsOptions{
+		Filter: func(o dtclient.DownloadSettingsObject) bool { return o.ObjectId == knownID },
+	})
+	if err != nil {
+		return entities.ResolvedEntity{}, errors.NewConfigDeployErr(c, err.Error()).WithError(err)
+	}
+
+	if len(objects) == 0 {
+		log.WithCtxFields(ctx).Debug("Known Settings object %q does not exist anymore - searching for matching object", knownID)
+		return Deploy(ctx, settingsClient, properties, renderedConfig, c)
+	}
+
+	withKnownID := *c
+	withKnownID.OriginObjectId = knownID
+	return Deploy(ctx, settingsClient, properties, renderedConfig, &withKnownID)
+}
+
+// Get fetches the Settings object the given config is deployed to. Objects are identified by the externalId generated
+// from the config's coordinate, or by the config's origin object ID. If no such object exists, found is false.
+func Get(ctx context.Context, settingsClient dtclient.SettingsClient, c *config.Config) (obj remote.Object, found bool, err error) {
+	t, ok := c.Type.(config.SettingsType)
+	if !ok {
+		return remote.Object{}, false, errors.NewConfigDeployErr(c, fmt.Sprintf("config was not of expected type %q, but %q", config.SettingsTypeId, c.Type.ID()))
+	}
+
+	externalID, err := idutils.GenerateExternalID(c.Coordinate)
+	if err != nil {
+		return remote.Object{}, false, errors.NewConfigDeployErr(c, err.Error()).WithError(err)
+	}
+
+	objects, err := settingsClient.ListSettings(ctx, t.SchemaId, dtclient.ListSettingsOptions{
+		Filter: func(o dtclient.DownloadSettingsObject) bool {
+			return o.ExternalId == externalID || (c.OriginObjectId != "" && o.ObjectId == c.OriginObjectId)
+		},
+	})
+	if err != nil {
+		return remote.Object{}, false, errors.NewConfigDeployErr(c, err.Error()).WithError(err)
+	}
+
+	if len(objects) == 0 {
+		return remote.Object{}, false, nil
+	}
+
+	// prefer the object matching the externalId, as this is the one an upsert would update
+	o := objects[0]
+	for _, candidate := range objects {
+		if candidate.ExternalId == externalID {
+			o = candidate
+			break
+		}
+	}
+
+	return remote.Object{ID: o.ObjectId, Content: o.Value, Scope: o.Scope}, true, nil
+}
+
+// GetByKnownID fetches the Settings object with the given object ID, e.g. an ID remembered from a previous deployment,
+// as DeployToKnownID would update it. If the config already defines an origin object ID, or no object with the given
+// ID exists, the object is fetched like by Get.
+func GetByKnownID(ctx context.Context, settingsClient dtclient.SettingsClient, c *config.Config, knownID string) (obj remote.Object, found bool, err error) {
+	t, ok := c.Type.(config.SettingsType)
+	if !ok {
+		return remote.Object{}, false, errors.NewConfigDeployErr(c, fmt.Sprintf("config was not of expected type %q, but %q", config.SettingsTypeId, c.Type.ID()))
+	}
+
+	if knownID == "" || c.OriginObjectId != "" {
+		return Get(ctx, settingsClient, c)
+	}
+
+	objects, err := settingsClient.ListSettings(ctx, t.SchemaId, dtclient.ListSettingsOptions{
+		Filter: func(o dtclient.DownloadSettingsObject) bool { return o.ObjectId == knownID },
+	})
+	if err != nil {
+		return remote.Object{}, false, errors.NewConfigDeployErr(c, err.Error()).WithError(err)
+	}
+
+	if len(objects) == 0 {
+		log.WithCtxFields(ctx).Debug("Known Settings object %q does not exist anymore - searching for matching object", knownID)
+		return Get(ctx, settingsClient, c)
+	}
 
+	o := objects[0]
+	return remote.Object{ID: o.ObjectId, Content: o.Value, Scope: o.Scope}, true, nil
 }
 
 func makeUpsertOptions(c *config.Config) dtclient.UpsertSettingsOptions {
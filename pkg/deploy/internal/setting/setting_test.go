@@ -20,6 +20,8 @@ package setting
 
 import (
 	"context"
+	"errors"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/idutils"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/client/dtclient"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/coordinate"
@@ -152,3 +154,175 @@ func TestDeploySetting_ManagementZone_FailToDecodeMZoneID(t *testing.T) {
 	assert.Zero(t, resolvedEntity)
 	assert.Error(t, err)
 }
+
+func TestGetSetting(t *testing.T) {
+	conf := &config.Config{
+		Coordinate: coordinate.Coordinate{Project: "p", Type: "builtin:alerting.profile", ConfigId: "abcde"},
+		Type:       config.SettingsType{SchemaId: "builtin:alerting.profile", SchemaVersion: "1.2.3"},
+	}
+	externalID, _ := idutils.GenerateExternalID(conf.Coordinate)
+
+	t.Run("returns object matching the externalId", func(t *testing.T) {
+		c := dtclient.NewMockClient(gomock.NewController(t))
+		c.EXPECT().ListSettings(gomock.Any(), "builtin:alerting.profile", gomock.Any()).Times(1).DoAndReturn(
+			func(_ context.Context, _ string, opts dtclient.ListSettingsOptions) ([]dtclient.DownloadSettingsObject, error) {
+				all := []dtclient.DownloadSettingsObject{
+					{ExternalId: "other", ObjectId: "other-id", Value: []byte(`{"name": "other"}`)},
+					{ExternalId: externalID, ObjectId: "object-id", Value: []byte(`{"name": "mine"}`)},
+				}
+				var res []dtclient.DownloadSettingsObject
+				for _, o := range all {
+					if opts.Filter(o) {
+						res = append(res, o)
+					}
+				}
+				return res, nil
+			})
+
+		obj, found, err := Get(context.TODO(), c, conf)
+		assert.NoError(t, err)
+		assert.True(t, found)
+		assert.Equal(t, "object-id", obj.ID)
+		assert.Equal(t, []byte(`{"name": "mine"}`), obj.Content)
+	})
+
+	t.Run("prefers externalId over origin object ID", func(t *testing.T) {
+		withOrigin := *conf
+		withOrigin.OriginObjectId = "origin-id"
+
+		c := dtclient.NewMockClient(gomock.NewController(t))
+		c.EXPECT().ListSettings(gomock.Any(), gomock.Any(), gomock.Any()).Times(1).Return([]dtclient.DownloadSettingsObject{
+			{ObjectId: "origin-id", Value: []byte(`{"name": "origin"}`)},
+			{ExternalId: externalID, ObjectId: "object-id", Value: []byte(`{"name": "mine"}`)},
+		}, nil)
+
+		obj, found, err := Get(context.TODO(), c, &withOrigin)
+		assert.NoError(t, err)
+		assert.True(t, found)
+		assert.Equal(t, "object-id", obj.ID)
+	})
+
+	t.Run("not found if no object matches", func(t *testing.T) {
+		c := dtclient.NewMockClient(gomock.NewController(t))
+		c.EXPECT().ListSettings(gomock.Any(), gomock.Any(), gomock.Any()).Times(1).Return(nil, nil)
+
+		_, found, err := Get(context.TODO(), c, conf)
+		assert.NoError(t, err)
+		assert.False(t, found)
+	})
+
+	t.Run("returns error if listing fails", func(t *testing.T) {
+		c := dtclient.NewMockClient(gomock.NewController(t))
+		c.EXPECT().ListSettings(gomock.Any(), gomock.Any(), gomock.Any()).Times(1).Return(nil, errors.New("LIST_FAIL"))
+
+		_, _, err := Get(context.TODO(), c, conf)
+		assert.Error(t, err)
+	})
+}
+
+func TestDeployToKnownID(t *testing.T) {
+	conf := &config.Config{
+		Coordinate: coordinate.Coordinate{Project: "p", Type: "builtin:alerting.profile", ConfigId: "abcde"},
+		Type:       config.SettingsType{SchemaId: "builtin:alerting.profile"},
+	}
+	props := func() parameter.Properties {
+		return parameter.Properties{config.ScopeParameter: "environment", config.NameParameter: "name"}
+	}
+
+	t.Run("updates known object if it exists", func(t *testing.T) {
+		c := dtclient.NewMockClient(gomock.NewController(t))
+		c.EXPECT().ListSettings(gomock.Any(), "builtin:alerting.profile", gomock.Any()).Times(1).DoAndReturn(
+			func(_ context.Context, _ string, opts dtclient.ListSettingsOptions) ([]dtclient.DownloadSettingsObject, error) {
+				o := dtclient.DownloadSettingsObject{ObjectId: "known-id"}
+				if opts.Filter(o) {
+					return []dtclient.DownloadSettingsObject{o}, nil
+				}
+				return nil, nil
+			})
+		c.EXPECT().UpsertSettings(gomock.Any(), gomock.Any(), gomock.Any()).Times(1).DoAndReturn(
+			func(_ context.Context, obj dtclient.SettingsObject, _ dtclient.UpsertSettingsOptions) (dtclient.DynatraceEntity, error) {
+				assert.Equal(t, "known-id", obj.OriginObjectId)
+				return dtclient.DynatraceEntity{Id: "known-id"}, nil
+			})
+
+		resolved, err := DeployToKnownID(context.TODO(), c, props(), "{}", conf, "known-id")
+		assert.NoError(t, err)
+		assert.Equal(t, "known-id", resolved.Properties[config.IdParameter])
+		assert.Empty(t, conf.OriginObjectId, "given config must not be modified")
+	})
+
+	t.Run("deploys without known ID if object does not exist anymore", func(t *testing.T) {
+		c := dtclient.NewMockClient(gomock.NewController(t))
+		c.EXPECT().ListSettings(gomock.Any(), gomock.Any(), gomock.Any()).Times(1).Return(nil, nil)
+		c.EXPECT().UpsertSettings(gomock.Any(), gomock.Any(), gomock.Any()).Times(1).DoAndReturn(
+			func(_ context.Context, obj dtclient.SettingsObject, _ dtclient.UpsertSettingsOptions) (dtclient.DynatraceEntity, error) {
+				assert.Empty(t, obj.OriginObjectId)
+				return dtclient.DynatraceEntity{Id: "new-id"}, nil
+			})
+
+		resolved, err := DeployToKnownID(context.TODO(), c, props(), "{}", conf, "known-id")
+		assert.NoError(t, err)
+		assert.Equal(t, "new-id", resolved.Properties[config.IdParameter])
+	})
+
+	t.Run("origin object ID takes precedence over known ID", func(t *testing.T) {
+		withOrigin := *conf
+		withOrigin.OriginObjectId = "origin-id"
+
+		c := dtclient.NewMockClient(gomock.NewController(t))
+		c.EXPECT().UpsertSettings(gomock.Any(), gomock.Any(), gomock.Any()).Times(1).DoAndReturn(
+			func(_ context.Context, obj dtclient.SettingsObject, _ dtclient.UpsertSettingsOptions) (dtclient.DynatraceEntity, error) {
+				assert.Equal(t, "origin-id", obj.OriginObjectId)
+				return dtclient.DynatraceEntity{Id: "origin-id"}, nil
+			})
+
+		_, err := DeployToKnownID(context.TODO(), c, props(), "{}", &withOrigin, "known-id")
+		assert.NoError(t, err)
+	})
+}
+
+func TestGetByKnownID(t *testing.T) {
+	conf := &config.Config{
+		Coordinate: coordinate.Coordinate{Project: "p", Type: "builtin:alerting.profile", ConfigId: "abcde"},
+		Type:       config.SettingsType{SchemaId: "builtin:alerting.profile"},
+	}
+	externalID, _ := idutils.GenerateExternalID(conf.Coordinate)
+
+	listing := func(objects ...dtclient.DownloadSettingsObject) func(context.Context, string, dtclient.ListSettingsOptions) ([]dtclient.DownloadSettingsObject, error) {
+		return func(_ context.Context, _ string, opts dtclient.ListSettingsOptions) ([]dtclient.DownloadSettingsObject, error) {
+			var res []dtclient.DownloadSettingsObject
+			for _, o := range objects {
+				if opts.Filter(o) {
+					res = append(res, o)
+				}
+			}
+			return res, nil
+		}
+	}
+
+	t.Run("returns known object", func(t *testing.T) {
+		c := dtclient.NewMockClient(gomock.NewController(t))
+		c.EXPECT().ListSettings(gomock.Any(), "builtin:alerting.profile", gomock.Any()).Times(1).DoAndReturn(listing(
+			dtclient.DownloadSettingsObject{ExternalId: externalID, ObjectId: "object-id"},
+			dtclient.DownloadSettingsObject{ObjectId: "known-id", Value: []byte(`{"name": "known"}`)},
+		))
+
+		obj, found, err := GetByKnownID(context.TODO(), c, conf, "known-id")
+		assert.NoError(t, err)
+		assert.True(t, found)
+		assert.Equal(t, "known-id", obj.ID)
+		assert.Equal(t, []byte(`{"name": "known"}`), obj.Content)
+	})
+
+	t.Run("returns object matching the externalId if known object does not exist anymore", func(t *testing.T) {
+		c := dtclient.NewMockClient(gomock.NewController(t))
+		c.EXPECT().ListSettings(gomock.Any(), "builtin:alerting.profile", gomock.Any()).Times(2).DoAndReturn(listing(
+			dtclient.DownloadSettingsObject{ExternalId: externalID, ObjectId: "object-id"},
+		))
+
+		obj, found, err := GetByKnownID(context.TODO(), c, conf, "known-id")
+		assert.NoError(t, err)
+		assert.True(t, found)
+		assert.Equal(t, "object-id", obj.ID)
+	})
+}
@@ -0,0 +1,77 @@
+// @license
+// Copyright 2024 Dynatrace LLC
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deploy
+
+import (
+	"context"
+	"fmt"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/json"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/parameter"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/deploy/internal/remote"
+)
+
+// mergeWithRemote merges the rendered config into the object it is deployed to, if the config defines a config.Merge.
+// If the config does not define a merge, or its object does not exist yet, the rendered config is returned unchanged.
+//
+// If the config was deployed to a known object by a previous deployment, this object is fetched by its ID. As writing
+// the unmerged config would replace all properties not owned by the config, an error is returned if the known object
+// can not be fetched.
+func mergeWithRemote(ctx context.Context, c *config.Config, clients ClientSet, properties parameter.Properties, renderedConfig string, knownID string) (string, error) {
+	if c.Merge == nil {
+		return renderedConfig, nil
+	}
+
+	obj, found, err := getKnownRemote(ctx, c, clients, properties, knownID)
+	if err != nil {
+		return "", fmt.Errorf("failed to fetch object to merge config into: %w", err)
+	}
+	if !found && knownID != "" {
+		return "", fmt.Errorf("failed to fetch object %q to merge config into, which it was deployed to previously - remove the config from the deployment state to create a new object", knownID)
+	}
+	if !found {
+		return renderedConfig, nil
+	}
+
+	merged, err := mergeInto(c, renderedConfig, obj)
+	if err != nil {
+		return "", fmt.Errorf("failed to merge config into object %q: %w", obj.ID, err)
+	}
+	return merged, nil
+}
+
+// mergeInto merges the rendered config into the given remote object according to the config's config.Merge.
+// Properties managed by the Dynatrace server are removed from the remote object, as they can not be written.
+func mergeInto(c *config.Config, renderedConfig string, obj remote.Object) (string, error) {
+	current := obj.Content
+	if removeServerManaged := serverManagedPropertiesRemover(c); removeServerManaged != nil {
+		var err error
+		if current, err = removeProperties(current, removeServerManaged); err != nil {
+			return "", fmt.Errorf("failed to unmarshal current JSON: %w", err)
+		}
+	}
+
+	var merged []byte
+	var err error
+	if len(c.Merge.OwnedPaths) > 0 {
+		merged, err = json.MergeOwned([]byte(renderedConfig), current, c.Merge.OwnedPaths)
+	} else {
+		merged, err = json.MergeIgnored([]byte(renderedConfig), current, c.Merge.IgnoredPaths)
+	}
+	if err != nil {
+		return "", err
+	}
+	return string(merged), nil
+}
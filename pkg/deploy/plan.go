@@ -0,0 +1,390 @@
+// @license
+// Copyright 2024 Dynatrace LLC
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deploy
+
+import (
+	"context"
+	stdjson "encoding/json"
+	"errors"
+	"fmt"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/cmd/monaco/dynatrace"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/idutils"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/json"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/log"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/log/field"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/mutlierror"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/api"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/coordinate"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/entities"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/parameter"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/parameter/secret"
+	deployErrors "github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/deploy/errors"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/deploy/internal/automation"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/deploy/internal/bucket"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/deploy/internal/classic"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/deploy/internal/extract"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/deploy/internal/remote"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/deploy/internal/setting"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/deploy/internal/validate"
+	classicDownload "github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/download/classic"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/graph"
+	project "github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/project/v2"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/state"
+)
+
+// PlanAction is the action a deployment would take for a single configuration
+type PlanAction string
+
+const (
+	// PlanActionCreate states that no matching object exists in the environment and a new one would be created
+	PlanActionCreate PlanAction = "create"
+	// PlanActionUpdate states that a matching object exists in the environment, but differs from the configuration
+	PlanActionUpdate PlanAction = "update"
+	// PlanActionUnchanged states that a matching object exists in the environment and is equal to the configuration
+	PlanActionUnchanged PlanAction = "unchanged"
+	// PlanActionSkip states that the configuration would not be deployed, either because it is marked as skipped,
+	// because its deploy policy prevents creating or updating its object, or because it depends on a configuration that
+	// is skipped or failed to be planned
+	PlanActionSkip PlanAction = "skip"
+)
+
+// PlannedChange describes what a deployment would do with a single configuration in an environment
+type PlannedChange struct {
+	Coordinate  coordinate.Coordinate `json:"coordinate"`
+	Environment string                `json:"environment"`
+	Action      PlanAction            `json:"action"`
+	// RemoteId is the ID of the object currently present in the environment. It is empty if the object would be created.
+	RemoteId string `json:"remoteId,omitempty"`
+	// Diff contains all differences between the rendered configuration and the object present in the environment
+	Diff []json.Difference `json:"diff,omitempty"`
+	// Additional contains all properties only present on the object in the environment, e.g. defaults filled in by the
+	// Dynatrace server or properties added manually. They do not cause the object to be updated.
+	Additional []json.Difference `json:"additional,omitempty"`
+	// DeployPolicy is the deploy policy of the configuration. It is empty if the configuration uses the default policy.
+	DeployPolicy config.DeployPolicy `json:"deployPolicy,omitempty"`
+}
+
+// Plan renders all configurations for the given environments and compares them with the objects currently present in
+// each environment, without modifying anything. The returned changes describe what a deployment would do.
+//
+// As objects that would be created do not have an ID yet, references to them are resolved with a placeholder ID.
+// Properties managed by the Dynatrace server, like IDs or metadata, are not compared. Only properties defined by the
+// configuration decide whether an object would be updated - properties only present on the object are reported as
+// additional properties.
+// If deploymentState is not nil, configurations known from previous deployments are compared with the objects they were
+// deployed to, as a deployment would update these objects even if their names changed.
+// Errors for single configurations do not stop the planning; they are aggregated and returned after all environments
+// have been planned.
+func Plan(ctx context.Context, projects []project.Project, environmentClients dynatrace.EnvironmentClients, deploymentState *state.State) ([]PlannedChange, error) {
+	return plan(ctx, projects, environmentClients, deploymentState, logPlannedChange)
+}
+
+// plan compares all configurations with the objects present in the environments, and logs each compared configuration
+// using logChange
+func plan(ctx context.Context, projects []project.Project, environmentClients dynatrace.EnvironmentClients, deploymentState *state.State, logChange func(context.Context, PlannedChange)) ([]PlannedChange, error) {
+	ctx = secret.WithCache(ctx)
+
+	g := graph.New(projects, environmentClients.Names())
+	deploymentErrors := make(deployErrors.EnvironmentDeploymentErrors)
+
+	if validationErrs := validate.Validate(projects); validationErrs != nil {
+		errors.As(validationErrs, &deploymentErrors)
+	}
+
+	var changes []PlannedChange
+	for env, clients := range environmentClients {
+		if ctx.Err() != nil {
+			return changes, fmt.Errorf("planning cancelled: %w", ctx.Err())
+		}
+
+		ctx := createContextWithEnvironment(ctx, env)
+		log.WithCtxFields(ctx).Info("Comparing configurations with environment %q...", env.Name)
+
+		sortedConfigs, err := g.GetIndependentlySortedConfigs(env.Name)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get independently sorted configs for environment %q: %w", env.Name, err)
+		}
+
+		clientSet := ClientSet{
+			Classic:    clients.DTClient,
+			Settings:   clients.DTClient,
+			Automation: clients.AutClient,
+			Bucket:     clients.BucketClient,
+		}
+
+		envChanges, errs := planComponents(ctx, env.Name, sortedConfigs, clientSet, deploymentState, logChange)
+		changes = append(changes, envChanges...)
+		for _, err := range errs {
+			deploymentErrors = deploymentErrors.Append(env.Name, err)
+		}
+	}
+
+	if len(deploymentErrors) != 0 {
+		return changes, deploymentErrors
+	}
+
+	return changes, nil
+}
+
+func planComponents(ctx context.Context, environment string, components []graph.SortedComponent, clients ClientSet, deploymentState *state.State, logChange func(context.Context, PlannedChange)) ([]PlannedChange, []error) {
+	var changes []PlannedChange
+	var errs []error
+
+	resolvedEntities := entities.New()
+	notDeployed := make(map[coordinate.Coordinate]struct{})
+
+	for _, component := range components {
+		for _, node := range component.SortedNodes {
+			c := node.(graph.ConfigNode).Config
+			ctx := context.WithValue(ctx, log.CtxKeyCoord{}, c.Coordinate)
+
+			if dependsOnAny(c, notDeployed) {
+				log.WithCtxFields(ctx).WithFields(field.F("planAction", PlanActionSkip)).Warn("Would skip deployment of %v, as it depends on a configuration that would not be deployed", c.Coordinate)
+				notDeployed[c.Coordinate] = struct{}{}
+				changes = append(changes, PlannedChange{Coordinate: c.Coordinate, Environment: environment, Action: PlanActionSkip})
+				continue
+			}
+
+			change, resolvedEntity, err := planConfig(ctx, c, clients, resolvedEntities, knownRemoteID(deploymentState, environment, c), logChange)
+			if err != nil {
+				log.WithCtxFields(ctx).WithFields(field.Error(err)).Error("Failed to plan deployment: %v", err)
+				notDeployed[c.Coordinate] = struct{}{}
+				errs = append(errs, err)
+				continue
+			}
+			change.Environment = environment
+			changes = append(changes, change)
+
+			// configs skipped due to their deploy policy still resolve to their existing object
+			if change.Action == PlanActionSkip && change.RemoteId == "" {
+				notDeployed[c.Coordinate] = struct{}{}
+				continue
+			}
+			resolvedEntities.Put(resolvedEntity)
+		}
+	}
+
+	return changes, errs
+}
+
+func dependsOnAny(c *config.Config, coordinates map[coordinate.Coordinate]struct{}) bool {
+	for _, ref := range c.References() {
+		if _, found := coordinates[ref]; found {
+			return true
+		}
+	}
+	return false
+}
+
+func planConfig(ctx context.Context, c *config.Config, clients ClientSet, resolvedEntities config.EntityLookup, knownID string, logChange func(context.Context, PlannedChange)) (PlannedChange, entities.ResolvedEntity, error) {
+	if c.Skip {
+		log.WithCtxFields(ctx).WithFields(field.F("planAction", PlanActionSkip)).Info("Would skip deployment of config")
+		return PlannedChange{Coordinate: c.Coordinate, Action: PlanActionSkip}, entities.ResolvedEntity{}, nil
+	}
+
+	properties, errs := c.ResolveParameterValues(ctx, newEnvironmentLookup(ctx, resolvedEntities, clients.Classic))
+	if len(errs) > 0 {
+		return PlannedChange{}, entities.ResolvedEntity{}, mutlierror.New(errs...)
+	}
+
+	renderedConfig, err := c.Render(properties)
+	if err != nil {
+		return PlannedChange{}, entities.ResolvedEntity{}, err
+	}
+
+	obj, found, err := getKnownRemote(ctx, c, clients, properties, knownID)
+	if err != nil {
+		return PlannedChange{}, entities.ResolvedEntity{}, err
+	}
+
+	change := PlannedChange{Coordinate: c.Coordinate, DeployPolicy: c.DeployPolicy}
+	if skip, reason := skippedByDeployPolicy(c, found); skip {
+		if found {
+			change.RemoteId = obj.ID
+		}
+		change.Action = PlanActionSkip
+		log.WithCtxFields(ctx).WithFields(field.F("planAction", PlanActionSkip), field.F("deployPolicy", c.DeployPolicy)).Info("Would skip deployment of config, as %s", reason)
+		if !found {
+			return change, entities.ResolvedEntity{}, nil
+		}
+
+		resolved, err := resolveEntity(ctx, c, properties, obj)
+		if err != nil {
+			return PlannedChange{}, entities.ResolvedEntity{}, err
+		}
+		return change, resolved, nil
+	}
+
+	id := idutils.GenerateUUIDFromCoordinate(c.Coordinate) // placeholder, as objects to be created do not have an ID yet
+	if found {
+		payload := renderedConfig
+		if c.Merge != nil {
+			if payload, err = mergeInto(c, renderedConfig, obj); err != nil {
+				return PlannedChange{}, entities.ResolvedEntity{}, fmt.Errorf("failed to merge config into object %q: %w", obj.ID, err)
+			}
+		}
+
+		diff, additional, err := diffRemote(c, []byte(payload), obj.Content)
+		if err != nil {
+			return PlannedChange{}, entities.ResolvedEntity{}, fmt.Errorf("failed to compare config with object %q: %w", obj.ID, err)
+		}
+
+		id = obj.ID
+		change.RemoteId = obj.ID
+		change.Diff = diff
+		change.Additional = additional
+		change.Action = PlanActionUpdate
+		if len(diff) == 0 {
+			change.Action = PlanActionUnchanged
+		}
+	} else {
+		diff, err := json.Diff([]byte(renderedConfig), nil)
+		if err != nil {
+			return PlannedChange{}, entities.ResolvedEntity{}, err
+		}
+		change.Diff = diff
+		change.Action = PlanActionCreate
+	}
+
+	logChange(ctx, change)
+
+	name := id
+	if configName, err := extract.ConfigName(c, properties); err == nil {
+		name = configName
+	}
+	properties[config.IdParameter] = id
+	properties[config.NameParameter] = name
+
+	return change, entities.ResolvedEntity{
+		EntityName: name,
+		Coordinate: c.Coordinate,
+		Properties: properties,
+	}, nil
+}
+
+func getRemote(ctx context.Context, c *config.Config, clients ClientSet, properties parameter.Properties) (remote.Object, bool, error) {
+	switch c.Type.(type) {
+	case config.SettingsType:
+		return setting.Get(ctx, clients.Settings, c)
+
+	case config.ClassicApiType:
+		return classic.Get(ctx, clients.Classic, api.NewAPIs(), properties, c)
+
+	case config.AutomationType:
+		return automation.Get(ctx, clients.Automation, c)
+
+	case config.BucketType:
+		return bucket.Get(ctx, clients.Bucket, c)
+
+	default:
+		return remote.Object{}, false, fmt.Errorf("unknown config-type (ID: %q)", c.Type.ID())
+	}
+}
+
+// getKnownRemote fetches the remote object the given config is deployed to like getRemote. If the config was deployed
+// to a known object by a previous deployment, this object is fetched by its ID, as deploying the config updates it even
+// if the config's name changed since.
+func getKnownRemote(ctx context.Context, c *config.Config, clients ClientSet, properties parameter.Properties, knownID string) (remote.Object, bool, error) {
+	switch c.Type.(type) {
+	case config.SettingsType:
+		return setting.GetByKnownID(ctx, clients.Settings, c, knownID)
+
+	case config.ClassicApiType:
+		return classic.GetByKnownID(ctx, clients.Classic, api.NewAPIs(), properties, c, knownID)
+
+	// automation objects and buckets are deployed with IDs derived from their coordinate, which are always known
+	default:
+		return getRemote(ctx, c, clients, properties)
+	}
+}
+
+// diffRemote compares a rendered configuration with the content of its remote object. Properties managed by the
+// Dynatrace server are removed from both before comparing them, as they can not be defined by a configuration.
+// Only the properties of the configuration are compared - properties only present on the remote object are returned
+// separately as additional properties, as the server fills in defaults for properties a configuration does not define,
+// e.g. for Settings objects.
+func diffRemote(c *config.Config, desired, current []byte) (diff []json.Difference, additional []json.Difference, err error) {
+	if removeServerManaged := serverManagedPropertiesRemover(c); removeServerManaged != nil {
+		if desired, err = removeProperties(desired, removeServerManaged); err != nil {
+			return nil, nil, fmt.Errorf("failed to unmarshal desired JSON: %w", err)
+		}
+		if current, err = removeProperties(current, removeServerManaged); err != nil {
+			return nil, nil, fmt.Errorf("failed to unmarshal current JSON: %w", err)
+		}
+	}
+
+	if diff, err = json.Diff(desired, current); err != nil {
+		return nil, nil, err
+	}
+	if additional, err = json.Additional(desired, current); err != nil {
+		return nil, nil, err
+	}
+	return diff, additional, nil
+}
+
+// serverManagedPropertiesRemover returns a function removing all properties managed by the Dynatrace server from an
+// object of the given config's type. It returns nil if the type has no such properties.
+func serverManagedPropertiesRemover(c *config.Config) func(map[string]any) {
+	switch t := c.Type.(type) {
+	case config.ClassicApiType:
+		if a, found := api.NewAPIs()[t.Api]; found {
+			return func(m map[string]any) { classicDownload.RemoveServerManagedProperties(m, a) }
+		}
+	case config.AutomationType:
+		// same properties as removed when downloading automation objects
+		return func(m map[string]any) {
+			delete(m, "id")
+			delete(m, "modificationInfo")
+			delete(m, "lastExecution")
+		}
+	}
+	return nil
+}
+
+// removeProperties applies remove to the given JSON payload, if it is a JSON object. Other payloads are returned unchanged.
+func removeProperties(payload []byte, remove func(map[string]any)) ([]byte, error) {
+	var m map[string]any
+	if err := stdjson.Unmarshal(payload, &m); err != nil {
+		var typeErr *stdjson.UnmarshalTypeError
+		if errors.As(err, &typeErr) {
+			return payload, nil
+		}
+		return nil, err
+	}
+	if m == nil {
+		return payload, nil
+	}
+
+	remove(m)
+	return stdjson.Marshal(m)
+}
+
+func logPlannedChange(ctx context.Context, change PlannedChange) {
+	l := log.WithCtxFields(ctx).WithFields(field.F("planAction", change.Action), field.F("remoteId", change.RemoteId), field.F("diff", change.Diff), field.F("additional", change.Additional))
+
+	switch change.Action {
+	case PlanActionCreate:
+		l.Info("Would create new object")
+	case PlanActionUnchanged:
+		l.Info("Object %q is up to date", change.RemoteId)
+	case PlanActionUpdate:
+		diffs := ""
+		for _, d := range change.Diff {
+			diffs += fmt.Sprintf("\n\t%s", d)
+		}
+		l.Info("Would update object %q:%s", change.RemoteId, diffs)
+	}
+}
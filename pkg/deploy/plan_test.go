@@ -0,0 +1,378 @@
+//go:build unit
+
+// @license
+// Copyright 2024 Dynatrace LLC
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deploy_test
+
+import (
+	"context"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/cmd/monaco/dynatrace"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/idutils"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/json"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/client"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/client/dtclient"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/coordinate"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/parameter/reference"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/parameter/value"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/template"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/deploy"
+	project "github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/project/v2"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/state"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"testing"
+)
+
+func TestPlan(t *testing.T) {
+	existingSetting := coordinate.Coordinate{Project: "proj", Type: "builtin:alerting.profile", ConfigId: "existing"}
+	newSetting := coordinate.Coordinate{Project: "proj", Type: "builtin:alerting.profile", ConfigId: "new"}
+	autoTag := coordinate.Coordinate{Project: "proj", Type: "auto-tag", ConfigId: "auto-tag"}
+	skipped := coordinate.Coordinate{Project: "proj", Type: "auto-tag", ConfigId: "skipped"}
+	dependsOnSkipped := coordinate.Coordinate{Project: "proj", Type: "auto-tag", ConfigId: "depends-on-skipped"}
+
+	existingExternalID, _ := idutils.GenerateExternalID(existingSetting)
+
+	configs := project.ConfigsPerType{
+		"builtin:alerting.profile": []config.Config{
+			{
+				Coordinate:  existingSetting,
+				Type:        config.SettingsType{SchemaId: "builtin:alerting.profile"},
+				Template:    template.NewInMemoryTemplate("existing", `{"name": "{{ .name }}", "enabled": true}`),
+				Environment: "env",
+				Parameters: config.Parameters{
+					config.NameParameter:  value.New("new-name"),
+					config.ScopeParameter: value.New("environment"),
+				},
+			},
+			{
+				Coordinate:  newSetting,
+				Type:        config.SettingsType{SchemaId: "builtin:alerting.profile"},
+				Template:    template.NewInMemoryTemplate("new", `{"name": "{{ .name }}"}`),
+				Environment: "env",
+				Parameters: config.Parameters{
+					config.NameParameter:  value.New("new"),
+					config.ScopeParameter: value.New("environment"),
+				},
+			},
+		},
+		"auto-tag": []config.Config{
+			{
+				Coordinate:  autoTag,
+				Type:        config.ClassicApiType{Api: "auto-tag"},
+				Template:    template.NewInMemoryTemplate("auto-tag", `{"name": "{{ .name }}", "profile": "{{ .profile }}"}`),
+				Environment: "env",
+				Parameters: config.Parameters{
+					config.NameParameter: value.New("my-auto-tag"),
+					"profile":            reference.NewWithCoordinate(existingSetting, config.IdParameter),
+				},
+			},
+			{
+				Coordinate:  skipped,
+				Type:        config.ClassicApiType{Api: "auto-tag"},
+				Template:    template.NewInMemoryTemplate("skipped", `{}`),
+				Environment: "env",
+				Parameters: config.Parameters{
+					config.NameParameter: value.New("skipped"),
+				},
+				Skip: true,
+			},
+			{
+				Coordinate:  dependsOnSkipped,
+				Type:        config.ClassicApiType{Api: "auto-tag"},
+				Template:    template.NewInMemoryTemplate("depends-on-skipped", `{"ref": "{{ .ref }}"}`),
+				Environment: "env",
+				Parameters: config.Parameters{
+					config.NameParameter: value.New("depends-on-skipped"),
+					"ref":                reference.NewWithCoordinate(skipped, config.IdParameter),
+				},
+			},
+		},
+	}
+
+	p := []project.Project{
+		{
+			Id:      "proj",
+			Configs: project.ConfigsPerTypePerEnvironments{"env": configs},
+		},
+	}
+
+	c := dtclient.NewMockClient(gomock.NewController(t))
+	c.EXPECT().ListSettings(gomock.Any(), "builtin:alerting.profile", gomock.Any()).AnyTimes().DoAndReturn(
+		func(_ context.Context, _ string, opts dtclient.ListSettingsOptions) ([]dtclient.DownloadSettingsObject, error) {
+			o := dtclient.DownloadSettingsObject{
+				ExternalId: existingExternalID,
+				ObjectId:   "existing-object-id",
+				Value:      []byte(`{"name": "old-name", "enabled": true}`),
+			}
+			if opts.Filter(o) {
+				return []dtclient.DownloadSettingsObject{o}, nil
+			}
+			return nil, nil
+		})
+	c.EXPECT().ConfigExistsByName(gomock.Any(), gomock.Any(), "my-auto-tag").Times(1).Return(true, "auto-tag-id", nil)
+	c.EXPECT().ReadConfigById(gomock.Any(), "auto-tag-id").Times(1).Return([]byte(`{"id": "auto-tag-id", "name": "my-auto-tag", "profile": "existing-object-id"}`), nil)
+
+	clients := dynatrace.EnvironmentClients{
+		dynatrace.EnvironmentInfo{Name: "env"}: &client.ClientSet{DTClient: c},
+	}
+
+	changes, err := deploy.Plan(context.TODO(), p, clients, nil)
+	require.NoError(t, err)
+
+	assert.ElementsMatch(t, []deploy.PlannedChange{
+		{
+			Coordinate:  existingSetting,
+			Environment: "env",
+			Action:      deploy.PlanActionUpdate,
+			RemoteId:    "existing-object-id",
+			Diff:        []json.Difference{{Path: "/name", Current: "old-name", Desired: "new-name"}},
+		},
+		{
+			Coordinate:  newSetting,
+			Environment: "env",
+			Action:      deploy.PlanActionCreate,
+			Diff:        []json.Difference{{Path: "/", Current: nil, Desired: map[string]any{"name": "new"}}},
+		},
+		{
+			Coordinate:  autoTag,
+			Environment: "env",
+			Action:      deploy.PlanActionUnchanged,
+			RemoteId:    "auto-tag-id",
+			Diff:        nil,
+		},
+		{
+			Coordinate:  skipped,
+			Environment: "env",
+			Action:      deploy.PlanActionSkip,
+		},
+		{
+			Coordinate:  dependsOnSkipped,
+			Environment: "env",
+			Action:      deploy.PlanActionSkip,
+		},
+	}, changes)
+}
+
+func TestPlan_ReturnsErrorsButContinues(t *testing.T) {
+	broken := coordinate.Coordinate{Project: "proj", Type: "auto-tag", ConfigId: "broken"}
+	dependent := coordinate.Coordinate{Project: "proj", Type: "auto-tag", ConfigId: "dependent"}
+	independent := coordinate.Coordinate{Project: "proj", Type: "auto-tag", ConfigId: "independent"}
+
+	p := []project.Project{
+		{
+			Id: "proj",
+			Configs: project.ConfigsPerTypePerEnvironments{"env": project.ConfigsPerType{
+				"auto-tag": []config.Config{
+					{
+						Coordinate:  broken,
+						Type:        config.ClassicApiType{Api: "auto-tag"},
+						Template:    template.NewInMemoryTemplate("broken", `{"name": "{{ .doesNotExist }}"}`),
+						Environment: "env",
+						Parameters:  config.Parameters{config.NameParameter: value.New("broken")},
+					},
+					{
+						Coordinate:  dependent,
+						Type:        config.ClassicApiType{Api: "auto-tag"},
+						Template:    template.NewInMemoryTemplate("dependent", `{"ref": "{{ .ref }}"}`),
+						Environment: "env",
+						Parameters: config.Parameters{
+							config.NameParameter: value.New("dependent"),
+							"ref":                reference.NewWithCoordinate(broken, config.IdParameter),
+						},
+					},
+					{
+						Coordinate:  independent,
+						Type:        config.ClassicApiType{Api: "auto-tag"},
+						Template:    template.NewInMemoryTemplate("independent", `{"name": "{{ .name }}"}`),
+						Environment: "env",
+						Parameters:  config.Parameters{config.NameParameter: value.New("independent")},
+					},
+				},
+			}},
+		},
+	}
+
+	c := dtclient.NewMockClient(gomock.NewController(t))
+	c.EXPECT().ConfigExistsByName(gomock.Any(), gomock.Any(), "independent").Times(1).Return(false, "", nil)
+
+	clients := dynatrace.EnvironmentClients{
+		dynatrace.EnvironmentInfo{Name: "env"}: &client.ClientSet{DTClient: c},
+	}
+
+	changes, err := deploy.Plan(context.TODO(), p, clients, nil)
+	assert.Error(t, err)
+
+	assert.ElementsMatch(t, []deploy.PlannedChange{
+		{
+			Coordinate:  dependent,
+			Environment: "env",
+			Action:      deploy.PlanActionSkip,
+		},
+		{
+			Coordinate:  independent,
+			Environment: "env",
+			Action:      deploy.PlanActionCreate,
+			Diff:        []json.Difference{{Path: "/", Current: nil, Desired: map[string]any{"name": "independent"}}},
+		},
+	}, changes)
+}
+
+func TestPlan_DeployPolicy(t *testing.T) {
+	createOnly := coordinate.Coordinate{Project: "proj", Type: "auto-tag", ConfigId: "create-only"}
+	missing := coordinate.Coordinate{Project: "proj", Type: "auto-tag", ConfigId: "missing"}
+	child := coordinate.Coordinate{Project: "proj", Type: "auto-tag", ConfigId: "child"}
+	orphan := coordinate.Coordinate{Project: "proj", Type: "auto-tag", ConfigId: "orphan"}
+
+	p := []project.Project{
+		{
+			Id: "proj",
+			Configs: project.ConfigsPerTypePerEnvironments{"env": project.ConfigsPerType{
+				"auto-tag": []config.Config{
+					{
+						Coordinate:   createOnly,
+						Type:         config.ClassicApiType{Api: "auto-tag"},
+						Template:     template.NewInMemoryTemplate("create-only", `{"name": "{{ .name }}"}`),
+						Environment:  "env",
+						Parameters:   config.Parameters{config.NameParameter: value.New("create-only")},
+						DeployPolicy: config.DeployPolicyCreateOnly,
+					},
+					{
+						Coordinate:   missing,
+						Type:         config.ClassicApiType{Api: "auto-tag"},
+						Template:     template.NewInMemoryTemplate("missing", `{"name": "{{ .name }}"}`),
+						Environment:  "env",
+						Parameters:   config.Parameters{config.NameParameter: value.New("missing")},
+						DeployPolicy: config.DeployPolicyUpdateOnly,
+					},
+					{
+						Coordinate:  child,
+						Type:        config.ClassicApiType{Api: "auto-tag"},
+						Template:    template.NewInMemoryTemplate("child", `{"ref": "{{ .ref }}"}`),
+						Environment: "env",
+						Parameters: config.Parameters{
+							config.NameParameter: value.New("child"),
+							"ref":                reference.NewWithCoordinate(createOnly, config.IdParameter),
+						},
+					},
+					{
+						Coordinate:  orphan,
+						Type:        config.ClassicApiType{Api: "auto-tag"},
+						Template:    template.NewInMemoryTemplate("orphan", `{"ref": "{{ .ref }}"}`),
+						Environment: "env",
+						Parameters: config.Parameters{
+							config.NameParameter: value.New("orphan"),
+							"ref":                reference.NewWithCoordinate(missing, config.IdParameter),
+						},
+					},
+				},
+			}},
+		},
+	}
+
+	c := dtclient.NewMockClient(gomock.NewController(t))
+	c.EXPECT().ConfigExistsByName(gomock.Any(), gomock.Any(), "create-only").Times(1).Return(true, "create-only-id", nil)
+	c.EXPECT().ReadConfigById(gomock.Any(), "create-only-id").Times(1).Return([]byte(`{"name": "changed by a product team"}`), nil)
+	c.EXPECT().ConfigExistsByName(gomock.Any(), gomock.Any(), "missing").Times(1).Return(false, "", nil)
+	c.EXPECT().ConfigExistsByName(gomock.Any(), gomock.Any(), "child").Times(1).Return(false, "", nil)
+
+	clients := dynatrace.EnvironmentClients{
+		dynatrace.EnvironmentInfo{Name: "env"}: &client.ClientSet{DTClient: c},
+	}
+
+	changes, err := deploy.Plan(context.TODO(), p, clients, nil)
+	require.NoError(t, err)
+
+	assert.ElementsMatch(t, []deploy.PlannedChange{
+		{
+			Coordinate:   createOnly,
+			Environment:  "env",
+			Action:       deploy.PlanActionSkip,
+			RemoteId:     "create-only-id",
+			DeployPolicy: config.DeployPolicyCreateOnly,
+		},
+		{
+			Coordinate:   missing,
+			Environment:  "env",
+			Action:       deploy.PlanActionSkip,
+			DeployPolicy: config.DeployPolicyUpdateOnly,
+		},
+		{
+			Coordinate:  child,
+			Environment: "env",
+			Action:      deploy.PlanActionCreate,
+			Diff:        []json.Difference{{Path: "/", Current: nil, Desired: map[string]any{"ref": "create-only-id"}}},
+		},
+		{
+			Coordinate:  orphan,
+			Environment: "env",
+			Action:      deploy.PlanActionSkip,
+		},
+	}, changes)
+}
+
+func TestPlan_ComparesObjectsKnownFromDeploymentState(t *testing.T) {
+	renamed := coordinate.Coordinate{Project: "proj", Type: "auto-tag", ConfigId: "renamed"}
+
+	p := []project.Project{
+		{
+			Id: "proj",
+			Configs: project.ConfigsPerTypePerEnvironments{"env": project.ConfigsPerType{
+				"auto-tag": []config.Config{
+					{
+						Coordinate:  renamed,
+						Type:        config.ClassicApiType{Api: "auto-tag"},
+						Template:    template.NewInMemoryTemplate("renamed", `{"name": "{{ .name }}"}`),
+						Environment: "env",
+						Parameters:  config.Parameters{config.NameParameter: value.New("new-name")},
+					},
+				},
+			}},
+		},
+	}
+
+	s := state.New()
+	s.Set("env", state.Entry{Coordinate: renamed, ConfigType: string(config.ClassicApiType{}.ID()), RemoteId: "known-id"})
+
+	// the object must not be looked up by its new name, which does not exist yet
+	newClients := func(t *testing.T) dynatrace.EnvironmentClients {
+		c := dtclient.NewMockClient(gomock.NewController(t))
+		c.EXPECT().ListConfigs(gomock.Any(), gomock.Any()).AnyTimes().Return([]dtclient.Value{{Id: "known-id", Name: "old-name"}}, nil)
+		c.EXPECT().ReadConfigById(gomock.Any(), "known-id").Times(1).Return([]byte(`{"name": "old-name"}`), nil)
+		return dynatrace.EnvironmentClients{
+			dynatrace.EnvironmentInfo{Name: "env"}: &client.ClientSet{DTClient: c},
+		}
+	}
+	diff := []json.Difference{{Path: "/name", Current: "old-name", Desired: "new-name"}}
+
+	t.Run("plan updates the known object", func(t *testing.T) {
+		changes, err := deploy.Plan(context.TODO(), p, newClients(t), s)
+		require.NoError(t, err)
+
+		assert.Equal(t, []deploy.PlannedChange{
+			{Coordinate: renamed, Environment: "env", Action: deploy.PlanActionUpdate, RemoteId: "known-id", Diff: diff},
+		}, changes)
+	})
+
+	t.Run("drift reports the known object as drifted instead of missing", func(t *testing.T) {
+		results, err := deploy.Drift(context.TODO(), p, newClients(t), s)
+		require.NoError(t, err)
+
+		assert.Equal(t, []deploy.DriftResult{
+			{Coordinate: renamed, Environment: "env", Status: deploy.DriftStatusDrifted, RemoteId: "known-id", Diff: diff},
+		}, results)
+	})
+}
@@ -0,0 +1,87 @@
+// @license
+// Copyright 2024 Dynatrace LLC
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deploy
+
+import (
+	"context"
+	"fmt"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/entities"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/parameter"
+)
+
+// errObjectMissing is returned for configs with config.DeployPolicyUpdateOnly whose object does not exist. As the
+// config is skipped, configs depending on it are skipped as well.
+var errObjectMissing = fmt.Errorf("object does not exist: %w", skipError)
+
+// checkDeployPolicy looks up the object the given config is deployed to, if the deploy policy of the config depends on
+// whether it exists. Lookups use the same client calls as deploying the config, so this works for all config types.
+// If the config was deployed to a known object by a previous deployment, this object is looked up by its ID first.
+//
+// For config.DeployPolicyCreateOnly, the entity of the existing object is returned and exists is true - the config must
+// not be written. For config.DeployPolicyUpdateOnly, errObjectMissing is returned if the object does not exist.
+func checkDeployPolicy(ctx context.Context, c *config.Config, clients ClientSet, properties parameter.Properties, knownID string) (existing entities.ResolvedEntity, exists bool, err error) {
+	policy := c.EffectiveDeployPolicy()
+	if policy == config.DeployPolicyUpsert {
+		return entities.ResolvedEntity{}, false, nil
+	}
+
+	obj, found, err := getKnownRemote(ctx, c, clients, properties, knownID)
+	if err != nil {
+		return entities.ResolvedEntity{}, false, fmt.Errorf("failed to look up object for deploy policy %q: %w", policy, err)
+	}
+
+	switch policy {
+	case config.DeployPolicyCreateOnly:
+		if !found {
+			return entities.ResolvedEntity{}, false, nil
+		}
+		existing, err := resolveEntity(ctx, c, properties, obj)
+		if err != nil {
+			return entities.ResolvedEntity{}, false, err
+		}
+		return existing, true, nil
+
+	case config.DeployPolicyUpdateOnly:
+		if !found {
+			return entities.ResolvedEntity{}, false, errObjectMissing
+		}
+		return entities.ResolvedEntity{}, false, nil
+
+	default:
+		return entities.ResolvedEntity{}, false, fmt.Errorf("unknown deploy policy %q", policy)
+	}
+}
+
+// skippedByDeployPolicy returns whether the deploy policy of the given config prevents deploying it, depending on
+// whether its object exists, and the reason for it
+func skippedByDeployPolicy(c *config.Config, exists bool) (bool, string) {
+	switch c.EffectiveDeployPolicy() {
+	case config.DeployPolicyCreateOnly:
+		return exists, deployPolicySkipReason(c)
+	case config.DeployPolicyUpdateOnly:
+		return !exists, deployPolicySkipReason(c)
+	default:
+		return false, ""
+	}
+}
+
+// deployPolicySkipReason returns why a config was not written due to its deploy policy
+func deployPolicySkipReason(c *config.Config) string {
+	if c.EffectiveDeployPolicy() == config.DeployPolicyUpdateOnly {
+		return fmt.Sprintf("deploy policy %q prevents creating the object, as it does not exist", config.DeployPolicyUpdateOnly)
+	}
+	return fmt.Sprintf("deploy policy %q prevents updating the object, as it already exists", config.DeployPolicyCreateOnly)
+}
@@ -0,0 +1,242 @@
+// @license
+// Copyright 2024 Dynatrace LLC
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deploy
+
+import (
+	"context"
+	"fmt"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/cmd/monaco/dynatrace"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/idutils"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/log"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/log/field"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/api"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/client"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/client/dtclient"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/coordinate"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/delete"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/delete/pointer"
+	deployErrors "github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/deploy/errors"
+	project "github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/project/v2"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/state"
+	"slices"
+	"strings"
+)
+
+// PruneOptions defines additional options used by Prune
+type PruneOptions struct {
+	// DryRun states that objects to prune are only reported, but not deleted
+	DryRun bool
+	// State holds the remote objects configurations were deployed to by previous deployments. Classic, Automation
+	// and Bucket objects can only be pruned if they are recorded in the state. If State is nil, only Settings objects
+	// are pruned.
+	State *state.State
+}
+
+// PruneCandidate is a remote object previously deployed by monaco, for which no configuration exists anymore
+type PruneCandidate struct {
+	Coordinate  coordinate.Coordinate `json:"coordinate"`
+	Environment string                `json:"environment"`
+	ConfigType  config.TypeId         `json:"configType"`
+	RemoteId    string                `json:"remoteId"`
+}
+
+// Prune deletes all remote objects that were deployed by monaco for one of the given projects, but have no matching
+// configuration in the projects anymore. Objects are owned by a project if they were deployed from one of its
+// configurations:
+//   - Settings objects are identified by the externalId monaco generates from a configuration's coordinate. Only the
+//     schemas used by the given projects, or recorded in the state, are searched.
+//   - Classic configurations are identified by the ID recorded in the state.
+//   - Automation objects and Buckets are identified by the ID recorded in the state, if it is the ID monaco generates
+//     from the configuration's coordinate.
+//
+// Configurations that are skipped are not pruned. All found candidates are returned - in dry-run mode nothing is deleted.
+// Pruned objects are removed from the state.
+func Prune(ctx context.Context, projects []project.Project, environmentClients dynatrace.EnvironmentClients, opts PruneOptions) ([]PruneCandidate, error) {
+	projectIDs := make(map[string]struct{}, len(projects))
+	var settingsSchemas []string
+	for _, p := range projects {
+		projectIDs[p.Id] = struct{}{}
+		p.ForEveryConfigDo(func(c config.Config) {
+			if t, ok := c.Type.(config.SettingsType); ok && !slices.Contains(settingsSchemas, t.SchemaId) {
+				settingsSchemas = append(settingsSchemas, t.SchemaId)
+			}
+		})
+	}
+
+	deploymentErrors := make(deployErrors.EnvironmentDeploymentErrors)
+	var candidates []PruneCandidate
+	for env, clients := range environmentClients {
+		if ctx.Err() != nil {
+			return candidates, fmt.Errorf("pruning cancelled: %w", ctx.Err())
+		}
+
+		ctx := createContextWithEnvironment(ctx, env)
+
+		existing := make(map[coordinate.Coordinate]struct{})
+		for _, p := range projects {
+			for _, configs := range p.Configs[env.Name] {
+				for _, c := range configs {
+					existing[c.Coordinate] = struct{}{}
+				}
+			}
+		}
+
+		envCandidates, err := findPruneCandidates(ctx, env.Name, clients, projectIDs, existing, settingsSchemas, opts.State)
+		if err != nil {
+			deploymentErrors = deploymentErrors.Append(env.Name, err)
+			continue
+		}
+		candidates = append(candidates, envCandidates...)
+
+		logPruneCandidates(ctx, envCandidates, opts.DryRun)
+		if opts.DryRun || len(envCandidates) == 0 {
+			continue
+		}
+
+		if err := pruneCandidates(ctx, clients, envCandidates); err != nil {
+			log.WithCtxFields(ctx).WithFields(field.Error(err)).Error("Failed to prune objects from environment %q: %v", env.Name, err)
+			deploymentErrors = deploymentErrors.Append(env.Name, err)
+			continue
+		}
+
+		if opts.State != nil {
+			for _, c := range envCandidates {
+				opts.State.Remove(env.Name, c.Coordinate)
+			}
+		}
+	}
+
+	if len(deploymentErrors) != 0 {
+		return candidates, deploymentErrors
+	}
+	return candidates, nil
+}
+
+func findPruneCandidates(ctx context.Context, environment string, clients *client.ClientSet, projectIDs map[string]struct{}, existing map[coordinate.Coordinate]struct{}, settingsSchemas []string, st *state.State) ([]PruneCandidate, error) {
+	owned := func(c coordinate.Coordinate) bool {
+		_, ownedProject := projectIDs[c.Project]
+		_, exists := existing[c]
+		return ownedProject && !exists
+	}
+
+	var entries []state.Entry
+	if st != nil {
+		entries = st.Entries(environment)
+	} else {
+		log.WithCtxFields(ctx).Warn("No deployment state defined - only Settings objects are pruned")
+	}
+
+	schemas := slices.Clone(settingsSchemas)
+	for _, e := range entries {
+		if e.ConfigType == string(config.SettingsTypeId) && !slices.Contains(schemas, e.Coordinate.Type) {
+			schemas = append(schemas, e.Coordinate.Type)
+		}
+	}
+
+	var candidates []PruneCandidate
+	for _, schema := range schemas {
+		objects, err := clients.DTClient.ListSettings(ctx, schema, dtclient.ListSettingsOptions{
+			DiscardValue: true,
+			Filter: func(o dtclient.DownloadSettingsObject) bool {
+				return strings.HasPrefix(o.ExternalId, "monaco:")
+			},
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list Settings objects of schema %q: %w", schema, err)
+		}
+
+		for _, o := range objects {
+			if c, ok := idutils.CoordinateFromExternalID(o.ExternalId); ok && c.Type == schema && owned(c) {
+				candidates = append(candidates, PruneCandidate{Coordinate: c, Environment: environment, ConfigType: config.SettingsTypeId, RemoteId: o.ObjectId})
+			}
+		}
+	}
+
+	apis := api.NewAPIs()
+	for _, e := range entries {
+		if !owned(e.Coordinate) {
+			continue
+		}
+
+		var ownedByMonaco bool
+		switch config.TypeId(e.ConfigType) {
+		case config.SettingsTypeId:
+			continue // already found by their externalId
+		case config.ClassicApiTypeId:
+			a, found := apis[e.Coordinate.Type]
+			ownedByMonaco = found && !a.SingleConfiguration && !a.HasParent()
+		case config.AutomationTypeId:
+			ownedByMonaco = e.RemoteId == idutils.GenerateUUIDFromCoordinate(e.Coordinate)
+		case config.BucketTypeId:
+			ownedByMonaco = e.RemoteId == idutils.GenerateBucketName(e.Coordinate)
+		}
+
+		if !ownedByMonaco {
+			log.WithCtxFields(ctx).WithFields(field.Coordinate(e.Coordinate)).Debug("Not pruning %q, as its object %q can not be safely identified", e.Coordinate, e.RemoteId)
+			continue
+		}
+		candidates = append(candidates, PruneCandidate{Coordinate: e.Coordinate, Environment: environment, ConfigType: config.TypeId(e.ConfigType), RemoteId: e.RemoteId})
+	}
+
+	slices.SortFunc(candidates, func(a, b PruneCandidate) int {
+		return strings.Compare(a.Coordinate.String(), b.Coordinate.String())
+	})
+	return candidates, nil
+}
+
+// pruneCandidates deletes the given candidates using the same logic as 'monaco delete'
+func pruneCandidates(ctx context.Context, clients *client.ClientSet, candidates []PruneCandidate) error {
+	entries := make(delete.DeleteEntries)
+	for _, c := range candidates {
+		ptr := pointer.DeletePointer{
+			Project:    c.Coordinate.Project,
+			Type:       c.Coordinate.Type,
+			Identifier: c.Coordinate.ConfigId,
+		}
+		// classic configurations are deleted by their name or ID - as the name may have changed, the known ID is used
+		if c.ConfigType == config.ClassicApiTypeId {
+			ptr.Identifier = c.RemoteId
+		}
+		entries[c.Coordinate.Type] = append(entries[c.Coordinate.Type], ptr)
+	}
+
+	automationResources := map[string]config.AutomationResource{
+		string(config.Workflow):         config.Workflow,
+		string(config.BusinessCalendar): config.BusinessCalendar,
+		string(config.SchedulingRule):   config.SchedulingRule,
+	}
+
+	deleteClients := delete.ClientSet{
+		Classic:    clients.Classic(),
+		Settings:   clients.Settings(),
+		Automation: clients.Automation(),
+		Buckets:    clients.Bucket(),
+	}
+
+	return delete.Configs(ctx, deleteClients, api.NewAPIs(), automationResources, entries)
+}
+
+func logPruneCandidates(ctx context.Context, candidates []PruneCandidate, dryRun bool) {
+	verb := "Pruning"
+	if dryRun {
+		verb = "Would prune"
+	}
+
+	log.WithCtxFields(ctx).Info("%s %d object(s) without matching configuration", verb, len(candidates))
+	for _, c := range candidates {
+		log.WithCtxFields(ctx).WithFields(field.Coordinate(c.Coordinate), field.F("remoteId", c.RemoteId)).Info("\t%s (%s)", c.Coordinate, c.RemoteId)
+	}
+}
@@ -0,0 +1,161 @@
+//go:build unit
+
+// @license
+// Copyright 2024 Dynatrace LLC
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deploy_test
+
+import (
+	"context"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/cmd/monaco/dynatrace"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/idutils"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/api"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/client"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/client/dtclient"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/coordinate"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/parameter/value"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/template"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/deploy"
+	project "github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/project/v2"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/state"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"testing"
+)
+
+var (
+	pruneExistingSetting = coordinate.Coordinate{Project: "proj", Type: "builtin:alerting.profile", ConfigId: "existing"}
+	pruneRemovedSetting  = coordinate.Coordinate{Project: "proj", Type: "builtin:alerting.profile", ConfigId: "removed"}
+	pruneOtherProject    = coordinate.Coordinate{Project: "other", Type: "builtin:alerting.profile", ConfigId: "removed"}
+	pruneRemovedAutoTag  = coordinate.Coordinate{Project: "proj", Type: "auto-tag", ConfigId: "removed"}
+)
+
+func pruneTestProjects() []project.Project {
+	return []project.Project{
+		{
+			Id: "proj",
+			Configs: project.ConfigsPerTypePerEnvironments{"env": project.ConfigsPerType{
+				"builtin:alerting.profile": []config.Config{
+					{
+						Coordinate:  pruneExistingSetting,
+						Type:        config.SettingsType{SchemaId: "builtin:alerting.profile"},
+						Template:    template.NewInMemoryTemplate("existing", `{}`),
+						Environment: "env",
+						Parameters:  config.Parameters{config.ScopeParameter: value.New("environment")},
+					},
+				},
+			}},
+		},
+	}
+}
+
+// listSettingsWithExternalIDs returns a ListSettings implementation returning an object for each given coordinate,
+// using the coordinate's ConfigId as object ID
+func listSettingsWithExternalIDs(t *testing.T, coordinates ...coordinate.Coordinate) func(context.Context, string, dtclient.ListSettingsOptions) ([]dtclient.DownloadSettingsObject, error) {
+	all := []dtclient.DownloadSettingsObject{{ExternalId: "not-from-monaco", ObjectId: "unrelated"}}
+	for _, c := range coordinates {
+		externalID, err := idutils.GenerateExternalID(c)
+		require.NoError(t, err)
+		all = append(all, dtclient.DownloadSettingsObject{ExternalId: externalID, ObjectId: c.Project + "-" + c.ConfigId})
+	}
+
+	return func(_ context.Context, _ string, opts dtclient.ListSettingsOptions) ([]dtclient.DownloadSettingsObject, error) {
+		var res []dtclient.DownloadSettingsObject
+		for _, o := range all {
+			if opts.Filter == nil || opts.Filter(o) {
+				res = append(res, o)
+			}
+		}
+		return res, nil
+	}
+}
+
+func TestPrune_DryRunFindsCandidates(t *testing.T) {
+	removedWorkflow := coordinate.Coordinate{Project: "proj", Type: "workflow", ConfigId: "removed"}
+	downloadedWorkflow := coordinate.Coordinate{Project: "proj", Type: "workflow", ConfigId: "downloaded"}
+	removedBucket := coordinate.Coordinate{Project: "proj", Type: "bucket", ConfigId: "removed"}
+	otherProjectAutoTag := coordinate.Coordinate{Project: "other", Type: "auto-tag", ConfigId: "removed"}
+
+	s := state.New()
+	s.Set("env", state.Entry{Coordinate: pruneExistingSetting, ConfigType: "settings", RemoteId: "proj-existing"})
+	s.Set("env", state.Entry{Coordinate: pruneRemovedAutoTag, ConfigType: "classic", RemoteId: "auto-tag-id"})
+	s.Set("env", state.Entry{Coordinate: otherProjectAutoTag, ConfigType: "classic", RemoteId: "other-auto-tag-id"})
+	s.Set("env", state.Entry{Coordinate: removedWorkflow, ConfigType: "automation", RemoteId: idutils.GenerateUUIDFromCoordinate(removedWorkflow)})
+	s.Set("env", state.Entry{Coordinate: downloadedWorkflow, ConfigType: "automation", RemoteId: "origin-object-id"})
+	s.Set("env", state.Entry{Coordinate: removedBucket, ConfigType: "bucket", RemoteId: idutils.GenerateBucketName(removedBucket)})
+
+	c := dtclient.NewMockClient(gomock.NewController(t))
+	c.EXPECT().ListSettings(gomock.Any(), "builtin:alerting.profile", gomock.Any()).AnyTimes().DoAndReturn(
+		listSettingsWithExternalIDs(t, pruneExistingSetting, pruneRemovedSetting, pruneOtherProject))
+
+	clients := dynatrace.EnvironmentClients{
+		dynatrace.EnvironmentInfo{Name: "env"}: &client.ClientSet{DTClient: c},
+	}
+
+	candidates, err := deploy.Prune(context.TODO(), pruneTestProjects(), clients, deploy.PruneOptions{DryRun: true, State: s})
+	require.NoError(t, err)
+
+	assert.Equal(t, []deploy.PruneCandidate{
+		{Coordinate: pruneRemovedAutoTag, Environment: "env", ConfigType: config.ClassicApiTypeId, RemoteId: "auto-tag-id"},
+		{Coordinate: removedBucket, Environment: "env", ConfigType: config.BucketTypeId, RemoteId: idutils.GenerateBucketName(removedBucket)},
+		{Coordinate: pruneRemovedSetting, Environment: "env", ConfigType: config.SettingsTypeId, RemoteId: "proj-removed"},
+		{Coordinate: removedWorkflow, Environment: "env", ConfigType: config.AutomationTypeId, RemoteId: idutils.GenerateUUIDFromCoordinate(removedWorkflow)},
+	}, candidates)
+	assert.Len(t, s.Entries("env"), 6, "state must not be modified in dry-run")
+}
+
+func TestPrune_DeletesCandidatesAndUpdatesState(t *testing.T) {
+	s := state.New()
+	s.Set("env", state.Entry{Coordinate: pruneRemovedAutoTag, ConfigType: "classic", RemoteId: "auto-tag-id"})
+	s.Set("env", state.Entry{Coordinate: pruneExistingSetting, ConfigType: "settings", RemoteId: "proj-existing"})
+
+	c := dtclient.NewMockClient(gomock.NewController(t))
+	c.EXPECT().ListSettings(gomock.Any(), "builtin:alerting.profile", gomock.Any()).AnyTimes().DoAndReturn(
+		listSettingsWithExternalIDs(t, pruneExistingSetting, pruneRemovedSetting))
+	c.EXPECT().DeleteSettings("proj-removed").Times(1).Return(nil)
+	c.EXPECT().ListConfigs(gomock.Any(), gomock.Any()).Times(1).Return([]dtclient.Value{{Id: "auto-tag-id", Name: "renamed"}}, nil)
+	c.EXPECT().DeleteConfigById(gomock.Any(), "auto-tag-id").Times(1).DoAndReturn(func(a api.API, _ string) error {
+		assert.Equal(t, "auto-tag", a.ID)
+		return nil
+	})
+
+	clients := dynatrace.EnvironmentClients{
+		dynatrace.EnvironmentInfo{Name: "env"}: &client.ClientSet{DTClient: c},
+	}
+
+	candidates, err := deploy.Prune(context.TODO(), pruneTestProjects(), clients, deploy.PruneOptions{State: s})
+	require.NoError(t, err)
+	assert.Len(t, candidates, 2)
+
+	assert.Equal(t, []state.Entry{{Coordinate: pruneExistingSetting, ConfigType: "settings", RemoteId: "proj-existing"}}, s.Entries("env"))
+}
+
+func TestPrune_WithoutStateOnlyPrunesSettings(t *testing.T) {
+	c := dtclient.NewMockClient(gomock.NewController(t))
+	c.EXPECT().ListSettings(gomock.Any(), "builtin:alerting.profile", gomock.Any()).AnyTimes().DoAndReturn(
+		listSettingsWithExternalIDs(t, pruneExistingSetting, pruneRemovedSetting))
+	c.EXPECT().DeleteSettings("proj-removed").Times(1).Return(nil)
+
+	clients := dynatrace.EnvironmentClients{
+		dynatrace.EnvironmentInfo{Name: "env"}: &client.ClientSet{DTClient: c},
+	}
+
+	candidates, err := deploy.Prune(context.TODO(), pruneTestProjects(), clients, deploy.PruneOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, []deploy.PruneCandidate{
+		{Coordinate: pruneRemovedSetting, Environment: "env", ConfigType: config.SettingsTypeId, RemoteId: "proj-removed"},
+	}, candidates)
+}
@@ -0,0 +1,326 @@
+/*
+ * @license
+ * Copyright 2024 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package report records the result of deploying each configuration, and writes them as machine-readable report files.
+package report
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/secret"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/coordinate"
+	"github.com/spf13/afero"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Status is the result of deploying a single configuration
+type Status string
+
+const (
+	StatusDeployed Status = "deployed"
+	StatusFailed   Status = "failed"
+	StatusSkipped  Status = "skipped"
+	// StatusUnchanged states that the configuration was not written, as the object it is deployed to is equal to it
+	StatusUnchanged Status = "unchanged"
+	// StatusRestored states that the configuration was not written, as it was already deployed by the deployment that
+	// was resumed from a checkpoint
+	StatusRestored Status = "restored"
+	// StatusCancelled states that the configuration was not deployed, as the deployment was cancelled
+	StatusCancelled Status = "cancelled"
+)
+
+// Format of a report file
+type Format string
+
+const (
+	FormatJSON  Format = "json"
+	FormatJUnit Format = "junit"
+)
+
+// Formats lists all supported report formats
+var Formats = []Format{FormatJSON, FormatJUnit}
+
+// Record is the result of deploying a single configuration to an environment
+type Record struct {
+	Coordinate  coordinate.Coordinate `json:"coordinate"`
+	Environment string                `json:"environment"`
+	Status      Status                `json:"status"`
+	// RemoteId is the ID of the object the configuration was deployed to. It is empty if the configuration was neither
+	// deployed nor unchanged.
+	RemoteId string `json:"remoteId,omitempty"`
+	// Duration the deployment of the configuration took
+	Duration time.Duration `json:"-"`
+	// Error that made the deployment fail. It is nil unless Status is StatusFailed.
+	Error *Error `json:"error,omitempty"`
+	// SkipReason describes why the configuration was skipped. It is empty unless Status is StatusSkipped.
+	SkipReason string `json:"skipReason,omitempty"`
+	// RootCause is the configuration which was skipped or failed to deploy, and thereby caused this configuration to
+	// be skipped. It is nil if the configuration itself is marked as skipped.
+	RootCause *coordinate.Coordinate `json:"rootCause,omitempty"`
+	// Retries is the number of times the deployment of the configuration was retried after it failed, or after a
+	// configuration it depends on failed
+	Retries int `json:"retries,omitempty"`
+}
+
+// Error holds the details of a failed deployment
+type Error struct {
+	// Type of the error
+	Type string `json:"type"`
+	// Message of the error
+	Message string `json:"message"`
+	// Details is the JSON representation of the error, serialized using its own JSON tags. If the error is wrapped, the
+	// first error of the chain with exported fields is used. It is empty if no error of the chain has exported fields.
+	Details json.RawMessage `json:"details,omitempty"`
+}
+
+// NewError returns the Error describing err
+func NewError(err error) *Error {
+	return &Error{
+		Type:    fmt.Sprintf("%T", err),
+		Message: err.Error(),
+		Details: errorDetails(err),
+	}
+}
+
+// errorDetails returns the JSON representation of the first error in the chain of err which is not serialized to an
+// empty JSON object, as errors without exported fields are, or nil if there is no such error
+func errorDetails(err error) json.RawMessage {
+	for ; err != nil; err = errors.Unwrap(err) {
+		b, mErr := json.Marshal(err)
+		if mErr != nil {
+			continue
+		}
+		if s := string(b); s != "{}" && s != "null" {
+			return b
+		}
+	}
+	return nil
+}
+
+// Recorder collects the Record of each deployed configuration. It is safe for concurrent use.
+type Recorder struct {
+	mu      sync.Mutex
+	records []Record
+	// index of the record of each configuration in records
+	index map[recordKey]int
+}
+
+type recordKey struct {
+	environment string
+	coordinate  coordinate.Coordinate
+}
+
+// NewRecorder returns a new empty Recorder
+func NewRecorder() *Recorder {
+	return &Recorder{index: make(map[recordKey]int)}
+}
+
+// Add records the given Record. If a configuration was already recorded for the same environment, e.g. as its
+// deployment is retried, the earlier Record is replaced.
+func (r *Recorder) Add(rec Record) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	key := recordKey{environment: rec.Environment, coordinate: rec.Coordinate}
+	if i, found := r.index[key]; found {
+		r.records[i] = rec
+		return
+	}
+	r.index[key] = len(r.records)
+	r.records = append(r.records, rec)
+}
+
+// Records returns all records, sorted by environment and coordinate
+func (r *Recorder) Records() []Record {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	records := slices.Clone(r.records)
+	slices.SortFunc(records, func(a, b Record) int {
+		if c := strings.Compare(a.Environment, b.Environment); c != 0 {
+			return c
+		}
+		return strings.Compare(a.Coordinate.String(), b.Coordinate.String())
+	})
+	return records
+}
+
+// Write stores the report at the given path in the given format. Missing parent directories are created.
+func (r *Recorder) Write(fs afero.Fs, path string, format Format) error {
+	var b []byte
+	var err error
+	switch format {
+	case FormatJSON:
+		b, err = r.marshalJSON()
+	case FormatJUnit:
+		b, err = r.marshalJUnit()
+	default:
+		return fmt.Errorf("unknown report format %q", format)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to marshal report: %w", err)
+	}
+
+	if err := fs.MkdirAll(filepath.Dir(path), 0777); err != nil {
+		return fmt.Errorf("failed to create directory for report file %q: %w", path, err)
+	}
+
+	// errors might contain resolved secrets, so these are masked
+	if err := afero.WriteFile(fs, path, []byte(secret.Mask(string(b))), 0664); err != nil {
+		return fmt.Errorf("failed to write report file %q: %w", path, err)
+	}
+	return nil
+}
+
+type jsonRecord struct {
+	Record
+	DurationSeconds float64 `json:"durationSeconds"`
+}
+
+type jsonReport struct {
+	Deployed  int          `json:"deployed"`
+	Failed    int          `json:"failed"`
+	Skipped   int          `json:"skipped"`
+	Unchanged int          `json:"unchanged"`
+	Restored  int          `json:"restored"`
+	Cancelled int          `json:"cancelled"`
+	Results   []jsonRecord `json:"results"`
+}
+
+func (r *Recorder) marshalJSON() ([]byte, error) {
+	records := r.Records()
+
+	rep := jsonReport{Results: make([]jsonRecord, 0, len(records))}
+	for _, rec := range records {
+		switch rec.Status {
+		case StatusDeployed:
+			rep.Deployed++
+		case StatusFailed:
+			rep.Failed++
+		case StatusSkipped:
+			rep.Skipped++
+		case StatusUnchanged:
+			rep.Unchanged++
+		case StatusRestored:
+			rep.Restored++
+		case StatusCancelled:
+			rep.Cancelled++
+		}
+		rep.Results = append(rep.Results, jsonRecord{Record: rec, DurationSeconds: rec.Duration.Seconds()})
+	}
+
+	return json.MarshalIndent(rep, "", "  ")
+}
+
+type junitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Name     string           `xml:"name,attr"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Skipped  int              `xml:"skipped,attr"`
+	Time     float64          `xml:"time,attr"`
+	Suites   []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name     string          `xml:"name,attr"`
+	Tests    int             `xml:"tests,attr"`
+	Failures int             `xml:"failures,attr"`
+	Skipped  int             `xml:"skipped,attr"`
+	Time     float64         `xml:"time,attr"`
+	Cases    []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Time      float64       `xml:"time,attr"`
+	Failure   *junitFailure `xml:"failure,omitempty"`
+	Skipped   *junitSkipped `xml:"skipped,omitempty"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Details string `xml:",chardata"`
+}
+
+type junitSkipped struct {
+	Message string `xml:"message,attr"`
+}
+
+// marshalJUnit returns the report in JUnit XML format, with a test suite per environment and a test case per configuration
+func (r *Recorder) marshalJUnit() ([]byte, error) {
+	suites := junitTestSuites{Name: "monaco deploy"}
+
+	for _, rec := range r.Records() {
+		if len(suites.Suites) == 0 || suites.Suites[len(suites.Suites)-1].Name != rec.Environment {
+			suites.Suites = append(suites.Suites, junitTestSuite{Name: rec.Environment})
+		}
+		suite := &suites.Suites[len(suites.Suites)-1]
+
+		tc := junitTestCase{
+			Name:      rec.Coordinate.String(),
+			ClassName: rec.Environment + "." + rec.Coordinate.Project,
+			Time:      rec.Duration.Seconds(),
+		}
+
+		switch rec.Status {
+		case StatusFailed:
+			f := &junitFailure{Message: "deployment failed"}
+			if rec.Error != nil {
+				details, err := json.MarshalIndent(rec.Error, "", "  ")
+				if err != nil {
+					return nil, err
+				}
+				f.Message = rec.Error.Message
+				f.Type = rec.Error.Type
+				f.Details = string(details)
+			}
+			tc.Failure = f
+			suite.Failures++
+		case StatusSkipped:
+			tc.Skipped = &junitSkipped{Message: rec.SkipReason}
+			suite.Skipped++
+		case StatusCancelled:
+			tc.Skipped = &junitSkipped{Message: "deployment was cancelled"}
+			suite.Skipped++
+		}
+
+		suite.Tests++
+		suite.Time += tc.Time
+		suite.Cases = append(suite.Cases, tc)
+	}
+
+	for _, s := range suites.Suites {
+		suites.Tests += s.Tests
+		suites.Failures += s.Failures
+		suites.Skipped += s.Skipped
+		suites.Time += s.Time
+	}
+
+	b, err := xml.MarshalIndent(suites, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), b...), nil
+}
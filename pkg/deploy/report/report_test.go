@@ -0,0 +1,193 @@
+//go:build unit
+
+/*
+ * @license
+ * Copyright 2024 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package report_test
+
+import (
+	"errors"
+	"fmt"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/coordinate"
+	deployErrors "github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/deploy/errors"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/deploy/report"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+var (
+	deployed = coordinate.Coordinate{Project: "p", Type: "auto-tag", ConfigId: "deployed"}
+	failed   = coordinate.Coordinate{Project: "p", Type: "auto-tag", ConfigId: "failed"}
+	skipped  = coordinate.Coordinate{Project: "p", Type: "auto-tag", ConfigId: "skipped"}
+)
+
+func testRecorder() *report.Recorder {
+	r := report.NewRecorder()
+	r.Add(report.Record{
+		Coordinate:  skipped,
+		Environment: "prod",
+		Status:      report.StatusSkipped,
+		SkipReason:  "depends on p:auto-tag:failed which was not deployed, as p:auto-tag:failed failed to deploy",
+		RootCause:   &failed,
+	})
+	r.Add(report.Record{
+		Coordinate:  failed,
+		Environment: "prod",
+		Status:      report.StatusFailed,
+		Duration:    500 * time.Millisecond,
+		Error:       report.NewError(deployErrors.ConfigDeployErr{Location: failed, Reason: "API rejected payload"}),
+	})
+	r.Add(report.Record{
+		Coordinate:  deployed,
+		Environment: "dev",
+		Status:      report.StatusDeployed,
+		RemoteId:    "remote-id",
+		Duration:    2 * time.Second,
+	})
+	return r
+}
+
+func TestRecorder_RecordsAreSorted(t *testing.T) {
+	records := testRecorder().Records()
+
+	require.Len(t, records, 3)
+	assert.Equal(t, deployed, records[0].Coordinate)
+	assert.Equal(t, failed, records[1].Coordinate)
+	assert.Equal(t, skipped, records[2].Coordinate)
+}
+
+func TestRecorder_WriteJSON(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	require.NoError(t, testRecorder().Write(fs, "reports/report.json", report.FormatJSON))
+
+	b, err := afero.ReadFile(fs, "reports/report.json")
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+  "deployed": 1,
+  "failed": 1,
+  "skipped": 1,
+  "unchanged": 0,
+  "restored": 0,
+  "cancelled": 0,
+  "results": [
+    {
+      "coordinate": {"Project": "p", "Type": "auto-tag", "ConfigId": "deployed"},
+      "environment": "dev",
+      "status": "deployed",
+      "remoteId": "remote-id",
+      "durationSeconds": 2
+    },
+    {
+      "coordinate": {"Project": "p", "Type": "auto-tag", "ConfigId": "failed"},
+      "environment": "prod",
+      "status": "failed",
+      "error": {
+        "type": "errors.ConfigDeployErr",
+        "message": "API rejected payload",
+        "details": {
+          "location": {"Project": "p", "Type": "auto-tag", "ConfigId": "failed"},
+          "environmentDetails": {"group": "", "environment": ""},
+          "reason": "API rejected payload",
+          "error": null
+        }
+      },
+      "durationSeconds": 0.5
+    },
+    {
+      "coordinate": {"Project": "p", "Type": "auto-tag", "ConfigId": "skipped"},
+      "environment": "prod",
+      "status": "skipped",
+      "skipReason": "depends on p:auto-tag:failed which was not deployed, as p:auto-tag:failed failed to deploy",
+      "rootCause": {"Project": "p", "Type": "auto-tag", "ConfigId": "failed"},
+      "durationSeconds": 0
+    }
+  ]
+}`, string(b))
+}
+
+func TestRecorder_WriteJUnit(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	require.NoError(t, testRecorder().Write(fs, "report.xml", report.FormatJUnit))
+
+	b, err := afero.ReadFile(fs, "report.xml")
+	require.NoError(t, err)
+
+	xml := string(b)
+	assert.Contains(t, xml, `<testsuites name="monaco deploy" tests="3" failures="1" skipped="1" time="2.5">`)
+	assert.Contains(t, xml, `<testsuite name="dev" tests="1" failures="0" skipped="0" time="2">`)
+	assert.Contains(t, xml, `<testsuite name="prod" tests="2" failures="1" skipped="1" time="0.5">`)
+	assert.Contains(t, xml, `<testcase name="p:auto-tag:deployed" classname="dev.p" time="2"></testcase>`)
+	assert.Contains(t, xml, `<failure message="API rejected payload" type="errors.ConfigDeployErr">`)
+	assert.Contains(t, xml, `<skipped message="depends on p:auto-tag:failed which was not deployed, as p:auto-tag:failed failed to deploy"></skipped>`)
+}
+
+func TestRecorder_WriteCancelled(t *testing.T) {
+	r := report.NewRecorder()
+	r.Add(report.Record{Coordinate: deployed, Environment: "dev", Status: report.StatusCancelled})
+
+	fs := afero.NewMemMapFs()
+	require.NoError(t, r.Write(fs, "report.json", report.FormatJSON))
+	require.NoError(t, r.Write(fs, "report.xml", report.FormatJUnit))
+
+	b, err := afero.ReadFile(fs, "report.json")
+	require.NoError(t, err)
+	assert.Contains(t, string(b), `"cancelled": 1`)
+	assert.Contains(t, string(b), `"status": "cancelled"`)
+
+	b, err = afero.ReadFile(fs, "report.xml")
+	require.NoError(t, err)
+	assert.Contains(t, string(b), `<skipped message="deployment was cancelled"></skipped>`)
+}
+
+func TestRecorder_WriteFailsForUnknownFormat(t *testing.T) {
+	err := testRecorder().Write(afero.NewMemMapFs(), "report", "yaml")
+	assert.ErrorContains(t, err, "unknown report format")
+}
+
+func TestRecorder_AddReplacesEarlierRecord(t *testing.T) {
+	r := report.NewRecorder()
+	r.Add(report.Record{Coordinate: failed, Environment: "prod", Status: report.StatusFailed})
+	r.Add(report.Record{Coordinate: failed, Environment: "dev", Status: report.StatusFailed})
+	r.Add(report.Record{Coordinate: failed, Environment: "prod", Status: report.StatusDeployed, Retries: 1})
+
+	assert.Equal(t, []report.Record{
+		{Coordinate: failed, Environment: "dev", Status: report.StatusFailed},
+		{Coordinate: failed, Environment: "prod", Status: report.StatusDeployed, Retries: 1},
+	}, r.Records())
+}
+
+func TestNewError(t *testing.T) {
+	t.Run("errors without exported fields have no details", func(t *testing.T) {
+		err := report.NewError(fmt.Errorf("failed to deploy: %w", errors.New("connection refused")))
+
+		assert.Equal(t, "failed to deploy: connection refused", err.Message)
+		assert.Nil(t, err.Details)
+	})
+
+	t.Run("details of wrapped errors are used", func(t *testing.T) {
+		err := report.NewError(fmt.Errorf("failed to deploy: %w", deployErrors.ConfigDeployErr{Location: failed, Reason: "API rejected payload"}))
+
+		assert.JSONEq(t, `{
+  "location": {"Project": "p", "Type": "auto-tag", "ConfigId": "failed"},
+  "environmentDetails": {"group": "", "environment": ""},
+  "reason": "API rejected payload",
+  "error": null
+}`, string(err.Details))
+	})
+}
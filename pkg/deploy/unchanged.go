@@ -0,0 +1,93 @@
+// @license
+// Copyright 2024 Dynatrace LLC
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deploy
+
+import (
+	"context"
+	"fmt"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/entities"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/parameter"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/deploy/internal/automation"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/deploy/internal/bucket"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/deploy/internal/classic"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/deploy/internal/extract"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/deploy/internal/remote"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/deploy/internal/setting"
+)
+
+// findUnchanged fetches the remote object the given config is deployed to and compares it with the rendered config,
+// like Plan does. If the object is equal to the rendered config, its resolved entity is returned and unchanged is true.
+// Only the properties managed by the config are compared, so the rendered config must already be merged into the object
+// if the config defines a config.Merge. Properties only present on the object, e.g. defaults filled in by the Dynatrace
+// server, are ignored.
+// Lookups use the same client calls as deploying the config, so data already cached by the clients is reused.
+//
+// If the config was deployed to a known object by a previous deployment, this object is fetched by its ID. If it does
+// not exist anymore, the config is considered changed.
+func findUnchanged(ctx context.Context, c *config.Config, clients ClientSet, properties parameter.Properties, renderedConfig string, knownID string) (resolved entities.ResolvedEntity, unchanged bool, err error) {
+	obj, found, err := getKnownRemote(ctx, c, clients, properties, knownID)
+	if err != nil || !found {
+		return entities.ResolvedEntity{}, false, err
+	}
+
+	if knownID != "" && knownID != obj.ID {
+		return entities.ResolvedEntity{}, false, nil
+	}
+
+	if _, isSettings := c.Type.(config.SettingsType); isSettings {
+		scope, err := extract.Scope(properties)
+		if err != nil {
+			return entities.ResolvedEntity{}, false, err
+		}
+		if scope != obj.Scope {
+			return entities.ResolvedEntity{}, false, nil
+		}
+	}
+
+	diff, _, err := diffRemote(c, []byte(renderedConfig), obj.Content)
+	if err != nil {
+		return entities.ResolvedEntity{}, false, fmt.Errorf("failed to compare config with object %q: %w", obj.ID, err)
+	}
+	if len(diff) != 0 {
+		return entities.ResolvedEntity{}, false, nil
+	}
+
+	resolved, err = resolveEntity(ctx, c, properties, obj)
+	if err != nil {
+		return entities.ResolvedEntity{}, false, err
+	}
+	return resolved, true, nil
+}
+
+// resolveEntity returns the entity of the given config, which is deployed to the given existing remote object
+func resolveEntity(ctx context.Context, c *config.Config, properties parameter.Properties, obj remote.Object) (entities.ResolvedEntity, error) {
+	switch c.Type.(type) {
+	case config.SettingsType:
+		return setting.ResolveEntity(ctx, properties, c, obj)
+
+	case config.ClassicApiType:
+		return classic.ResolveEntity(properties, c, obj)
+
+	case config.AutomationType:
+		return automation.ResolveEntity(ctx, properties, c, obj), nil
+
+	case config.BucketType:
+		return bucket.ResolveEntity(properties, c, obj), nil
+
+	default:
+		return entities.ResolvedEntity{}, fmt.Errorf("unknown config-type (ID: %q)", c.Type.ID())
+	}
+}
@@ -52,6 +52,17 @@ func sanitizeProperties(properties map[string]interface{}, apiId string) map[str
 	return replaceTemplateProperties(properties, apiId)
 }
 
+// RemoveServerManagedProperties removes all properties of an object returned by the given API that are managed by
+// the Dynatrace server, like IDs, metadata or counters, and can therefore not be defined by a configuration.
+// Properties are removed in place, the changed map is returned for convenience.
+func RemoveServerManagedProperties(properties map[string]interface{}, a api.API) map[string]interface{} {
+	if a.TweakResponseFunc != nil {
+		a.TweakResponseFunc(properties)
+	}
+	properties = removeIdentifyingProperties(properties, a.ID)
+	return removePropertiesNotAllowedOnUpload(properties, a.ID)
+}
+
 func removeIdentifyingProperties(dat map[string]interface{}, apiId string) map[string]interface{} {
 	dat = removeByPath(dat, []string{"metadata"})
 	dat = removeByPath(dat, []string{"id"})
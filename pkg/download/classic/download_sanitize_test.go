@@ -17,6 +17,7 @@
 package classic
 
 import (
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/api"
 	"github.com/stretchr/testify/require"
 	"testing"
 )
@@ -213,3 +214,13 @@ func TestRemoveIdentifiers(t *testing.T) {
 		})
 	}
 }
+
+func TestRemoveServerManagedProperties(t *testing.T) {
+	networkZone := api.NewAPIs()[api.NetworkZone]
+
+	result := RemoveServerManagedProperties(unmarshal(t, `{"id": "zone", "name": "zone", "description": "d", "numOfOneAgentsUsing": 3}`), networkZone)
+	require.Equal(t, unmarshal(t, `{"name": "zone", "description": "d"}`), result, "identifying properties and properties removed by the API's TweakResponseFunc must be removed, but the name must be kept")
+
+	result = RemoveServerManagedProperties(unmarshal(t, `{"name": "window", "scope": {"entities": [], "matches": []}}`), api.NewAPIs()[api.MaintenanceWindow])
+	require.Equal(t, unmarshal(t, `{"name": "window"}`), result, "properties not allowed on upload must be removed")
+}
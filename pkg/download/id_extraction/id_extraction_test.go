@@ -19,6 +19,7 @@
 package id_extraction
 
 import (
+	"context"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/featureflags"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/parameter"
@@ -405,7 +406,7 @@ func TestExtractedTemplatesRenderCorrectly(t *testing.T) {
 
 	for _, cfgs := range got {
 		for _, c := range cfgs {
-			props, errs := c.ResolveParameterValues(nil)
+			props, errs := c.ResolveParameterValues(context.TODO(), nil)
 			assert.Empty(t, errs)
 			_, err := c.Render(props)
 			assert.NoError(t, err)
@@ -0,0 +1,89 @@
+/*
+ * @license
+ * Copyright 2024 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package graph
+
+import (
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/coordinate"
+	"gonum.org/v1/gonum/graph"
+	"gonum.org/v1/gonum/graph/simple"
+)
+
+// Select returns the sub-graphs of the given graphs, which only contain the configurations for which selected returns
+// true, and all configurations they transitively depend on, so that their references can be resolved.
+// If withDependents is true, all configurations transitively depending on selected configurations are included as
+// well, again together with all configurations they depend on.
+func (graphs ConfigGraphPerEnvironment) Select(selected func(coordinate.Coordinate) bool, withDependents bool) ConfigGraphPerEnvironment {
+	result := make(ConfigGraphPerEnvironment, len(graphs))
+	for environment, g := range graphs {
+		result[environment] = selectSubGraph(g, selected, withDependents)
+	}
+	return result
+}
+
+func selectSubGraph(g *simple.DirectedGraph, selected func(coordinate.Coordinate) bool, withDependents bool) *simple.DirectedGraph {
+	var nodes []graph.Node
+	it := g.Nodes()
+	for it.Next() {
+		if selected(it.Node().(ConfigNode).Config.Coordinate) {
+			nodes = append(nodes, it.Node())
+		}
+	}
+
+	// edges point from a configuration to the configurations depending on it
+	if withDependents {
+		nodes = reachable(nodes, g.From)
+	}
+	nodes = reachable(nodes, g.To)
+
+	sub := simple.NewDirectedGraph()
+	for _, n := range nodes {
+		sub.AddNode(n)
+	}
+
+	edges := g.Edges()
+	for edges.Next() {
+		e := edges.Edge()
+		if sub.Node(e.From().ID()) != nil && sub.Node(e.To().ID()) != nil {
+			sub.SetEdge(sub.NewEdge(e.From(), e.To()))
+		}
+	}
+	return sub
+}
+
+// reachable returns the given nodes, and all nodes transitively reachable from them via next
+func reachable(start []graph.Node, next func(id int64) graph.Nodes) []graph.Node {
+	seen := make(map[int64]struct{})
+	var result []graph.Node
+
+	queue := start
+	for len(queue) > 0 {
+		n := queue[0]
+		queue = queue[1:]
+
+		if _, found := seen[n.ID()]; found {
+			continue
+		}
+		seen[n.ID()] = struct{}{}
+		result = append(result, n)
+
+		it := next(n.ID())
+		for it.Next() {
+			queue = append(queue, it.Node())
+		}
+	}
+	return result
+}
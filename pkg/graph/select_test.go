@@ -0,0 +1,96 @@
+//go:build unit
+
+/*
+ * @license
+ * Copyright 2024 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package graph_test
+
+import (
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/coordinate"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/parameter"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/graph"
+	project "github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/project/v2"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestConfigGraphPerEnvironment_Select(t *testing.T) {
+	coord := func(id string) coordinate.Coordinate {
+		return coordinate.Coordinate{Project: "p", Type: "dashboard", ConfigId: id}
+	}
+	cfg := func(id string, refs ...string) config.Config {
+		var references []parameter.ParameterReference
+		for _, r := range refs {
+			references = append(references, parameter.ParameterReference{Config: coord(r), Property: "id"})
+		}
+		return config.Config{
+			Coordinate:  coord(id),
+			Environment: "dev",
+			Parameters: map[string]parameter.Parameter{
+				"ref": &parameter.DummyParameter{References: references},
+			},
+		}
+	}
+
+	// a <- b <- c -> other, and an unrelated config
+	projects := []project.Project{
+		{
+			Id: "p",
+			Configs: project.ConfigsPerTypePerEnvironments{
+				"dev": {
+					"dashboard": []config.Config{
+						cfg("a"),
+						cfg("b", "a"),
+						cfg("c", "b", "other"),
+						cfg("other"),
+						cfg("unrelated"),
+					},
+				},
+			},
+		},
+	}
+	graphs := graph.New(projects, []string{"dev"})
+
+	selectedIDs := func(g graph.ConfigGraphPerEnvironment) []string {
+		var ids []string
+		nodes := g["dev"].Nodes()
+		for nodes.Next() {
+			ids = append(ids, nodes.Node().(graph.ConfigNode).Config.Coordinate.ConfigId)
+		}
+		return ids
+	}
+	isB := func(c coordinate.Coordinate) bool { return c == coord("b") }
+
+	t.Run("dependencies are included", func(t *testing.T) {
+		selected := graphs.Select(isB, false)
+		assert.ElementsMatch(t, []string{"a", "b"}, selectedIDs(selected))
+		assert.True(t, selected["dev"].HasEdgeFromTo(0, 1), "edges between selected configurations must be kept")
+	})
+
+	t.Run("dependents and their dependencies are included", func(t *testing.T) {
+		assert.ElementsMatch(t, []string{"a", "b", "c", "other"}, selectedIDs(graphs.Select(isB, true)))
+	})
+
+	t.Run("nothing is selected if no configuration matches", func(t *testing.T) {
+		assert.Empty(t, selectedIDs(graphs.Select(func(coordinate.Coordinate) bool { return false }, true)))
+	})
+
+	t.Run("original graph is not modified", func(t *testing.T) {
+		graphs.Select(isB, false)
+		assert.Equal(t, 5, graphs["dev"].Nodes().Len())
+	})
+}
@@ -44,6 +44,8 @@ const (
 type TypedValue struct {
 	Type  Type   `yaml:"type,omitempty" mapstructure:"type" json:"type" jsonschema:"enum=environment,enum=value,description=The type of this value - either an 'environment' variable to read, or simpy a 'value' directly in the YAML."`
 	Value string `yaml:"value" mapstructure:"value" json:"value" jsonschema:"required,description=The value is depending on 'type' either the name of an environment variable to load or just a string value."`
+	// Constraints the loaded value is checked against, see parameter.ParseConstraints
+	Constraints map[string]any `yaml:"constraints,omitempty" mapstructure:"constraints" json:"constraints,omitempty" jsonschema:"description=Optional constraints the loaded value must satisfy - 'type' (int\\, bool or string)\\, 'pattern'\\, 'enum'\\, 'min'\\, 'max' and 'required'."`
 }
 
 // UnmarshalYAML Custom unmarshaler for TypedValue able to parse simple shorthands (accountUUID: 1234) and full values.
@@ -118,6 +120,58 @@ type Manifest struct {
 	EnvironmentGroups []Group `yaml:"environmentGroups" json:"environmentGroups" jsonschema:"minItems=1,description=A list of environment groups that configs in the defined 'projects' will be deployed to. Required when deploying environment configurations."`
 	// Accounts is a list of accounts that account resources in Projects will be deployed to
 	Accounts []Account `yaml:"accounts,omitempty" json:"accounts" jsonschema:"minItems=1,description=A list of of accounts that account resources defined in 'projects' will be deployed to. Required when deploying account resources."`
+	// State optionally defines where deployment state is persisted
+	State *State `yaml:"state,omitempty" json:"state" jsonschema:"description=Optionally defines a file to persist deployment state in. If defined, the IDs of deployed objects are remembered and used to find them again on later deployments."`
+	// Partials optionally defines a folder with template partials shared by all projects
+	Partials string `yaml:"partials,omitempty" json:"partials" jsonschema:"description=Optionally defines a folder holding template partials that can be included by the templates of all projects, relative to the manifest's location. Partials in the '_partials' folder of a project take precedence."`
+	// Rollouts optionally define the order in which the environments of groups are deployed
+	Rollouts []Rollout `yaml:"rollouts,omitempty" json:"rollouts" jsonschema:"description=Optionally orders the environments of groups into stages. The stages of a group are deployed one after another, and the next stage is only deployed if the previous one succeeded and its gate passed."`
+	// Variables optionally define values shared by the configs of all projects
+	Variables []Variable `yaml:"variables,omitempty" json:"variables" jsonschema:"description=Optionally defines variables shared by the configs of all projects. Configs reference them with parameters of type 'variable'."`
+}
+
+// Variable defines a value shared by all projects, which can be overridden per environment group and environment
+type Variable struct {
+	Name                 string                        `yaml:"name" json:"name" jsonschema:"required,description=The name of the variable - parameters of type 'variable' reference it by this name."`
+	Value                *TypedValue                   `yaml:"value,omitempty" json:"value" jsonschema:"oneof_type=string;object,description=The value of the variable. If it is not defined the variable is only defined for the groups and environments it is overridden for."`
+	GroupOverrides       []VariableGroupOverride       `yaml:"groupOverrides,omitempty" json:"groupOverrides" jsonschema:"description=Overrides the value of the variable for all environments of a group."`
+	EnvironmentOverrides []VariableEnvironmentOverride `yaml:"environmentOverrides,omitempty" json:"environmentOverrides" jsonschema:"description=Overrides the value of the variable for single environments. Environment overrides take precedence over group overrides."`
+}
+
+// VariableGroupOverride overrides the value of a Variable for all environments of a group
+type VariableGroupOverride struct {
+	Group string     `yaml:"group" json:"group" jsonschema:"required,description=The name of the environment group the value is overridden for."`
+	Value TypedValue `yaml:"value" json:"value" jsonschema:"required,oneof_type=string;object,description=The value of the variable for the environments of the group."`
+}
+
+// VariableEnvironmentOverride overrides the value of a Variable for a single environment
+type VariableEnvironmentOverride struct {
+	Environment string     `yaml:"environment" json:"environment" jsonschema:"required,description=The name of the environment the value is overridden for."`
+	Value       TypedValue `yaml:"value" json:"value" jsonschema:"required,oneof_type=string;object,description=The value of the variable for the environment."`
+}
+
+// Rollout orders the environments of a group into stages
+type Rollout struct {
+	Group  string         `yaml:"group" json:"group" jsonschema:"required,description=The name of the environment group this rollout applies to."`
+	Stages []RolloutStage `yaml:"stages" json:"stages" jsonschema:"required,minItems=1,description=The stages in the order they are deployed. Each environment of the group must be part of exactly one stage."`
+}
+
+// RolloutStage defines environments that are deployed at the same time
+type RolloutStage struct {
+	Name         string   `yaml:"name" json:"name" jsonschema:"required,description=The name of the stage - this can be freely defined and will be used in logs, etc."`
+	Environments []string `yaml:"environments" json:"environments" jsonschema:"required,minItems=1,description=The names of the environments deployed in this stage."`
+	Gate         *Gate    `yaml:"gate,omitempty" json:"gate" jsonschema:"description=A check that must pass after this stage was deployed, before the next stage is deployed. It can not be defined for the last stage."`
+}
+
+// Gate defines a check that must pass before a rollout proceeds to the next stage
+type Gate struct {
+	Wait    string `yaml:"wait,omitempty" json:"wait" jsonschema:"description=The time to wait before the next stage is deployed, e.g. '10m' or '1h30m'."`
+	Command string `yaml:"command,omitempty" json:"command" jsonschema:"description=A command that is run after the wait time elapsed, in the directory of the manifest. The next stage is only deployed if the command exits with code 0."`
+}
+
+// State defines where the deployment state is persisted
+type State struct {
+	Path string `yaml:"path" json:"path" jsonschema:"required,description=The file path of the state file, relative to the manifest's location."`
 }
 
 type Account struct {
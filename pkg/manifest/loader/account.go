@@ -94,6 +94,9 @@ func loadAccountUUID(u persistence.TypedValue) (string, error) {
 	}
 
 	if u.Type == "" || u.Type == persistence.TypeValue { // shorthand or explicit type: value
+		if err := checkConstraints(u, u.Value); err != nil {
+			return "", err
+		}
 		return u.Value, nil
 	}
 
@@ -105,6 +108,9 @@ func loadAccountUUID(u persistence.TypedValue) (string, error) {
 		if val == "" {
 			return "", fmt.Errorf("environment variable %q is defined but has no value", u.Value)
 		}
+		if err := checkConstraints(u, val); err != nil {
+			return "", err
+		}
 		return val, nil
 	}
 
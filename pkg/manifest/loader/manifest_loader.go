@@ -24,6 +24,7 @@ import (
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/log/field"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/secret"
 	version2 "github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/version"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/parameter"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/manifest"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/manifest/internal/persistence"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/version"
@@ -188,12 +189,35 @@ func Load(context *Context) (manifest.Manifest, []error) {
 		}
 	}
 
+	// variables
+	if variableErrs := parseVariables(context, manifestYAML.EnvironmentGroups, environmentDefinitions, manifestYAML.Variables); variableErrs != nil {
+		errs = append(errs, variableErrs...)
+	}
+
 	// accounts
 	accounts, accErr := parseAccounts(context, manifestYAML.Accounts)
 	if accErr != nil {
 		errs = append(errs, newManifestLoaderError(context.ManifestPath, accErr.Error()))
 	}
 
+	// state
+	var state manifest.StateDefinition
+	if manifestYAML.State != nil {
+		if manifestYAML.State.Path == "" {
+			errs = append(errs, newManifestLoaderError(context.ManifestPath, "'state' is defined, but has no 'path'"))
+		}
+		state.Path = filepath.FromSlash(manifestYAML.State.Path)
+	}
+
+	// partials
+	partialsPath := filepath.FromSlash(manifestYAML.Partials)
+
+	// rollouts
+	rollouts, rolloutErrs := parseRollouts(context, manifestYAML.EnvironmentGroups, manifestYAML.Rollouts)
+	if rolloutErrs != nil {
+		errs = append(errs, rolloutErrs...)
+	}
+
 	// if any errors occurred up to now, return them
 	if errs != nil {
 		return manifest.Manifest{}, errs
@@ -203,6 +227,9 @@ func Load(context *Context) (manifest.Manifest, []error) {
 		Projects:     projectDefinitions,
 		Environments: environmentDefinitions,
 		Accounts:     accounts,
+		State:        state,
+		PartialsPath: partialsPath,
+		Rollouts:     rollouts,
 	}, nil
 }
 
@@ -463,6 +490,10 @@ func parseURLDefinition(context *Context, u persistence.TypedValue) (manifest.UR
 	if u.Type == "" || u.Type == persistence.TypeValue {
 		val := strings.TrimSuffix(u.Value, "/")
 
+		if err := checkConstraints(u, val); err != nil {
+			return manifest.URLDefinition{}, err
+		}
+
 		return manifest.URLDefinition{
 			Type:  manifest.ValueURLType,
 			Value: val,
@@ -491,6 +522,10 @@ func parseURLDefinition(context *Context, u persistence.TypedValue) (manifest.UR
 
 		val = strings.TrimSuffix(val, "/")
 
+		if err := checkConstraints(u, val); err != nil {
+			return manifest.URLDefinition{}, err
+		}
+
 		return manifest.URLDefinition{
 			Type:  manifest.EnvironmentURLType,
 			Value: val,
@@ -502,6 +537,23 @@ func parseURLDefinition(context *Context, u persistence.TypedValue) (manifest.UR
 	return manifest.URLDefinition{}, fmt.Errorf("%q is not a valid URL type", u.Type)
 }
 
+// checkConstraints checks the loaded value of the given TypedValue against its constraints
+func checkConstraints(u persistence.TypedValue, value string) error {
+	if u.Constraints == nil {
+		return nil
+	}
+
+	constraints, err := parameter.ParseConstraints(u.Constraints)
+	if err != nil {
+		return fmt.Errorf("invalid constraints: %w", err)
+	}
+
+	if err := constraints.Check(value); err != nil {
+		return fmt.Errorf("constraint violated: %w", err)
+	}
+	return nil
+}
+
 func parseProjects(context *projectLoaderContext, definitions []persistence.Project) (map[string]manifest.ProjectDefinition, []error) {
 	var errors []error
 	result := make(map[string]manifest.ProjectDefinition)
@@ -19,6 +19,7 @@
 package loader
 
 import (
+	"errors"
 	"fmt"
 	monacoVersion "github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/version"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/manifest"
@@ -31,6 +32,7 @@ import (
 	"path/filepath"
 	"reflect"
 	"testing"
+	"time"
 )
 
 func Test_extractUrlType(t *testing.T) {
@@ -1822,3 +1824,304 @@ projects: [{name: a, path: p}]
 		})
 	}
 }
+
+func TestStateIsLoaded(t *testing.T) {
+	tests := []struct {
+		name                 string
+		givenManifestContent string
+		want                 manifest.StateDefinition
+		wantErr              bool
+	}{
+		{
+			"state is optional",
+			`
+manifestVersion: 1.0
+projects: [{name: a, path: p}]
+`,
+			manifest.StateDefinition{},
+			false,
+		},
+		{
+			"state path is loaded",
+			`
+manifestVersion: 1.0
+projects: [{name: a, path: p}]
+state:
+  path: state/monaco-state.json
+`,
+			manifest.StateDefinition{Path: filepath.FromSlash("state/monaco-state.json")},
+			false,
+		},
+		{
+			"state without path produces error",
+			`
+manifestVersion: 1.0
+projects: [{name: a, path: p}]
+state: {}
+`,
+			manifest.StateDefinition{},
+			true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fs := afero.NewMemMapFs()
+			assert.NoError(t, afero.WriteFile(fs, "manifest.yaml", []byte(tt.givenManifestContent), 0400))
+
+			got, errs := Load(&Context{
+				Fs:           fs,
+				ManifestPath: "manifest.yaml",
+			})
+
+			if tt.wantErr {
+				assert.NotEmpty(t, errs)
+				return
+			}
+			assert.Empty(t, errs)
+			assert.Equal(t, tt.want, got.State)
+		})
+	}
+}
+
+func TestRolloutsAreLoaded(t *testing.T) {
+	const groups = `
+manifestVersion: 1.0
+projects: [{name: a, path: p}]
+environmentGroups:
+- name: prod
+  environments:
+  - {name: canary, url: {value: "https://canary.dynatrace.com"}, auth: {token: {name: TOKEN}}}
+  - {name: eu, url: {value: "https://eu.dynatrace.com"}, auth: {token: {name: TOKEN}}}
+  - {name: us, url: {value: "https://us.dynatrace.com"}, auth: {token: {name: TOKEN}}}
+- name: dev
+  environments:
+  - {name: dev, url: {value: "https://dev.dynatrace.com"}, auth: {token: {name: TOKEN}}}
+`
+	t.Setenv("TOKEN", "dt0c01.token")
+
+	tests := []struct {
+		name            string
+		givenRollouts   string
+		want            map[string]manifest.Rollout
+		wantErrContains string
+	}{
+		{
+			name: "rollouts are optional",
+			want: nil,
+		},
+		{
+			name: "rollout is loaded",
+			givenRollouts: `
+rollouts:
+- group: prod
+  stages:
+  - name: canary
+    environments: [canary]
+    gate: {wait: 10m, command: ./check.sh}
+  - name: rest
+    environments: [us, eu]
+`,
+			want: map[string]manifest.Rollout{
+				"prod": {
+					Group: "prod",
+					Stages: []manifest.RolloutStage{
+						{Name: "canary", Environments: []string{"canary"}, Gate: manifest.Gate{Wait: 10 * time.Minute, Command: "./check.sh"}},
+						{Name: "rest", Environments: []string{"us", "eu"}},
+					},
+				},
+			},
+		},
+		{
+			name: "unknown group",
+			givenRollouts: `
+rollouts:
+- group: staging
+  stages: [{name: all, environments: [canary]}]
+`,
+			wantErrContains: `rollout of unknown group "staging"`,
+		},
+		{
+			name: "duplicated rollout",
+			givenRollouts: `
+rollouts:
+- group: dev
+  stages: [{name: all, environments: [dev]}]
+- group: dev
+  stages: [{name: all, environments: [dev]}]
+`,
+			wantErrContains: `duplicated rollout of group "dev"`,
+		},
+		{
+			name: "environment of another group",
+			givenRollouts: `
+rollouts:
+- group: dev
+  stages: [{name: all, environments: [dev, canary]}]
+`,
+			wantErrContains: `environment "canary" of stage "all" is not part of the group`,
+		},
+		{
+			name: "environment in multiple stages",
+			givenRollouts: `
+rollouts:
+- group: prod
+  stages:
+  - {name: canary, environments: [canary]}
+  - {name: rest, environments: [canary, eu, us]}
+`,
+			wantErrContains: `environment "canary" is part of both stage "canary" and stage "rest"`,
+		},
+		{
+			name: "environment in no stage",
+			givenRollouts: `
+rollouts:
+- group: prod
+  stages:
+  - {name: canary, environments: [canary]}
+  - {name: rest, environments: [eu]}
+`,
+			wantErrContains: `environment "us" is not part of any stage`,
+		},
+		{
+			name: "duplicated stage name",
+			givenRollouts: `
+rollouts:
+- group: prod
+  stages:
+  - {name: first, environments: [canary]}
+  - {name: first, environments: [eu, us]}
+`,
+			wantErrContains: `duplicated stage name "first"`,
+		},
+		{
+			name: "invalid wait time",
+			givenRollouts: `
+rollouts:
+- group: prod
+  stages:
+  - {name: canary, environments: [canary], gate: {wait: soon}}
+  - {name: rest, environments: [eu, us]}
+`,
+			wantErrContains: `invalid wait time "soon"`,
+		},
+		{
+			name: "empty gate",
+			givenRollouts: `
+rollouts:
+- group: prod
+  stages:
+  - {name: canary, environments: [canary], gate: {}}
+  - {name: rest, environments: [eu, us]}
+`,
+			wantErrContains: `neither 'wait' nor 'command' is defined`,
+		},
+		{
+			name: "gate of last stage",
+			givenRollouts: `
+rollouts:
+- group: prod
+  stages:
+  - {name: canary, environments: [canary]}
+  - {name: rest, environments: [eu, us], gate: {wait: 1m}}
+`,
+			wantErrContains: `the last stage "rest" defines a gate, but no stage follows it`,
+		},
+		{
+			name: "no stages",
+			givenRollouts: `
+rollouts:
+- group: prod
+  stages: []
+`,
+			wantErrContains: `invalid rollout of group "prod": no stages defined`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fs := afero.NewMemMapFs()
+			assert.NoError(t, afero.WriteFile(fs, "manifest.yaml", []byte(groups+tt.givenRollouts), 0400))
+
+			got, errs := Load(&Context{
+				Fs:           fs,
+				ManifestPath: "manifest.yaml",
+				// rollouts are validated against all groups, even if only some are loaded
+				Groups: []string{"dev"},
+			})
+
+			if tt.wantErrContains != "" {
+				assert.ErrorContains(t, errors.Join(errs...), tt.wantErrContains)
+				return
+			}
+			assert.Empty(t, errs)
+			assert.Equal(t, tt.want, got.Rollouts)
+		})
+	}
+}
+
+func TestPartialsPathIsLoaded(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	assert.NoError(t, afero.WriteFile(fs, "manifest.yaml", []byte(`
+manifestVersion: 1.0
+projects: [{name: a, path: p}]
+partials: shared/partials
+`), 0400))
+
+	got, errs := Load(&Context{
+		Fs:           fs,
+		ManifestPath: "manifest.yaml",
+	})
+	assert.Empty(t, errs)
+	assert.Equal(t, filepath.FromSlash("shared/partials"), got.PartialsPath)
+}
+
+func TestURLConstraintsAreChecked(t *testing.T) {
+	t.Setenv("ENV_TOKEN", "token")
+
+	manifest := []byte(`
+manifestVersion: 1.0
+projects: [{name: a, path: p}]
+environmentGroups:
+- name: default
+  environments:
+  - name: env
+    url:
+      type: environment
+      value: ENV_URL
+      constraints:
+        pattern: 'https://[a-z0-9]+\.live\.dynatrace\.com'
+    auth:
+      token:
+        name: ENV_TOKEN
+`)
+
+	t.Run("valid value is loaded", func(t *testing.T) {
+		t.Setenv("ENV_URL", "https://abc123.live.dynatrace.com")
+
+		fs := afero.NewMemMapFs()
+		assert.NoError(t, afero.WriteFile(fs, "manifest.yaml", manifest, 0400))
+
+		got, errs := Load(&Context{Fs: fs, ManifestPath: "manifest.yaml"})
+		assert.Empty(t, errs)
+		assert.Equal(t, "https://abc123.live.dynatrace.com", got.Environments["env"].URL.Value)
+	})
+
+	t.Run("violated constraint is reported with the value", func(t *testing.T) {
+		t.Setenv("ENV_URL", "http://abc123.live.dynatrace.com")
+
+		fs := afero.NewMemMapFs()
+		assert.NoError(t, afero.WriteFile(fs, "manifest.yaml", manifest, 0400))
+
+		_, errs := Load(&Context{Fs: fs, ManifestPath: "manifest.yaml"})
+		assert.Len(t, errs, 1)
+		assert.ErrorContains(t, errors.Join(errs...), `constraint violated: value "http://abc123.live.dynatrace.com" does not match pattern`)
+	})
+}
+
+func TestAccountUUIDConstraintsAreChecked(t *testing.T) {
+	_, err := loadAccountUUID(persistence.TypedValue{
+		Type:        persistence.TypeValue,
+		Value:       "8e8c04f3-5e6e-4d9a-a7b1-09f2e6e4d4f1",
+		Constraints: map[string]any{"enum": []interface{}{"e5b8a7c4-1d2e-4f3a-9b8c-7d6e5f4a3b2c"}},
+	})
+	assert.ErrorContains(t, err, `constraint violated: value "8e8c04f3-5e6e-4d9a-a7b1-09f2e6e4d4f1" is not one of`)
+}
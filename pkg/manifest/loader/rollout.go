@@ -0,0 +1,156 @@
+/*
+ * @license
+ * Copyright 2024 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package loader
+
+import (
+	"fmt"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/manifest"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/manifest/internal/persistence"
+	"time"
+)
+
+// parseRollouts validates and converts the rollouts defined in the manifest. Rollouts are validated against all groups,
+// regardless of which groups and environments are loaded.
+func parseRollouts(context *Context, groups []persistence.Group, rollouts []persistence.Rollout) (map[string]manifest.Rollout, []error) {
+	if len(rollouts) == 0 {
+		return nil, nil
+	}
+
+	environmentsPerGroup := make(map[string][]string, len(groups))
+	for _, g := range groups {
+		for _, e := range g.Environments {
+			environmentsPerGroup[g.Name] = append(environmentsPerGroup[g.Name], e.Name)
+		}
+	}
+
+	var errs []error
+	result := make(map[string]manifest.Rollout, len(rollouts))
+
+	for i, r := range rollouts {
+		if r.Group == "" {
+			errs = append(errs, newManifestLoaderError(context.ManifestPath, fmt.Sprintf("missing group of rollout on index `%d`", i)))
+			continue
+		}
+		envs, found := environmentsPerGroup[r.Group]
+		if !found {
+			errs = append(errs, newManifestLoaderError(context.ManifestPath, fmt.Sprintf("rollout of unknown group %q", r.Group)))
+			continue
+		}
+		if _, exists := result[r.Group]; exists {
+			errs = append(errs, newManifestLoaderError(context.ManifestPath, fmt.Sprintf("duplicated rollout of group %q", r.Group)))
+			continue
+		}
+
+		rollout, rolloutErrs := parseRollout(r, envs)
+		for _, err := range rolloutErrs {
+			errs = append(errs, newManifestLoaderError(context.ManifestPath, fmt.Sprintf("invalid rollout of group %q: %s", r.Group, err)))
+		}
+		result[r.Group] = rollout
+	}
+
+	if errs != nil {
+		return nil, errs
+	}
+	return result, nil
+}
+
+func parseRollout(r persistence.Rollout, groupEnvironments []string) (manifest.Rollout, []error) {
+	var errs []error
+
+	if len(r.Stages) == 0 {
+		return manifest.Rollout{}, []error{fmt.Errorf("no stages defined")}
+	}
+
+	inGroup := make(map[string]bool, len(groupEnvironments))
+	for _, e := range groupEnvironments {
+		inGroup[e] = true
+	}
+
+	stageOfEnvironment := make(map[string]string, len(groupEnvironments))
+	stageNames := make(map[string]bool, len(r.Stages))
+	rollout := manifest.Rollout{Group: r.Group, Stages: make([]manifest.RolloutStage, 0, len(r.Stages))}
+
+	for i, s := range r.Stages {
+		if s.Name == "" {
+			errs = append(errs, fmt.Errorf("missing stage name on index `%d`", i))
+		} else if stageNames[s.Name] {
+			errs = append(errs, fmt.Errorf("duplicated stage name %q", s.Name))
+		}
+		stageNames[s.Name] = true
+
+		if len(s.Environments) == 0 {
+			errs = append(errs, fmt.Errorf("stage %q has no environments", s.Name))
+		}
+		for _, e := range s.Environments {
+			if !inGroup[e] {
+				errs = append(errs, fmt.Errorf("environment %q of stage %q is not part of the group", e, s.Name))
+				continue
+			}
+			if other, found := stageOfEnvironment[e]; found {
+				errs = append(errs, fmt.Errorf("environment %q is part of both stage %q and stage %q", e, other, s.Name))
+				continue
+			}
+			stageOfEnvironment[e] = s.Name
+		}
+
+		gate, err := parseGate(s.Gate)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("invalid gate of stage %q: %w", s.Name, err))
+		}
+		if gate.Defined() && i == len(r.Stages)-1 {
+			errs = append(errs, fmt.Errorf("the last stage %q defines a gate, but no stage follows it", s.Name))
+		}
+
+		rollout.Stages = append(rollout.Stages, manifest.RolloutStage{
+			Name:         s.Name,
+			Environments: s.Environments,
+			Gate:         gate,
+		})
+	}
+
+	for _, e := range groupEnvironments {
+		if _, found := stageOfEnvironment[e]; !found {
+			errs = append(errs, fmt.Errorf("environment %q is not part of any stage", e))
+		}
+	}
+
+	return rollout, errs
+}
+
+func parseGate(g *persistence.Gate) (manifest.Gate, error) {
+	if g == nil {
+		return manifest.Gate{}, nil
+	}
+
+	var gate manifest.Gate
+	if g.Wait != "" {
+		wait, err := time.ParseDuration(g.Wait)
+		if err != nil {
+			return manifest.Gate{}, fmt.Errorf("invalid wait time %q: %w", g.Wait, err)
+		}
+		if wait < 0 {
+			return manifest.Gate{}, fmt.Errorf("wait time %q must not be negative", g.Wait)
+		}
+		gate.Wait = wait
+	}
+	gate.Command = g.Command
+
+	if !gate.Defined() {
+		return manifest.Gate{}, fmt.Errorf("neither 'wait' nor 'command' is defined")
+	}
+	return gate, nil
+}
@@ -0,0 +1,167 @@
+/*
+ * @license
+ * Copyright 2024 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package loader
+
+import (
+	"errors"
+	"fmt"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/manifest"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/manifest/internal/persistence"
+	"os"
+)
+
+// parseVariables validates the variables defined in the manifest and sets their values for each of the given loaded
+// environments. Overrides are validated against all groups and environments, regardless of which environments are loaded.
+// Values are only loaded for the loaded environments, so environment variables of other environments need not be set.
+func parseVariables(context *Context, groups []persistence.Group, environments map[string]manifest.EnvironmentDefinition, variables []persistence.Variable) []error {
+	if len(variables) == 0 {
+		return nil
+	}
+
+	groupNames := make(map[string]bool, len(groups))
+	environmentNames := make(map[string]bool)
+	for _, g := range groups {
+		groupNames[g.Name] = true
+		for _, e := range g.Environments {
+			environmentNames[e.Name] = true
+		}
+	}
+
+	var errs []error
+	names := make(map[string]bool, len(variables))
+
+	for i, v := range variables {
+		if v.Name == "" {
+			errs = append(errs, newManifestLoaderError(context.ManifestPath, fmt.Sprintf("missing variable name on index `%d`", i)))
+			continue
+		}
+		if names[v.Name] {
+			errs = append(errs, newManifestLoaderError(context.ManifestPath, fmt.Sprintf("duplicated variable name %q", v.Name)))
+			continue
+		}
+		names[v.Name] = true
+
+		for _, err := range validateVariableOverrides(v, groupNames, environmentNames) {
+			errs = append(errs, newManifestLoaderError(context.ManifestPath, fmt.Sprintf("invalid variable %q: %s", v.Name, err)))
+		}
+	}
+
+	if errs != nil {
+		return errs
+	}
+
+	for name, env := range environments {
+		values := make(map[string]string, len(variables))
+
+		for _, v := range variables {
+			typedValue, defined := variableValueFor(v, env)
+			if !defined {
+				continue
+			}
+
+			val, err := loadVariableValue(context, typedValue)
+			if err != nil {
+				errs = append(errs, newManifestEnvironmentLoaderError(context.ManifestPath, env.Group, env.Name, fmt.Sprintf("failed to load variable %q: %s", v.Name, err)))
+				continue
+			}
+			values[v.Name] = val
+		}
+
+		env.Variables = values
+		environments[name] = env
+	}
+
+	return errs
+}
+
+func validateVariableOverrides(v persistence.Variable, groupNames, environmentNames map[string]bool) []error {
+	var errs []error
+
+	overriddenGroups := make(map[string]bool, len(v.GroupOverrides))
+	for _, o := range v.GroupOverrides {
+		if !groupNames[o.Group] {
+			errs = append(errs, fmt.Errorf("override of unknown group %q", o.Group))
+		} else if overriddenGroups[o.Group] {
+			errs = append(errs, fmt.Errorf("duplicated override of group %q", o.Group))
+		}
+		overriddenGroups[o.Group] = true
+	}
+
+	overriddenEnvironments := make(map[string]bool, len(v.EnvironmentOverrides))
+	for _, o := range v.EnvironmentOverrides {
+		if !environmentNames[o.Environment] {
+			errs = append(errs, fmt.Errorf("override of unknown environment %q", o.Environment))
+		} else if overriddenEnvironments[o.Environment] {
+			errs = append(errs, fmt.Errorf("duplicated override of environment %q", o.Environment))
+		}
+		overriddenEnvironments[o.Environment] = true
+	}
+
+	return errs
+}
+
+// variableValueFor returns the value of the variable for the given environment. Environment overrides take precedence
+// over group overrides, which take precedence over the value of the variable. If none of them is defined, the variable
+// is not defined for the environment.
+func variableValueFor(v persistence.Variable, env manifest.EnvironmentDefinition) (persistence.TypedValue, bool) {
+	for _, o := range v.EnvironmentOverrides {
+		if o.Environment == env.Name {
+			return o.Value, true
+		}
+	}
+
+	for _, o := range v.GroupOverrides {
+		if o.Group == env.Group {
+			return o.Value, true
+		}
+	}
+
+	if v.Value != nil {
+		return *v.Value, true
+	}
+	return persistence.TypedValue{}, false
+}
+
+func loadVariableValue(context *Context, v persistence.TypedValue) (string, error) {
+	var val string
+
+	switch v.Type {
+	case "", persistence.TypeValue: // shorthand or explicit type: value
+		val = v.Value
+	case persistence.TypeEnvironment:
+		if v.Value == "" {
+			return "", errors.New("name of the environment variable is missing")
+		}
+
+		if context.Opts.DoNotResolveEnvVars {
+			return fmt.Sprintf("SKIPPED RESOLUTION OF ENV_VAR: %s", v.Value), nil
+		}
+
+		var found bool
+		val, found = os.LookupEnv(v.Value)
+		if !found {
+			return "", fmt.Errorf("environment variable %q could not be found", v.Value)
+		}
+	default:
+		return "", fmt.Errorf("unexpected type: %q (expected one of %q, %q)", v.Type, persistence.TypeValue, persistence.TypeEnvironment)
+	}
+
+	if err := checkConstraints(v, val); err != nil {
+		return "", err
+	}
+	return val, nil
+}
@@ -0,0 +1,180 @@
+//go:build unit
+
+/*
+ * @license
+ * Copyright 2024 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package loader
+
+import (
+	"errors"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/manifest/internal/persistence"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+const variablesManifest = `
+manifestVersion: 1.0
+projects: [{name: a, path: p}]
+environmentGroups:
+- name: dev
+  environments:
+  - name: dev1
+    url: https://dev1.example.com
+    auth: {token: {name: ENV_TOKEN}}
+  - name: dev2
+    url: https://dev2.example.com
+    auth: {token: {name: ENV_TOKEN}}
+- name: prod
+  environments:
+  - name: prod1
+    url: https://prod1.example.com
+    auth: {token: {name: ENV_TOKEN}}
+variables:
+- name: domain
+  value: example.com
+- name: channel
+  value: '#dev-alerts'
+  groupOverrides:
+  - group: prod
+    value: '#prod-alerts'
+  environmentOverrides:
+  - environment: dev2
+    value:
+      type: environment
+      value: DEV2_CHANNEL
+- name: oncall
+  groupOverrides:
+  - group: prod
+    value:
+      type: environment
+      value: PROD_ONCALL
+      constraints:
+        pattern: '.+@example\.com'
+`
+
+func TestVariablesAreLoadedPerEnvironment(t *testing.T) {
+	t.Setenv("ENV_TOKEN", "token")
+	t.Setenv("DEV2_CHANNEL", "#dev2-alerts")
+	t.Setenv("PROD_ONCALL", "oncall@example.com")
+
+	fs := afero.NewMemMapFs()
+	require.NoError(t, afero.WriteFile(fs, "manifest.yaml", []byte(variablesManifest), 0400))
+
+	got, errs := Load(&Context{Fs: fs, ManifestPath: "manifest.yaml"})
+	require.Empty(t, errs)
+
+	assert.Equal(t, map[string]string{"domain": "example.com", "channel": "#dev-alerts"}, got.Environments["dev1"].Variables)
+	assert.Equal(t, map[string]string{"domain": "example.com", "channel": "#dev2-alerts"}, got.Environments["dev2"].Variables)
+	assert.Equal(t, map[string]string{"domain": "example.com", "channel": "#prod-alerts", "oncall": "oncall@example.com"}, got.Environments["prod1"].Variables)
+}
+
+func TestVariablesAreOnlyLoadedForLoadedEnvironments(t *testing.T) {
+	t.Setenv("ENV_TOKEN", "token")
+
+	fs := afero.NewMemMapFs()
+	require.NoError(t, afero.WriteFile(fs, "manifest.yaml", []byte(variablesManifest), 0400))
+
+	// neither DEV2_CHANNEL nor PROD_ONCALL are set, but they are not needed for dev1
+	got, errs := Load(&Context{Fs: fs, ManifestPath: "manifest.yaml", Environments: []string{"dev1"}})
+	require.Empty(t, errs)
+	assert.Equal(t, map[string]string{"domain": "example.com", "channel": "#dev-alerts"}, got.Environments["dev1"].Variables)
+}
+
+func TestVariablesLoadingErrors(t *testing.T) {
+	t.Setenv("ENV_TOKEN", "token")
+	t.Setenv("DEV2_CHANNEL", "#dev2-alerts")
+
+	tests := []struct {
+		name      string
+		variables string
+		want      string
+	}{
+		{
+			name:      "missing name",
+			variables: `[{value: a}]`,
+			want:      "missing variable name on index `0`",
+		},
+		{
+			name:      "duplicated name",
+			variables: `[{name: a, value: a}, {name: a, value: b}]`,
+			want:      `duplicated variable name "a"`,
+		},
+		{
+			name:      "unknown group",
+			variables: `[{name: a, groupOverrides: [{group: staging, value: a}]}]`,
+			want:      `invalid variable "a": override of unknown group "staging"`,
+		},
+		{
+			name:      "duplicated group override",
+			variables: `[{name: a, groupOverrides: [{group: dev, value: a}, {group: dev, value: b}]}]`,
+			want:      `invalid variable "a": duplicated override of group "dev"`,
+		},
+		{
+			name:      "unknown environment",
+			variables: `[{name: a, environmentOverrides: [{environment: dev3, value: a}]}]`,
+			want:      `invalid variable "a": override of unknown environment "dev3"`,
+		},
+		{
+			name:      "duplicated environment override",
+			variables: `[{name: a, environmentOverrides: [{environment: dev1, value: a}, {environment: dev1, value: b}]}]`,
+			want:      `invalid variable "a": duplicated override of environment "dev1"`,
+		},
+		{
+			name:      "missing environment variable",
+			variables: `[{name: a, value: {type: environment, value: UNDEFINED_ENV_VAR}}]`,
+			want:      `failed to load variable "a": environment variable "UNDEFINED_ENV_VAR" could not be found`,
+		},
+		{
+			name:      "unknown type",
+			variables: `[{name: a, value: {type: file, value: a.txt}}]`,
+			want:      `failed to load variable "a": unexpected type: "file"`,
+		},
+		{
+			name:      "violated constraint",
+			variables: `[{name: a, environmentOverrides: [{environment: dev2, value: {type: environment, value: DEV2_CHANNEL, constraints: {pattern: '#[a-z]+'}}}]}]`,
+			want:      `failed to load variable "a": constraint violated: value "#dev2-alerts" does not match pattern`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fs := afero.NewMemMapFs()
+			require.NoError(t, afero.WriteFile(fs, "manifest.yaml", []byte(`
+manifestVersion: 1.0
+projects: [{name: a, path: p}]
+environmentGroups:
+- name: dev
+  environments:
+  - name: dev1
+    url: https://dev1.example.com
+    auth: {token: {name: ENV_TOKEN}}
+  - name: dev2
+    url: https://dev2.example.com
+    auth: {token: {name: ENV_TOKEN}}
+variables: `+tt.variables), 0400))
+
+			_, errs := Load(&Context{Fs: fs, ManifestPath: "manifest.yaml"})
+			assert.ErrorContains(t, errors.Join(errs...), tt.want)
+		})
+	}
+}
+
+func TestVariablesWithoutResolvingEnvironmentVariables(t *testing.T) {
+	got, err := loadVariableValue(&Context{Opts: Options{DoNotResolveEnvVars: true}}, persistence.TypedValue{Type: persistence.TypeEnvironment, Value: "UNDEFINED_ENV_VAR"})
+	require.NoError(t, err)
+	assert.Equal(t, "SKIPPED RESOLUTION OF ENV_VAR: UNDEFINED_ENV_VAR", got)
+}
@@ -23,6 +23,7 @@ import (
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/oauth2/endpoints"
 	"github.com/google/uuid"
 	"golang.org/x/exp/maps"
+	"time"
 )
 
 type ProjectDefinition struct {
@@ -63,6 +64,10 @@ type EnvironmentDefinition struct {
 	Group string
 	URL   URLDefinition
 	Auth  Auth
+
+	// Variables holds the values of the manifest variables for this environment, with group and environment overrides applied.
+	// Key is the variable name.
+	Variables map[string]string
 }
 
 // URLType describes from where the url is loaded.
@@ -137,4 +142,61 @@ type Manifest struct {
 
 	// Accounts holds all accounts defined in the manifest. Key is the user-defined account name.
 	Accounts map[string]Account
+
+	// State defines where the deployment state is persisted. It is empty if no state file is used.
+	State StateDefinition
+
+	// PartialsPath is the folder holding template partials shared by all projects, relative to the manifest.
+	// It is empty if no shared partials are used.
+	PartialsPath string
+
+	// Rollouts defines the order in which the environments of a group are deployed. Key is the group name.
+	// Groups without a rollout have all of their environments deployed at once.
+	Rollouts map[string]Rollout
+}
+
+// StateDefinition holds information about the deployment state file
+type StateDefinition struct {
+	// Path of the state file, relative to the manifest. If empty, no state file is used.
+	Path string
+}
+
+// Enabled returns whether a state file is defined
+func (s StateDefinition) Enabled() bool {
+	return s.Path != ""
+}
+
+// Rollout orders the environments of a group into stages, which are deployed one after another
+type Rollout struct {
+	// Group is the name of the environment group rolled out
+	Group string
+
+	// Stages in the order they are deployed. Each environment of the group is part of exactly one stage.
+	Stages []RolloutStage
+}
+
+// RolloutStage is a set of environments deployed at the same time
+type RolloutStage struct {
+	// Name of the stage, used in logs
+	Name string
+
+	// Environments deployed in this stage
+	Environments []string
+
+	// Gate must pass after the stage was deployed successfully, before the next stage is deployed
+	Gate Gate
+}
+
+// Gate is a check that must pass before a rollout proceeds to the next stage. An empty Gate always passes.
+type Gate struct {
+	// Wait is the time to wait before the next stage is deployed
+	Wait time.Duration
+
+	// Command is run after Wait elapsed. The next stage is only deployed if it exits with code 0.
+	Command string
+}
+
+// Defined returns whether the gate does anything
+func (g Gate) Defined() bool {
+	return g.Wait > 0 || g.Command != ""
 }
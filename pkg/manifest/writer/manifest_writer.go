@@ -23,6 +23,7 @@ import (
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/manifest/internal/persistence"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/version"
 	"path/filepath"
+	"slices"
 	"strings"
 
 	"github.com/spf13/afero"
@@ -84,6 +85,13 @@ func Write(context *Context, manifestToWrite manifest.Manifest) error {
 		m.Accounts = toWriteableAccounts(manifestToWrite.Accounts)
 	}
 
+	if manifestToWrite.State.Enabled() {
+		m.State = &persistence.State{Path: filepath.ToSlash(manifestToWrite.State.Path)}
+	}
+
+	m.Partials = filepath.ToSlash(manifestToWrite.PartialsPath)
+	m.Rollouts = toWriteableRollouts(manifestToWrite.Rollouts)
+
 	return persistManifestToDisk(context, m)
 }
 
@@ -260,3 +268,31 @@ func toWriteableAccounts(accounts map[string]manifest.Account) []persistence.Acc
 	}
 	return out
 }
+
+// toWriteableRollouts converts the rollouts, sorted by group name
+func toWriteableRollouts(rollouts map[string]manifest.Rollout) []persistence.Rollout {
+	if len(rollouts) == 0 {
+		return nil
+	}
+
+	result := make([]persistence.Rollout, 0, len(rollouts))
+	for _, r := range rollouts {
+		stages := make([]persistence.RolloutStage, 0, len(r.Stages))
+		for _, s := range r.Stages {
+			stage := persistence.RolloutStage{Name: s.Name, Environments: s.Environments}
+			if s.Gate.Defined() {
+				stage.Gate = &persistence.Gate{Command: s.Gate.Command}
+				if s.Gate.Wait > 0 {
+					stage.Gate.Wait = s.Gate.Wait.String()
+				}
+			}
+			stages = append(stages, stage)
+		}
+		result = append(result, persistence.Rollout{Group: r.Group, Stages: stages})
+	}
+
+	slices.SortFunc(result, func(a, b persistence.Rollout) int {
+		return strings.Compare(a.Group, b.Group)
+	})
+	return result
+}
@@ -30,6 +30,7 @@ import (
 	"sort"
 	"strconv"
 	"testing"
+	"time"
 )
 
 func Test_toWriteableProjects(t *testing.T) {
@@ -422,6 +423,41 @@ func Test_toWriteableUrl(t *testing.T) {
 	}
 }
 
+func Test_toWriteableRollouts(t *testing.T) {
+	assert.Nil(t, toWriteableRollouts(nil))
+
+	got := toWriteableRollouts(map[string]manifest.Rollout{
+		"prod": {
+			Group: "prod",
+			Stages: []manifest.RolloutStage{
+				{Name: "canary", Environments: []string{"canary"}, Gate: manifest.Gate{Wait: 90 * time.Minute, Command: "./check.sh"}},
+				{Name: "rest", Environments: []string{"eu", "us"}},
+			},
+		},
+		"dev": {
+			Group:  "dev",
+			Stages: []manifest.RolloutStage{{Name: "first", Environments: []string{"dev1"}, Gate: manifest.Gate{Command: "true"}}, {Name: "second", Environments: []string{"dev2"}}},
+		},
+	})
+
+	assert.Equal(t, []persistence.Rollout{
+		{
+			Group: "dev",
+			Stages: []persistence.RolloutStage{
+				{Name: "first", Environments: []string{"dev1"}, Gate: &persistence.Gate{Command: "true"}},
+				{Name: "second", Environments: []string{"dev2"}},
+			},
+		},
+		{
+			Group: "prod",
+			Stages: []persistence.RolloutStage{
+				{Name: "canary", Environments: []string{"canary"}, Gate: &persistence.Gate{Wait: "1h30m0s", Command: "./check.sh"}},
+				{Name: "rest", Environments: []string{"eu", "us"}},
+			},
+		},
+	}, got)
+}
+
 func Test_toWritableToken(t *testing.T) {
 	tests := []struct {
 		name  string
@@ -36,12 +36,30 @@ type ConfigDefinition struct {
 	Template       string                     `yaml:"template,omitempty" json:"template,omitempty" jsonschema:"required,description=The filepath to the JSON template used for this configuration"`
 	Skip           ConfigParameter            `yaml:"skip,omitempty" json:"skip,omitempty" jsonschema:"description=Defines whether this config should be skipped when deploying."`
 	OriginObjectId string                     `yaml:"originObjectId,omitempty" json:"originObjectId,omitempty" jsonschema:"description=description=The identifier of the Dynatrace object this config originated from - this is filled when downloading, but can also be set to tie a config to a specific object."`
+	Merge          *MergeDefinition           `yaml:"merge,omitempty" json:"merge,omitempty" jsonschema:"description=Merges this config into the existing Dynatrace object instead of replacing it - only supported for Classic Config API and Settings 2.0 types."`
+	DeployPolicy   string                     `yaml:"deployPolicy,omitempty" json:"deployPolicy,omitempty" jsonschema:"enum=upsert,enum=createOnly,enum=updateOnly,description=Defines how this config is deployed: upsert (default) creates or updates the object - createOnly only creates the object if it does not exist yet - updateOnly only updates the object if it already exists."`
+}
+
+type MergeDefinition struct {
+	OwnedPaths   []string `yaml:"ownedPaths,omitempty" json:"ownedPaths,omitempty" jsonschema:"description=JSON pointers of the values managed by this config - all other values of the existing object are kept. Can not be combined with ignoredPaths."`
+	IgnoredPaths []string `yaml:"ignoredPaths,omitempty" json:"ignoredPaths,omitempty" jsonschema:"description=JSON pointers of the values not managed by this config - they are kept as found in the existing object. Can not be combined with ownedPaths."`
+}
+
+// ForEachDefinition defines the items configs are generated for. Exactly one of its fields must be set.
+type ForEachDefinition struct {
+	Items  []any            `yaml:"items,omitempty" json:"items,omitempty" jsonschema:"description=The items to generate configs for. Items are objects whose properties are available as parameters - plain values are available as parameter 'item'."`
+	File   string           `yaml:"file,omitempty" json:"file,omitempty" jsonschema:"description=A YAML file containing a list of items or a CSV file with a header row and one item per row - relative to this config file. YAML data files are not loaded as config files."`
+	Matrix map[string][]any `yaml:"matrix,omitempty" json:"matrix,omitempty" jsonschema:"description=Generates a config for each combination of the given values - the keys are the names of the parameters."`
 }
 
 type TopLevelConfigDefinition struct {
 	Id     string           `yaml:"id" json:"id" jsonschema:"required,description=The monaco identifier for this config - is used in references and for some generated IDs in Dynatrace environments."`
 	Config ConfigDefinition `yaml:"config" json:"config" jsonschema:"required,description=The actual configuration to be applied"`
 	Type   TypeDefinition   `yaml:"type" json:"type" jsonschema:"required,oneof_type=string;object,description=The type of this configuration, e.g. a config API or a Settings 2.0 schema."`
+	// ForEach generates one config per item instead of this config
+	ForEach *ForEachDefinition `yaml:"forEach,omitempty" json:"forEach,omitempty" jsonschema:"description=Generates one config per item instead of this config. The values of an item are available as parameters and can be used in the config ID - e.g. 'mz-{{ .team }}' - and in the configId of reference parameters."`
+	// Extends references a config whose definition is used as the base of this config
+	Extends string `yaml:"extends,omitempty" json:"extends,omitempty" jsonschema:"description=References a config this config is based on - either by its config ID if it is of the same project and type or as 'project:type:configId'. Its template and parameters and skip and overrides are used unless this config defines them."`
 	// GroupOverrides overwrite specific parts of the Config when deploying it to any environment in a given group
 	GroupOverrides []GroupOverride `yaml:"groupOverrides,omitempty" json:"groupOverrides,omitempty" jsonschema:"description=GroupOverrides overwrite specific parts of the Config when deploying it to any environment in a given group."`
 	// EnvironmentOverrides overwrite specific parts of the Config when deploying it to a given environment
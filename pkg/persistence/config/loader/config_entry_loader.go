@@ -19,6 +19,7 @@ package loader
 import (
 	"errors"
 	"fmt"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/json"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/coordinate"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/parameter"
@@ -143,6 +144,14 @@ func applyOverrides(base *persistence.ConfigDefinition, override persistence.Con
 		base.OriginObjectId = override.OriginObjectId
 	}
 
+	if override.DeployPolicy != "" {
+		base.DeployPolicy = override.DeployPolicy
+	}
+
+	if override.Merge != nil {
+		base.Merge = override.Merge
+	}
+
 	for name, param := range override.Parameters {
 		base.Parameters[name] = param
 	}
@@ -164,7 +173,7 @@ func getConfigFromDefinition(
 		}
 	}
 
-	tmpl, err := template.NewFileTemplate(fs, filepath.Join(context.Folder, definition.Template))
+	tmpl, err := template.NewFileTemplateWithPartials(fs, filepath.Join(context.Folder, definition.Template), context.PartialFolders)
 
 	var errs []error
 
@@ -180,6 +189,21 @@ func getConfigFromDefinition(
 		parameters = make(map[string]parameter.Parameter)
 	}
 
+	// the name and scope are parameters too, and may define constraints as well
+	constrainedParameters := make(map[string]persistence.ConfigParameter, len(definition.Parameters)+2)
+	for name, param := range definition.Parameters {
+		constrainedParameters[name] = param
+	}
+	if definition.Name != nil {
+		constrainedParameters[config.NameParameter] = definition.Name
+	}
+	if configType.Scope != nil {
+		constrainedParameters[config.ScopeParameter] = configType.Scope
+	}
+
+	constraints, constraintErrors := parseParameterConstraints(context, environment, configId, constrainedParameters)
+	errs = append(errs, constraintErrors...)
+
 	skipConfig := false
 
 	if definition.Skip != nil {
@@ -191,6 +215,16 @@ func getConfigFromDefinition(
 		}
 	}
 
+	deployPolicy := config.DeployPolicy(definition.DeployPolicy)
+	if deployPolicy != "" && !slices.Contains(config.DeployPolicies, deployPolicy) {
+		errs = append(errs, newDetailedDefinitionParserError(configId, context, environment, fmt.Sprintf("unknown deployPolicy %q, must be one of %v", definition.DeployPolicy, config.DeployPolicies)))
+	}
+
+	merge, mergeErr := parseMerge(definition.Merge, configType)
+	if mergeErr != nil {
+		errs = append(errs, newDetailedDefinitionParserError(configId, context, environment, fmt.Sprintf("invalid merge definition: %s", mergeErr)))
+	}
+
 	if err != nil {
 		return config.Config{}, []error{fmt.Errorf("failed to parse type of config %q: %w", configId, err)}
 	}
@@ -232,15 +266,43 @@ func getConfigFromDefinition(
 			Type:     context.Type,
 			ConfigId: configId,
 		},
-		Type:           configType.Type,
-		Group:          environment.Group,
-		Environment:    environment.Name,
-		Parameters:     parameters,
-		Skip:           skipConfig,
-		OriginObjectId: definition.OriginObjectId,
+		Type:                 configType.Type,
+		Group:                environment.Group,
+		Environment:          environment.Name,
+		Parameters:           parameters,
+		ParameterConstraints: constraints,
+		Skip:                 skipConfig,
+		OriginObjectId:       definition.OriginObjectId,
+		DeployPolicy:         deployPolicy,
+		Merge:                merge,
 	}, nil
 }
 
+func parseMerge(definition *persistence.MergeDefinition, configType persistence.TypeDefinition) (*config.Merge, error) {
+	if definition == nil {
+		return nil, nil
+	}
+
+	if id := configType.Type.ID(); id != config.ClassicApiTypeId && id != config.SettingsTypeId {
+		return nil, fmt.Errorf("merging is not supported for config type %q", id)
+	}
+
+	if len(definition.OwnedPaths) > 0 && len(definition.IgnoredPaths) > 0 {
+		return nil, fmt.Errorf("only one of `ownedPaths` and `ignoredPaths` can be defined")
+	}
+	if len(definition.OwnedPaths) == 0 && len(definition.IgnoredPaths) == 0 {
+		return nil, fmt.Errorf("either `ownedPaths` or `ignoredPaths` must be defined")
+	}
+
+	for _, p := range slices.Concat(definition.OwnedPaths, definition.IgnoredPaths) {
+		if _, err := json.ParsePointer(p); err != nil {
+			return nil, fmt.Errorf("invalid path %q: %w", p, err)
+		}
+	}
+
+	return &config.Merge{OwnedPaths: definition.OwnedPaths, IgnoredPaths: definition.IgnoredPaths}, nil
+}
+
 func parseSkip(
 	context *singleConfigEntryLoadContext,
 	environmentDefinition manifest.EnvironmentDefinition,
@@ -36,11 +36,18 @@ type LoaderContext struct {
 	Environments    []manifest.EnvironmentDefinition
 	KnownApis       map[string]struct{}
 	ParametersSerDe map[string]parameter.ParameterSerDe
+	// PartialFolders are the folders partials included by templates are looked up in, in order
+	PartialFolders []string
+	// Definitions provides the configs of other files and projects that configs can extend. If it is nil, configs
+	// can only extend configs of the same file.
+	Definitions *ConfigDefinitionIndex
 }
 
 // configFileLoaderContext is a context for each config-file
 type configFileLoaderContext struct {
 	*LoaderContext
+	// Fs the config file is loaded from
+	Fs     afero.Fs
 	Folder string
 	Path   string
 }
@@ -89,16 +96,28 @@ func LoadConfigFile(fs afero.Fs, context *LoaderContext, filePath string) ([]con
 
 	configLoaderContext := &configFileLoaderContext{
 		LoaderContext: context,
+		Fs:            fs,
 		Folder:        filepath.Dir(filePath),
 		Path:          filePath,
 	}
 
+	definedConfigEntries, err = expandGenerators(fs, configLoaderContext.Folder, definedConfigEntries)
+	if err != nil {
+		return nil, []error{newLoadError(filePath, err)}
+	}
+
 	var errs []error
 	var configs []config.Config
 
 	for _, cgf := range definedConfigEntries {
 
-		result, definitionErrors := parseConfigEntry(fs, configLoaderContext, cgf.Id, cgf)
+		resolved, err := resolveExtends(context.Definitions, definitionSource{definition: cgf, project: context.ProjectId, folder: configLoaderContext.Folder, siblings: definedConfigEntries})
+		if err != nil {
+			errs = append(errs, newDefinitionParserError(cgf.Id, &singleConfigEntryLoadContext{configFileLoaderContext: configLoaderContext, Type: cgf.Type.GetApiType()}, err.Error()))
+			continue
+		}
+
+		result, definitionErrors := parseConfigEntry(fs, configLoaderContext, resolved.Id, resolved)
 
 		if len(definitionErrors) > 0 {
 			errs = append(errs, definitionErrors...)
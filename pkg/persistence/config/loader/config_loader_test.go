@@ -24,10 +24,13 @@ import (
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/coordinate"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/parameter"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/parameter/compound"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/parameter/entity"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/parameter/environment"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/parameter/file"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/parameter/list"
 	ref "github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/parameter/reference"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/parameter/value"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/parameter/variable"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/template"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/manifest"
 	"github.com/google/go-cmp/cmp"
@@ -53,11 +56,14 @@ func Test_parseConfigs(t *testing.T) {
 				Auth: manifest.Auth{
 					Token: manifest.AuthSecret{Name: "token var"},
 				},
+				Variables: map[string]string{"domain": "example.com"},
 			},
 		},
 		ParametersSerDe: config.DefaultParameterParsers,
 	}
 
+	maxThreshold := 100.0
+
 	tests := []struct {
 		name              string
 		filePathArgument  string
@@ -440,6 +446,162 @@ configs:
 				},
 			},
 		},
+		{
+			name:             "loads constraints of parameters",
+			filePathArgument: "test-file.yaml",
+			filePathOnDisk:   "test-file.yaml",
+			fileContentOnDisk: `
+configs:
+- id: profile-id
+  config:
+    name:
+      type: value
+      value: 'Star Trek'
+      constraints:
+        required: true
+    template: 'profile.json'
+    parameters:
+      threshold:
+        type: value
+        value: 42
+        constraints:
+          type: int
+          max: 100
+  type:
+    settings:
+      schema: 'builtin:profile.test'
+      scope:
+        type: value
+        value: environment
+        constraints:
+          enum: [environment, tenant]`,
+			wantConfigs: []config.Config{
+				{
+					Coordinate: coordinate.Coordinate{
+						Project:  "project",
+						Type:     "builtin:profile.test",
+						ConfigId: "profile-id",
+					},
+					Type: config.SettingsType{
+						SchemaId: "builtin:profile.test",
+					},
+					Template: template.NewInMemoryTemplate("profile.json", "{}"),
+					Parameters: config.Parameters{
+						"name":                &value.ValueParameter{Value: "Star Trek"},
+						"threshold":           &value.ValueParameter{Value: 42},
+						config.ScopeParameter: &value.ValueParameter{Value: "environment"},
+					},
+					ParameterConstraints: map[string]parameter.Constraints{
+						"name":                {Required: true},
+						"threshold":           {Type: parameter.ConstraintTypeInt, Max: &maxThreshold},
+						config.ScopeParameter: {Enum: []string{"environment", "tenant"}},
+					},
+					Environment: "env name",
+					Group:       "default",
+				},
+			},
+		},
+		{
+			name:             "fails on invalid constraints",
+			filePathArgument: "test-file.yaml",
+			filePathOnDisk:   "test-file.yaml",
+			fileContentOnDisk: `
+configs:
+- id: profile-id
+  config:
+    name: 'Star Trek'
+    template: 'profile.json'
+    parameters:
+      threshold:
+        type: value
+        value: 42
+        constraints:
+          type: float
+  type:
+    api: some-api`,
+			wantErrorsContain: []string{`invalid constraints: invalid constraint type "float"`},
+		},
+		{
+			name:             "loads variable parameters with the values of the environment",
+			filePathArgument: "test-file.yaml",
+			filePathOnDisk:   "test-file.yaml",
+			fileContentOnDisk: `
+configs:
+- id: profile-id
+  config:
+    name: 'Star Trek'
+    template: 'profile.json'
+    parameters:
+      domain:
+        type: variable
+        name: domain
+      channel:
+        type: variable
+        name: channel
+        default: '#alerts'
+  type:
+    api: some-api`,
+			wantConfigs: []config.Config{
+				{
+					Coordinate: coordinate.Coordinate{
+						Project:  "project",
+						Type:     "some-api",
+						ConfigId: "profile-id",
+					},
+					Type: config.ClassicApiType{
+						Api: "some-api",
+					},
+					Template: template.NewInMemoryTemplate("profile.json", "{}"),
+					Parameters: config.Parameters{
+						config.NameParameter: &value.ValueParameter{Value: "Star Trek"},
+						"domain":             &variable.VariableParameter{Name: "domain", Defined: true, Value: "example.com"},
+						"channel":            &variable.VariableParameter{Name: "channel", HasDefaultValue: true, DefaultValue: "#alerts"},
+					},
+					Environment: "env name",
+					Group:       "default",
+				},
+			},
+		},
+		{
+			name:             "loads settings 2.0 config with an entity parameter as scope",
+			filePathArgument: "test-file.yaml",
+			filePathOnDisk:   "test-file.yaml",
+			fileContentOnDisk: `
+configs:
+- id: profile-id
+  config:
+    name: 'Star Trek > Star Wars'
+    template: 'profile.json'
+  type:
+    settings:
+      schema: 'builtin:profile.test'
+      schemaVersion: '1.0'
+      scope:
+        type: entity
+        selector: 'type(HOST_GROUP),entityName("payments")'
+        onMismatch: warn`,
+			wantConfigs: []config.Config{
+				{
+					Coordinate: coordinate.Coordinate{
+						Project:  "project",
+						Type:     "builtin:profile.test",
+						ConfigId: "profile-id",
+					},
+					Type: config.SettingsType{
+						SchemaId:      "builtin:profile.test",
+						SchemaVersion: "1.0",
+					},
+					Template: template.NewInMemoryTemplate("profile.json", "{}"),
+					Parameters: config.Parameters{
+						"name":                &value.ValueParameter{Value: "Star Trek > Star Wars"},
+						config.ScopeParameter: &entity.EntityParameter{Selector: `type(HOST_GROUP),entityName("payments")`, OnMismatch: entity.OnMismatchWarn},
+					},
+					Skip:        false,
+					Environment: "env name",
+					Group:       "default",
+				},
+			},
+		},
 		{
 			name:             "loads settings 2.0 config with a full reference as scope",
 			filePathArgument: "test-file.yaml",
@@ -942,6 +1104,542 @@ configs:
 				},
 			},
 		},
+		{
+			name:             "loads config with deploy policy",
+			filePathArgument: "test-file.yaml",
+			filePathOnDisk:   "test-file.yaml",
+			fileContentOnDisk: `
+configs:
+- id: profile
+  config:
+    name: Star Trek Service
+    template: profile.json
+    deployPolicy: createOnly
+  type:
+    api: some-api`,
+			wantConfigs: []config.Config{
+				{
+					Coordinate: coordinate.Coordinate{
+						Project:  "project",
+						Type:     "some-api",
+						ConfigId: "profile",
+					},
+					Type: config.ClassicApiType{
+						Api: "some-api",
+					},
+					Template: template.NewInMemoryTemplate("profile.json", "{}"),
+					Parameters: config.Parameters{
+						"name": &value.ValueParameter{Value: "Star Trek Service"},
+					},
+					Environment:  "env name",
+					Group:        "default",
+					DeployPolicy: config.DeployPolicyCreateOnly,
+				},
+			},
+		},
+		{
+			name:             "loads config with deploy policy group override",
+			filePathArgument: "test-file.yaml",
+			filePathOnDisk:   "test-file.yaml",
+			fileContentOnDisk: `
+configs:
+- id: profile
+  config:
+    name: Star Trek Service
+    template: profile.json
+    deployPolicy: createOnly
+  type:
+    api: some-api
+  groupOverrides:
+    - group: default
+      override:
+        deployPolicy: updateOnly`,
+			wantConfigs: []config.Config{
+				{
+					Coordinate: coordinate.Coordinate{
+						Project:  "project",
+						Type:     "some-api",
+						ConfigId: "profile",
+					},
+					Type: config.ClassicApiType{
+						Api: "some-api",
+					},
+					Template: template.NewInMemoryTemplate("profile.json", "{}"),
+					Parameters: config.Parameters{
+						"name": &value.ValueParameter{Value: "Star Trek Service"},
+					},
+					Environment:  "env name",
+					Group:        "default",
+					DeployPolicy: config.DeployPolicyUpdateOnly,
+				},
+			},
+		},
+		{
+			name:             "reports error for unknown deploy policy",
+			filePathArgument: "test-file.yaml",
+			filePathOnDisk:   "test-file.yaml",
+			fileContentOnDisk: `
+configs:
+- id: profile
+  config:
+    name: Star Trek Service
+    template: profile.json
+    deployPolicy: neverEver
+  type:
+    api: some-api`,
+			wantErrorsContain: []string{`unknown deployPolicy "neverEver"`},
+		},
+		{
+			name:             "loads config with merge definition",
+			filePathArgument: "test-file.yaml",
+			filePathOnDisk:   "test-file.yaml",
+			fileContentOnDisk: `
+configs:
+- id: profile
+  config:
+    name: Star Trek Service
+    template: profile.json
+    merge:
+      ownedPaths: [/name, /rules]
+  type:
+    api: some-api
+  environmentOverrides:
+    - environment: "env name"
+      override:
+        merge:
+          ignoredPaths: [/tiles]`,
+			wantConfigs: []config.Config{
+				{
+					Coordinate: coordinate.Coordinate{
+						Project:  "project",
+						Type:     "some-api",
+						ConfigId: "profile",
+					},
+					Type: config.ClassicApiType{
+						Api: "some-api",
+					},
+					Template: template.NewInMemoryTemplate("profile.json", "{}"),
+					Parameters: config.Parameters{
+						"name": &value.ValueParameter{Value: "Star Trek Service"},
+					},
+					Environment: "env name",
+					Group:       "default",
+					Merge:       &config.Merge{IgnoredPaths: []string{"/tiles"}},
+				},
+			},
+		},
+		{
+			name:             "reports error if merge defines owned and ignored paths",
+			filePathArgument: "test-file.yaml",
+			filePathOnDisk:   "test-file.yaml",
+			fileContentOnDisk: `
+configs:
+- id: profile
+  config:
+    name: Star Trek Service
+    template: profile.json
+    merge:
+      ownedPaths: [/name]
+      ignoredPaths: [/tiles]
+  type:
+    api: some-api`,
+			wantErrorsContain: []string{"only one of `ownedPaths` and `ignoredPaths` can be defined"},
+		},
+		{
+			name:             "reports error for invalid merge path",
+			filePathArgument: "test-file.yaml",
+			filePathOnDisk:   "test-file.yaml",
+			fileContentOnDisk: `
+configs:
+- id: profile
+  config:
+    name: Star Trek Service
+    template: profile.json
+    merge:
+      ownedPaths: [name]
+  type:
+    api: some-api`,
+			wantErrorsContain: []string{`invalid path "name"`},
+		},
+		{
+			name:             "reports error if merge is used for unsupported type",
+			filePathArgument: "test-file.yaml",
+			filePathOnDisk:   "test-file.yaml",
+			fileContentOnDisk: `
+configs:
+- id: workflow
+  config:
+    template: profile.json
+    merge:
+      ownedPaths: [/title]
+  type:
+    automation:
+      resource: workflow`,
+			wantErrorsContain: []string{`merging is not supported for config type "automation"`},
+		},
+		{
+			name:             "loads file parameter with environment override",
+			filePathArgument: "test-file.yaml",
+			filePathOnDisk:   "test-file.yaml",
+			fileContentOnDisk: `
+configs:
+- id: profile
+  config:
+    name: Star Trek Service
+    template: profile.json
+    parameters:
+      fragment:
+        type: file
+        path: other-environment.json
+  type:
+    api: some-api
+  environmentOverrides:
+    - environment: "env name"
+      override:
+        parameters:
+          fragment:
+            type: file
+            path: profile.json
+            escape: false`,
+			wantConfigs: []config.Config{
+				{
+					Coordinate: coordinate.Coordinate{
+						Project:  "project",
+						Type:     "some-api",
+						ConfigId: "profile",
+					},
+					Type: config.ClassicApiType{
+						Api: "some-api",
+					},
+					Template: template.NewInMemoryTemplate("profile.json", "{}"),
+					Parameters: config.Parameters{
+						"name":     &value.ValueParameter{Value: "Star Trek Service"},
+						"fragment": &file.FileParameter{Path: "profile.json", Content: "{}", Escape: false},
+					},
+					Environment: "env name",
+					Group:       "default",
+				},
+			},
+		},
+		{
+			name:             "extends config of the same file",
+			filePathArgument: "test-file.yaml",
+			filePathOnDisk:   "test-file.yaml",
+			fileContentOnDisk: `
+configs:
+- id: base
+  config:
+    name: Base Profile
+    template: profile.json
+    skip: true
+    parameters:
+      severity: HIGH
+      team: none
+  type:
+    api: some-api
+  groupOverrides:
+    - group: default
+      override:
+        parameters:
+          severity: LOW
+- id: team-a
+  extends: base
+  config:
+    name: Team A Profile
+    skip: false
+    parameters:
+      team: a
+  type:
+    api: some-api
+- id: team-b
+  extends: project:some-api:team-a
+  config:
+    parameters:
+      team: b
+  type:
+    api: some-api
+  groupOverrides:
+    - group: default
+      override:
+        parameters:
+          severity: MEDIUM`,
+			wantConfigs: []config.Config{
+				{
+					Coordinate: coordinate.Coordinate{Project: "project", Type: "some-api", ConfigId: "base"},
+					Type:       config.ClassicApiType{Api: "some-api"},
+					Template:   template.NewInMemoryTemplate("profile.json", "{}"),
+					Parameters: config.Parameters{
+						"name":     &value.ValueParameter{Value: "Base Profile"},
+						"severity": &value.ValueParameter{Value: "LOW"},
+						"team":     &value.ValueParameter{Value: "none"},
+					},
+					Skip:        true,
+					Environment: "env name",
+					Group:       "default",
+				},
+				{
+					Coordinate: coordinate.Coordinate{Project: "project", Type: "some-api", ConfigId: "team-a"},
+					Type:       config.ClassicApiType{Api: "some-api"},
+					Template:   template.NewInMemoryTemplate("profile.json", "{}"),
+					Parameters: config.Parameters{
+						"name":     &value.ValueParameter{Value: "Team A Profile"},
+						"severity": &value.ValueParameter{Value: "LOW"},
+						"team":     &value.ValueParameter{Value: "a"},
+					},
+					Environment: "env name",
+					Group:       "default",
+				},
+				{
+					Coordinate: coordinate.Coordinate{Project: "project", Type: "some-api", ConfigId: "team-b"},
+					Type:       config.ClassicApiType{Api: "some-api"},
+					Template:   template.NewInMemoryTemplate("profile.json", "{}"),
+					Parameters: config.Parameters{
+						"name":     &value.ValueParameter{Value: "Team A Profile"},
+						"severity": &value.ValueParameter{Value: "MEDIUM"},
+						"team":     &value.ValueParameter{Value: "b"},
+					},
+					Environment: "env name",
+					Group:       "default",
+				},
+			},
+		},
+		{
+			name:             "reports error for cyclic extends",
+			filePathArgument: "test-file.yaml",
+			filePathOnDisk:   "test-file.yaml",
+			fileContentOnDisk: `
+configs:
+- id: a
+  extends: b
+  config:
+    name: A
+    template: profile.json
+  type:
+    api: some-api
+- id: b
+  extends: a
+  config:
+    name: B
+  type:
+    api: some-api`,
+			wantErrorsContain: []string{
+				"cyclic extends: project:some-api:a -> project:some-api:b -> project:some-api:a",
+				"cyclic extends: project:some-api:b -> project:some-api:a -> project:some-api:b",
+			},
+		},
+		{
+			name:             "reports error if extended config does not exist",
+			filePathArgument: "test-file.yaml",
+			filePathOnDisk:   "test-file.yaml",
+			fileContentOnDisk: `
+configs:
+- id: a
+  extends: missing
+  config:
+    name: A
+    template: profile.json
+  type:
+    api: some-api`,
+			wantErrorsContain: []string{`extended config "project:some-api:missing" not found`},
+		},
+		{
+			name:             "reports error for invalid extends",
+			filePathArgument: "test-file.yaml",
+			filePathOnDisk:   "test-file.yaml",
+			fileContentOnDisk: `
+configs:
+- id: a
+  extends: some-api:base
+  config:
+    name: A
+    template: profile.json
+  type:
+    api: some-api`,
+			wantErrorsContain: []string{"invalid `extends` \"some-api:base\""},
+		},
+		{
+			name:             "generates configs for each item",
+			filePathArgument: "test-file.yaml",
+			filePathOnDisk:   "test-file.yaml",
+			fileContentOnDisk: `
+configs:
+- id: "profile-{{ lower .team }}"
+  forEach:
+    items:
+      - team: A
+        severity: HIGH
+      - team: B
+        severity: LOW
+  config:
+    name: Profile
+    template: profile.json
+    parameters:
+      zone:
+        type: reference
+        configType: some-api
+        configId: "zone-{{ lower .team }}"
+        property: id
+  type:
+    api: some-api
+- id: "zone-{{ .item }}"
+  forEach:
+    items: [a, b]
+  config:
+    name: Zone
+    template: profile.json
+  type:
+    api: some-api`,
+			wantConfigs: []config.Config{
+				{
+					Coordinate: coordinate.Coordinate{Project: "project", Type: "some-api", ConfigId: "profile-a"},
+					Type:       config.ClassicApiType{Api: "some-api"},
+					Template:   template.NewInMemoryTemplate("profile.json", "{}"),
+					Parameters: config.Parameters{
+						"name":     &value.ValueParameter{Value: "Profile"},
+						"team":     &value.ValueParameter{Value: "A"},
+						"severity": &value.ValueParameter{Value: "HIGH"},
+						"zone":     ref.New("project", "some-api", "zone-a", "id"),
+					},
+					Environment: "env name",
+					Group:       "default",
+				},
+				{
+					Coordinate: coordinate.Coordinate{Project: "project", Type: "some-api", ConfigId: "profile-b"},
+					Type:       config.ClassicApiType{Api: "some-api"},
+					Template:   template.NewInMemoryTemplate("profile.json", "{}"),
+					Parameters: config.Parameters{
+						"name":     &value.ValueParameter{Value: "Profile"},
+						"team":     &value.ValueParameter{Value: "B"},
+						"severity": &value.ValueParameter{Value: "LOW"},
+						"zone":     ref.New("project", "some-api", "zone-b", "id"),
+					},
+					Environment: "env name",
+					Group:       "default",
+				},
+				{
+					Coordinate: coordinate.Coordinate{Project: "project", Type: "some-api", ConfigId: "zone-a"},
+					Type:       config.ClassicApiType{Api: "some-api"},
+					Template:   template.NewInMemoryTemplate("profile.json", "{}"),
+					Parameters: config.Parameters{
+						"name": &value.ValueParameter{Value: "Zone"},
+						"item": &value.ValueParameter{Value: "a"},
+					},
+					Environment: "env name",
+					Group:       "default",
+				},
+				{
+					Coordinate: coordinate.Coordinate{Project: "project", Type: "some-api", ConfigId: "zone-b"},
+					Type:       config.ClassicApiType{Api: "some-api"},
+					Template:   template.NewInMemoryTemplate("profile.json", "{}"),
+					Parameters: config.Parameters{
+						"name": &value.ValueParameter{Value: "Zone"},
+						"item": &value.ValueParameter{Value: "b"},
+					},
+					Environment: "env name",
+					Group:       "default",
+				},
+			},
+		},
+		{
+			name:             "generates configs for each combination of a matrix",
+			filePathArgument: "test-file.yaml",
+			filePathOnDisk:   "test-file.yaml",
+			fileContentOnDisk: `
+configs:
+- id: "{{ .team }}-{{ .stage }}"
+  forEach:
+    matrix:
+      team: [a, b]
+      stage: [dev, prod]
+  config:
+    name: Profile
+    template: profile.json
+    parameters:
+      stage: fixed
+  type:
+    api: some-api`,
+			wantConfigs: []config.Config{
+				{
+					Coordinate: coordinate.Coordinate{Project: "project", Type: "some-api", ConfigId: "a-dev"},
+					Type:       config.ClassicApiType{Api: "some-api"},
+					Template:   template.NewInMemoryTemplate("profile.json", "{}"),
+					Parameters: config.Parameters{
+						"name":  &value.ValueParameter{Value: "Profile"},
+						"team":  &value.ValueParameter{Value: "a"},
+						"stage": &value.ValueParameter{Value: "fixed"},
+					},
+					Environment: "env name",
+					Group:       "default",
+				},
+				{
+					Coordinate: coordinate.Coordinate{Project: "project", Type: "some-api", ConfigId: "b-dev"},
+					Type:       config.ClassicApiType{Api: "some-api"},
+					Template:   template.NewInMemoryTemplate("profile.json", "{}"),
+					Parameters: config.Parameters{
+						"name":  &value.ValueParameter{Value: "Profile"},
+						"team":  &value.ValueParameter{Value: "b"},
+						"stage": &value.ValueParameter{Value: "fixed"},
+					},
+					Environment: "env name",
+					Group:       "default",
+				},
+				{
+					Coordinate: coordinate.Coordinate{Project: "project", Type: "some-api", ConfigId: "a-prod"},
+					Type:       config.ClassicApiType{Api: "some-api"},
+					Template:   template.NewInMemoryTemplate("profile.json", "{}"),
+					Parameters: config.Parameters{
+						"name":  &value.ValueParameter{Value: "Profile"},
+						"team":  &value.ValueParameter{Value: "a"},
+						"stage": &value.ValueParameter{Value: "fixed"},
+					},
+					Environment: "env name",
+					Group:       "default",
+				},
+				{
+					Coordinate: coordinate.Coordinate{Project: "project", Type: "some-api", ConfigId: "b-prod"},
+					Type:       config.ClassicApiType{Api: "some-api"},
+					Template:   template.NewInMemoryTemplate("profile.json", "{}"),
+					Parameters: config.Parameters{
+						"name":  &value.ValueParameter{Value: "Profile"},
+						"team":  &value.ValueParameter{Value: "b"},
+						"stage": &value.ValueParameter{Value: "fixed"},
+					},
+					Environment: "env name",
+					Group:       "default",
+				},
+			},
+		},
+		{
+			name:             "reports error if generator defines no items",
+			filePathArgument: "test-file.yaml",
+			filePathOnDisk:   "test-file.yaml",
+			fileContentOnDisk: `
+configs:
+- id: "profile-{{ .item }}"
+  forEach: {}
+  config:
+    name: Profile
+    template: profile.json
+  type:
+    api: some-api`,
+			wantErrorsContain: []string{"exactly one of `items`, `file` and `matrix` must be defined in `forEach`"},
+		},
+		{
+			name:             "reports error if config ID can not be rendered",
+			filePathArgument: "test-file.yaml",
+			filePathOnDisk:   "test-file.yaml",
+			fileContentOnDisk: `
+configs:
+- id: "profile-{{ .team }}"
+  forEach:
+    items: [a]
+  config:
+    name: Profile
+    template: profile.json
+  type:
+    api: some-api`,
+			wantErrorsContain: []string{"failed to render config ID for item 0"},
+		},
 		{
 			name:             "reports error if config API is missing name",
 			filePathArgument: "test-file.yaml",
@@ -1443,3 +2141,48 @@ func makeCompoundParam(t *testing.T, refs []parameter.ParameterReference) *compo
 	assert.NoError(t, err)
 	return compoundParam
 }
+
+func TestLoadConfigFile_GeneratesConfigsFromItemsFile(t *testing.T) {
+	context := &LoaderContext{
+		ProjectId:       "project",
+		KnownApis:       map[string]struct{}{"some-api": {}},
+		Environments:    []manifest.EnvironmentDefinition{{Name: "env", Group: "default"}},
+		ParametersSerDe: config.DefaultParameterParsers,
+	}
+
+	tests := []struct {
+		name      string
+		itemsFile string
+		items     string
+	}{
+		{"CSV", "teams.csv", "team,owner\na,alice\nb,bob\n"},
+		{"YAML", "teams.yaml", "- team: a\n  owner: alice\n- team: b\n  owner: bob\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			testFs := afero.NewMemMapFs()
+			_ = afero.WriteFile(testFs, "proj/profile.json", []byte("{}"), 0644)
+			_ = afero.WriteFile(testFs, "proj/data/"+tt.itemsFile, []byte(tt.items), 0644)
+			_ = afero.WriteFile(testFs, "proj/config.yaml", []byte(`
+configs:
+- id: "profile-{{ .team }}"
+  forEach:
+    file: data/`+tt.itemsFile+`
+  config:
+    name: Profile
+    template: profile.json
+  type:
+    api: some-api`), 0644)
+
+			gotConfigs, gotErrors := LoadConfigFile(testFs, context, "proj/config.yaml")
+			assert.Empty(t, gotErrors)
+			assert.Len(t, gotConfigs, 2)
+			for i, want := range []struct{ team, owner string }{{"a", "alice"}, {"b", "bob"}} {
+				assert.Equal(t, "profile-"+want.team, gotConfigs[i].Coordinate.ConfigId)
+				assert.Equal(t, value.New(want.team), gotConfigs[i].Parameters["team"])
+				assert.Equal(t, value.New(want.owner), gotConfigs[i].Parameters["owner"])
+			}
+		})
+	}
+}
@@ -0,0 +1,309 @@
+/*
+ * @license
+ * Copyright 2024 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package loader
+
+import (
+	"fmt"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/coordinate"
+	fileParam "github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/parameter/file"
+	refParam "github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/parameter/reference"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/persistence/config/internal/persistence"
+	"github.com/spf13/afero"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// ConfigDefinitionIndex provides the config definitions of all projects of a manifest, so that configs can extend
+// configs defined in other files and projects. The files of a project are only read once a config of it is extended.
+type ConfigDefinitionIndex struct {
+	fs           afero.Fs
+	projectPaths map[string]string
+	definitions  map[string][]definitionSource
+}
+
+// NewConfigDefinitionIndex creates a ConfigDefinitionIndex for the projects at the given paths, by project name
+func NewConfigDefinitionIndex(fs afero.Fs, projectPaths map[string]string) *ConfigDefinitionIndex {
+	return &ConfigDefinitionIndex{
+		fs:           fs,
+		projectPaths: projectPaths,
+		definitions:  make(map[string][]definitionSource),
+	}
+}
+
+// definitionSource is a config definition together with where it is defined
+type definitionSource struct {
+	definition persistence.TopLevelConfigDefinition
+	project    string
+	// folder of the file the config is defined in, which relative paths of the definition are based on
+	folder string
+	// siblings are all definitions of the file the config is defined in
+	siblings []persistence.TopLevelConfigDefinition
+}
+
+func (s definitionSource) coordinate() coordinate.Coordinate {
+	return coordinate.Coordinate{Project: s.project, Type: s.definition.Type.GetApiType(), ConfigId: s.definition.Id}
+}
+
+// find returns the definition of the config with the given coordinate. Files that can not be parsed are ignored, as
+// their errors are reported when loading their project.
+func (i *ConfigDefinitionIndex) find(c coordinate.Coordinate) (definitionSource, bool, error) {
+	if i == nil {
+		return definitionSource{}, false, nil
+	}
+
+	if _, loaded := i.definitions[c.Project]; !loaded {
+		path, found := i.projectPaths[c.Project]
+		if !found {
+			return definitionSource{}, false, nil
+		}
+
+		configFiles, err := FindConfigFiles(i.fs, path)
+		if err != nil {
+			return definitionSource{}, false, fmt.Errorf("failed to walk files of project %q: %w", c.Project, err)
+		}
+
+		sources := make([]definitionSource, 0)
+		for _, file := range configFiles {
+			data, err := afero.ReadFile(i.fs, file)
+			if err != nil {
+				continue
+			}
+			definitions, err := loadConfigDefinitions(data)
+			if err != nil {
+				continue
+			}
+			if definitions, err = expandGenerators(i.fs, filepath.Dir(file), definitions); err != nil {
+				continue
+			}
+			for _, d := range definitions {
+				sources = append(sources, definitionSource{definition: d, project: c.Project, folder: filepath.Dir(file), siblings: definitions})
+			}
+		}
+		i.definitions[c.Project] = sources
+	}
+
+	for _, s := range i.definitions[c.Project] {
+		if s.coordinate() == c {
+			return s, true, nil
+		}
+	}
+	return definitionSource{}, false, nil
+}
+
+// resolveExtends returns the given definition with the definitions of all configs it extends applied, and its template
+// path relative to the folder of the given source. Cyclic extends result in an error.
+func resolveExtends(index *ConfigDefinitionIndex, source definitionSource) (persistence.TopLevelConfigDefinition, error) {
+	return resolveExtendsChain(index, source, nil)
+}
+
+func resolveExtendsChain(index *ConfigDefinitionIndex, source definitionSource, chain []coordinate.Coordinate) (persistence.TopLevelConfigDefinition, error) {
+	if source.definition.Extends == "" {
+		return source.definition, nil
+	}
+
+	chain = append(chain, source.coordinate())
+
+	baseCoordinate, err := parseExtends(source.definition.Extends, source.coordinate())
+	if err != nil {
+		return persistence.TopLevelConfigDefinition{}, err
+	}
+
+	if slices.Contains(chain, baseCoordinate) {
+		names := make([]string, 0, len(chain)+1)
+		for _, c := range append(chain, baseCoordinate) {
+			names = append(names, c.String())
+		}
+		return persistence.TopLevelConfigDefinition{}, fmt.Errorf("cyclic extends: %s", strings.Join(names, " -> "))
+	}
+
+	base, found, err := findBase(index, source, baseCoordinate)
+	if err != nil {
+		return persistence.TopLevelConfigDefinition{}, err
+	}
+	if !found {
+		return persistence.TopLevelConfigDefinition{}, fmt.Errorf("extended config %q not found", baseCoordinate)
+	}
+
+	base.definition, err = resolveExtendsChain(index, base, chain)
+	if err != nil {
+		return persistence.TopLevelConfigDefinition{}, err
+	}
+
+	return extend(base, source)
+}
+
+// parseExtends parses the coordinate of an extended config. A plain config ID references a config of the same project
+// and type as the extending config, all other configs are referenced as 'project:type:configId'.
+func parseExtends(extends string, current coordinate.Coordinate) (coordinate.Coordinate, error) {
+	parts := strings.Split(extends, ":")
+	switch {
+	case len(parts) == 1:
+		return coordinate.Coordinate{Project: current.Project, Type: current.Type, ConfigId: extends}, nil
+	case len(parts) >= 3:
+		// types may contain colons, e.g. settings schemas like builtin:alerting.profile
+		return coordinate.Coordinate{Project: parts[0], Type: strings.Join(parts[1:len(parts)-1], ":"), ConfigId: parts[len(parts)-1]}, nil
+	default:
+		return coordinate.Coordinate{}, fmt.Errorf("invalid `extends` %q: must be a config ID or 'project:type:configId'", extends)
+	}
+}
+
+// findBase looks up the extended config. Configs defined in the same file as the extending config take precedence.
+func findBase(index *ConfigDefinitionIndex, source definitionSource, c coordinate.Coordinate) (definitionSource, bool, error) {
+	if c.Project == source.project {
+		for _, d := range source.siblings {
+			if d.Id == c.ConfigId && d.Type.GetApiType() == c.Type {
+				return definitionSource{definition: d, project: source.project, folder: source.folder, siblings: source.siblings}, true, nil
+			}
+		}
+	}
+	return index.find(c)
+}
+
+// extend applies the definition of the given source on top of the given base definition. The extending config keeps
+// its own ID and type, everything else is copied from the base config unless the extending config defines it.
+func extend(base definitionSource, source definitionSource) (persistence.TopLevelConfigDefinition, error) {
+	result := source.definition
+	result.Extends = ""
+
+	var err error
+	if result.Config, err = extendConfigDefinition(base.definition.Config, source.definition.Config, base, source); err != nil {
+		return persistence.TopLevelConfigDefinition{}, err
+	}
+	// the origin object identifies the object of the base config, not the one of the extending config
+	result.Config.OriginObjectId = source.definition.Config.OriginObjectId
+
+	result.GroupOverrides = nil
+	for _, o := range base.definition.GroupOverrides {
+		override := o
+		var extending persistence.ConfigDefinition
+		if i := slices.IndexFunc(source.definition.GroupOverrides, func(c persistence.GroupOverride) bool { return c.Group == o.Group }); i >= 0 {
+			extending = source.definition.GroupOverrides[i].Override
+		}
+		if override.Override, err = extendConfigDefinition(o.Override, extending, base, source); err != nil {
+			return persistence.TopLevelConfigDefinition{}, err
+		}
+		result.GroupOverrides = append(result.GroupOverrides, override)
+	}
+	for _, o := range source.definition.GroupOverrides {
+		if !slices.ContainsFunc(base.definition.GroupOverrides, func(b persistence.GroupOverride) bool { return b.Group == o.Group }) {
+			result.GroupOverrides = append(result.GroupOverrides, o)
+		}
+	}
+
+	result.EnvironmentOverrides = nil
+	for _, o := range base.definition.EnvironmentOverrides {
+		override := o
+		var extending persistence.ConfigDefinition
+		if i := slices.IndexFunc(source.definition.EnvironmentOverrides, func(c persistence.EnvironmentOverride) bool { return c.Environment == o.Environment }); i >= 0 {
+			extending = source.definition.EnvironmentOverrides[i].Override
+		}
+		if override.Override, err = extendConfigDefinition(o.Override, extending, base, source); err != nil {
+			return persistence.TopLevelConfigDefinition{}, err
+		}
+		result.EnvironmentOverrides = append(result.EnvironmentOverrides, override)
+	}
+	for _, o := range source.definition.EnvironmentOverrides {
+		if !slices.ContainsFunc(base.definition.EnvironmentOverrides, func(b persistence.EnvironmentOverride) bool { return b.Environment == o.Environment }) {
+			result.EnvironmentOverrides = append(result.EnvironmentOverrides, o)
+		}
+	}
+
+	return result, nil
+}
+
+// extendConfigDefinition returns a copy of base with the given definition applied. Everything taken from base is
+// rebased onto the extending config: the template and file parameter paths are rewritten to be relative to the folder of
+// the extending config, and references to configs of the base project are made explicit. Generators are expanded in the
+// folder of their own file before configs are extended, so their data files need no rebasing.
+func extendConfigDefinition(base persistence.ConfigDefinition, definition persistence.ConfigDefinition, baseSource, source definitionSource) (persistence.ConfigDefinition, error) {
+	result := persistence.ConfigDefinition{Parameters: make(map[string]persistence.ConfigParameter)}
+	applyOverrides(&result, base)
+
+	if result.Template != "" && baseSource.folder != source.folder {
+		rel, err := filepath.Rel(source.folder, filepath.Join(baseSource.folder, result.Template))
+		if err != nil {
+			return persistence.ConfigDefinition{}, fmt.Errorf("failed to resolve template of extended config: %w", err)
+		}
+		result.Template = rel
+	}
+
+	for name, p := range result.Parameters {
+		rebased, err := rebaseParameter(p, baseSource, source)
+		if err != nil {
+			return persistence.ConfigDefinition{}, fmt.Errorf("failed to rebase parameter %q of extended config: %w", name, err)
+		}
+		result.Parameters[name] = rebased
+	}
+
+	applyOverrides(&result, definition)
+	return result, nil
+}
+
+// rebaseParameter returns a copy of the given parameter of the base config that has the same meaning when being defined
+// by the extending config. File paths are made relative to the folder of the extending config, and references to other
+// configs that rely on the project and type of the base config get them set explicitly.
+func rebaseParameter(p persistence.ConfigParameter, base, source definitionSource) (persistence.ConfigParameter, error) {
+	m, ok := p.(map[interface{}]interface{})
+	if !ok {
+		return p, nil
+	}
+
+	switch toString(m["type"]) {
+	case fileParam.FileParameterType:
+		path, found := m["path"]
+		if !found || base.folder == source.folder {
+			return p, nil
+		}
+		rel, err := filepath.Rel(source.folder, filepath.Join(base.folder, filepath.FromSlash(toString(path))))
+		if err != nil {
+			return nil, err
+		}
+		rebased := copyParameter(m)
+		rebased["path"] = filepath.ToSlash(rel)
+		return rebased, nil
+
+	case refParam.ReferenceParameterType:
+		// references without configId reference the config itself, which is the extending config
+		if _, found := m["configId"]; !found {
+			return p, nil
+		}
+		_, projectSet := m["project"]
+		_, typeSet := m["configType"]
+		baseType := base.definition.Type.GetApiType()
+
+		setProject := !projectSet && base.project != source.project
+		rebased := copyParameter(m)
+		if setProject {
+			rebased["project"] = base.project
+		}
+		// references setting the project must set the type, too
+		if !typeSet && (setProject || baseType != source.definition.Type.GetApiType()) {
+			rebased["configType"] = baseType
+		}
+		return rebased, nil
+	}
+	return p, nil
+}
+
+func copyParameter(m map[interface{}]interface{}) map[interface{}]interface{} {
+	result := make(map[interface{}]interface{}, len(m))
+	for k, v := range m {
+		result[k] = v
+	}
+	return result
+}
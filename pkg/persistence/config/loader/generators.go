@@ -0,0 +1,304 @@
+/*
+ * @license
+ * Copyright 2024 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package loader
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/files"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/maps"
+	refParam "github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/parameter/reference"
+	valueParam "github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/parameter/value"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/template"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/persistence/config/internal/persistence"
+	"github.com/spf13/afero"
+	"gopkg.in/yaml.v2"
+	"path/filepath"
+	"slices"
+	"strings"
+	gotemplate "text/template" // nosemgrep: go.lang.security.audit.xss.import-text-template.import-text-template
+)
+
+// itemParameterName is the name of the parameter holding items that are plain values instead of objects
+const itemParameterName = "item"
+
+// FindConfigFiles returns all YAML files in the given folder and its sub-folders that define configs. YAML data files
+// referenced by generators of these files are excluded, as they contain the items of the generators instead of configs.
+func FindConfigFiles(fs afero.Fs, folder string) ([]string, error) {
+	yamlFiles, err := files.FindYamlFiles(fs, folder)
+	if err != nil {
+		return nil, err
+	}
+
+	dataFiles := make(map[string]struct{})
+	for _, file := range yamlFiles {
+		for _, dataFile := range generatorDataFiles(fs, file) {
+			dataFiles[dataFile] = struct{}{}
+		}
+	}
+
+	configFiles := make([]string, 0, len(yamlFiles))
+	for _, file := range yamlFiles {
+		if _, isDataFile := dataFiles[filepath.Clean(file)]; !isDataFile {
+			configFiles = append(configFiles, file)
+		}
+	}
+	return configFiles, nil
+}
+
+// generatorDataFiles returns the cleaned paths of all data files referenced by generators of the given config file.
+// Files that can not be parsed are ignored, as their errors are reported when loading them.
+func generatorDataFiles(fs afero.Fs, file string) []string {
+	data, err := afero.ReadFile(fs, file)
+	if err != nil {
+		return nil
+	}
+
+	var definition struct {
+		Configs []struct {
+			ForEach *persistence.ForEachDefinition `yaml:"forEach"`
+		} `yaml:"configs"`
+	}
+	if err := yaml.Unmarshal(data, &definition); err != nil {
+		return nil
+	}
+
+	var dataFiles []string
+	for _, c := range definition.Configs {
+		if c.ForEach != nil && c.ForEach.File != "" {
+			dataFiles = append(dataFiles, filepath.Clean(filepath.Join(filepath.Dir(file), filepath.FromSlash(c.ForEach.File))))
+		}
+	}
+	return dataFiles
+}
+
+// expandGenerators replaces all definitions defining a persistence.ForEachDefinition by the configs they generate.
+// Data files are read relative to the given folder.
+func expandGenerators(fs afero.Fs, folder string, definitions []persistence.TopLevelConfigDefinition) ([]persistence.TopLevelConfigDefinition, error) {
+	var result []persistence.TopLevelConfigDefinition
+	var errs []error
+
+	for _, d := range definitions {
+		if d.ForEach == nil {
+			result = append(result, d)
+			continue
+		}
+
+		generated, err := generate(fs, folder, d)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to generate configs of %q: %w", d.Id, err))
+			continue
+		}
+		result = append(result, generated...)
+	}
+
+	return result, errors.Join(errs...)
+}
+
+// generate returns one definition per item of the generator of the given definition
+func generate(fs afero.Fs, folder string, definition persistence.TopLevelConfigDefinition) ([]persistence.TopLevelConfigDefinition, error) {
+	items, err := loadItems(fs, folder, *definition.ForEach)
+	if err != nil {
+		return nil, err
+	}
+
+	idTemplate, err := template.ParseTemplate(definition.Id, definition.Id)
+	if err != nil {
+		return nil, fmt.Errorf("invalid config ID template: %w", err)
+	}
+
+	result := make([]persistence.TopLevelConfigDefinition, 0, len(items))
+	for i, item := range items {
+		d := definition
+		d.ForEach = nil
+
+		if d.Id, err = render(idTemplate, item); err != nil {
+			return nil, fmt.Errorf("failed to render config ID for item %d: %w", i, err)
+		}
+
+		if d.Config, err = applyItem(d.Config, item, true); err != nil {
+			return nil, fmt.Errorf("failed to generate config %q: %w", d.Id, err)
+		}
+
+		d.GroupOverrides = slices.Clone(d.GroupOverrides)
+		for j := range d.GroupOverrides {
+			if d.GroupOverrides[j].Override, err = applyItem(d.GroupOverrides[j].Override, item, false); err != nil {
+				return nil, fmt.Errorf("failed to generate config %q: %w", d.Id, err)
+			}
+		}
+
+		d.EnvironmentOverrides = slices.Clone(d.EnvironmentOverrides)
+		for j := range d.EnvironmentOverrides {
+			if d.EnvironmentOverrides[j].Override, err = applyItem(d.EnvironmentOverrides[j].Override, item, false); err != nil {
+				return nil, fmt.Errorf("failed to generate config %q: %w", d.Id, err)
+			}
+		}
+
+		result = append(result, d)
+	}
+	return result, nil
+}
+
+// applyItem returns a copy of the given definition, whose reference parameters have their configId rendered with the
+// given item. If addParameters is set, the values of the item are added as parameters, unless the definition defines
+// parameters of the same name.
+func applyItem(definition persistence.ConfigDefinition, item map[string]any, addParameters bool) (persistence.ConfigDefinition, error) {
+	params := make(map[string]persistence.ConfigParameter, len(definition.Parameters)+len(item))
+
+	if addParameters {
+		for k, v := range item {
+			params[k] = map[interface{}]interface{}{"type": valueParam.ValueParameterType, "value": v}
+		}
+	}
+
+	for name, p := range definition.Parameters {
+		m, ok := p.(map[interface{}]interface{})
+		if !ok || toString(m["type"]) != refParam.ReferenceParameterType {
+			params[name] = p
+			continue
+		}
+
+		configId, ok := m["configId"].(string)
+		if !ok || !strings.Contains(configId, "{{") {
+			params[name] = p
+			continue
+		}
+
+		t, err := template.ParseTemplate(name, configId)
+		if err != nil {
+			return persistence.ConfigDefinition{}, fmt.Errorf("invalid configId template of parameter %q: %w", name, err)
+		}
+		rendered, err := render(t, item)
+		if err != nil {
+			return persistence.ConfigDefinition{}, fmt.Errorf("failed to render configId of parameter %q: %w", name, err)
+		}
+
+		ref := make(map[interface{}]interface{}, len(m))
+		for k, v := range m {
+			ref[k] = v
+		}
+		ref["configId"] = rendered
+		params[name] = ref
+	}
+
+	definition.Parameters = params
+	return definition, nil
+}
+
+func render(t *gotemplate.Template, item map[string]any) (string, error) {
+	var b bytes.Buffer
+	if err := t.Execute(&b, item); err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
+
+// loadItems returns the items defined by the given generator. Each item maps parameter names to values.
+func loadItems(fs afero.Fs, folder string, forEach persistence.ForEachDefinition) ([]map[string]any, error) {
+	defined := 0
+	for _, isSet := range []bool{forEach.Items != nil, forEach.File != "", forEach.Matrix != nil} {
+		if isSet {
+			defined++
+		}
+	}
+	if defined != 1 {
+		return nil, errors.New("exactly one of `items`, `file` and `matrix` must be defined in `forEach`")
+	}
+
+	switch {
+	case forEach.Items != nil:
+		return toItems(forEach.Items), nil
+	case forEach.File != "":
+		return loadItemsFile(fs, filepath.Join(folder, filepath.FromSlash(forEach.File)))
+	default:
+		return matrixItems(forEach.Matrix), nil
+	}
+}
+
+func toItems(values []any) []map[string]any {
+	items := make([]map[string]any, 0, len(values))
+	for _, v := range values {
+		if m, ok := v.(map[interface{}]interface{}); ok {
+			items = append(items, maps.ToStringMap(m))
+		} else {
+			items = append(items, map[string]any{itemParameterName: v})
+		}
+	}
+	return items
+}
+
+// loadItemsFile reads items from a CSV file with a header row, or a YAML file containing a list
+func loadItemsFile(fs afero.Fs, path string) ([]map[string]any, error) {
+	data, err := afero.ReadFile(fs, path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read items file: %w", err)
+	}
+
+	if strings.EqualFold(filepath.Ext(path), ".csv") {
+		records, err := csv.NewReader(bytes.NewReader(data)).ReadAll()
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse items file %q: %w", path, err)
+		}
+		if len(records) == 0 {
+			return nil, fmt.Errorf("items file %q has no header row", path)
+		}
+
+		items := make([]map[string]any, 0, len(records)-1)
+		for _, r := range records[1:] {
+			item := make(map[string]any, len(r))
+			for i, column := range records[0] {
+				item[column] = r[i]
+			}
+			items = append(items, item)
+		}
+		return items, nil
+	}
+
+	var values []any
+	if err := yaml.Unmarshal(data, &values); err != nil {
+		return nil, fmt.Errorf("failed to parse items file %q: %w", path, err)
+	}
+	return toItems(values), nil
+}
+
+// matrixItems returns an item for each combination of the values of the given matrix, ordered by the matrix keys
+func matrixItems(matrix map[string][]any) []map[string]any {
+	keys := make([]string, 0, len(matrix))
+	for k := range matrix {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+
+	items := []map[string]any{{}}
+	for _, k := range keys {
+		var expanded []map[string]any
+		for _, item := range items {
+			for _, v := range matrix[k] {
+				next := make(map[string]any, len(item)+1)
+				for ik, iv := range item {
+					next[ik] = iv
+				}
+				next[k] = v
+				expanded = append(expanded, next)
+			}
+		}
+		items = expanded
+	}
+	return items
+}
@@ -23,9 +23,11 @@ import (
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/coordinate"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/parameter"
+	entityParam "github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/parameter/entity"
 	envParam "github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/parameter/environment"
 	refParam "github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/parameter/reference"
 	valueParam "github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/parameter/value"
+	variableParam "github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/parameter/variable"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/manifest"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/persistence/config/internal/persistence"
 )
@@ -34,6 +36,8 @@ var allowedScopeParameterTypes = []string{
 	refParam.ReferenceParameterType,
 	valueParam.ValueParameterType,
 	envParam.EnvironmentVariableParameterType,
+	entityParam.EntityParameterType,
+	variableParam.VariableParameterType,
 }
 
 // isSupportedParamTypeForSkip check is 'skip' section of configuration supports specified param type
@@ -91,6 +95,45 @@ func parseParametersAndReferences(context *singleConfigEntryLoadContext, environ
 	return parameters, nil
 }
 
+// parseParameterConstraints parses the `constraints` of all parameters of the given map that define them
+func parseParameterConstraints(context *singleConfigEntryLoadContext, environment manifest.EnvironmentDefinition,
+	configId string, parameterMap map[string]persistence.ConfigParameter) (map[string]parameter.Constraints, []error) {
+
+	var result map[string]parameter.Constraints
+	var errs []error
+
+	for name, param := range parameterMap {
+		val, ok := param.(map[interface{}]interface{})
+		if !ok {
+			continue
+		}
+
+		definition, found := val["constraints"]
+		if !found {
+			continue
+		}
+
+		m, ok := definition.(map[interface{}]interface{})
+		if !ok {
+			errs = append(errs, newParameterDefinitionParserError(name, configId, context, environment, "`constraints` must map constraints to their values"))
+			continue
+		}
+
+		constraints, err := parameter.ParseConstraints(maps.ToStringMap(m))
+		if err != nil {
+			errs = append(errs, newParameterDefinitionParserError(name, configId, context, environment, fmt.Sprintf("invalid constraints: %s", err)))
+			continue
+		}
+
+		if result == nil {
+			result = make(map[string]parameter.Constraints)
+		}
+		result[name] = constraints
+	}
+
+	return result, errs
+}
+
 func validateParameterName(context *singleConfigEntryLoadContext, environment manifest.EnvironmentDefinition, configId string, name string) error {
 
 	for _, parameterName := range config.ReservedParameterNames {
@@ -131,6 +174,9 @@ func parseParameter(context *singleConfigEntryLoadContext, environment manifest.
 			},
 			ParameterName: name,
 			Value:         maps.ToStringMap(val),
+			Fs:            context.Fs,
+			Folder:        context.Folder,
+			Variables:     environment.Variables,
 		})
 	}
 
@@ -23,6 +23,7 @@ import (
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/coordinate"
 	configError "github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/errors"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/parameter"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/parameter/file"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/parameter/value"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/template"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/persistence/config/internal/persistence"
@@ -338,7 +339,9 @@ func extractCommonBase(configs []extendedConfigDefinition) (*persistence.ConfigD
 	// TODO refactor this monstrosity
 	if len(sharedParam) == 0 && (!checkResult.foundName || !checkResult.shareName) &&
 		(!checkResult.foundTemplate || !checkResult.shareTemplate) &&
-		(!checkResult.foundSkip || !checkResult.shareSkip) {
+		(!checkResult.foundSkip || !checkResult.shareSkip) &&
+		(!checkResult.foundDeployPolicy || !checkResult.shareDeployPolicy) &&
+		(!checkResult.foundMerge || !checkResult.shareMerge) {
 		return nil, configs
 	}
 
@@ -373,7 +376,7 @@ func createConfigDefinitionWithoutSharedValues(toReduce extendedConfigDefinition
 	}
 
 	if allParametersShared && checkResult.shareName &&
-		checkResult.shareSkip && checkResult.shareTemplate {
+		checkResult.shareSkip && checkResult.shareTemplate && checkResult.shareDeployPolicy && checkResult.shareMerge {
 		return nil
 	}
 
@@ -393,6 +396,14 @@ func createConfigDefinitionWithoutSharedValues(toReduce extendedConfigDefinition
 		result.Skip = toReduce.Skip
 	}
 
+	if !checkResult.shareDeployPolicy {
+		result.DeployPolicy = toReduce.DeployPolicy
+	}
+
+	if !checkResult.shareMerge {
+		result.Merge = toReduce.Merge
+	}
+
 	return result
 }
 
@@ -411,6 +422,14 @@ func createCommonConfigDefinition(checkResult propertyCheckResult, sharedParamet
 		result.Skip = checkResult.skip
 	}
 
+	if checkResult.shareDeployPolicy {
+		result.DeployPolicy = checkResult.deployPolicy
+	}
+
+	if checkResult.shareMerge {
+		result.Merge = checkResult.merge
+	}
+
 	if len(sharedParameters) > 0 {
 		result.Parameters = sharedParameters
 	}
@@ -453,17 +472,29 @@ type propertyCheckResult struct {
 	shareSkip bool
 	foundSkip bool
 	skip      interface{}
+
+	shareDeployPolicy bool
+	foundDeployPolicy bool
+	deployPolicy      string
+
+	shareMerge bool
+	foundMerge bool
+	merge      *persistence.MergeDefinition
 }
 
 func testForSameProperties(configs []extendedConfigDefinition) propertyCheckResult {
 	name := configs[0].Name
 	templ := configs[0].Template
 	skip := configs[0].Skip
+	deployPolicy := configs[0].DeployPolicy
+	merge := configs[0].Merge
 
 	var (
 		sameName,
 		sameTemplate,
-		sameSkip = true, true, true
+		sameSkip,
+		sameDeployPolicy,
+		sameMerge = true, true, true, true, true
 	)
 
 	for _, c := range configs {
@@ -472,6 +503,8 @@ func testForSameProperties(configs []extendedConfigDefinition) propertyCheckResu
 		sameSkip = sameSkip && (reflect.DeepEqual(skip, c.Skip) ||
 			(skip == nil && c.Skip == false) ||
 			(skip == false && c.Skip == nil))
+		sameDeployPolicy = sameDeployPolicy && deployPolicy == c.DeployPolicy
+		sameMerge = sameMerge && reflect.DeepEqual(merge, c.Merge)
 	}
 
 	if !sameName {
@@ -486,6 +519,14 @@ func testForSameProperties(configs []extendedConfigDefinition) propertyCheckResu
 		skip = nil
 	}
 
+	if !sameDeployPolicy {
+		deployPolicy = ""
+	}
+
+	if !sameMerge {
+		merge = nil
+	}
+
 	return propertyCheckResult{
 		shareName: sameName,
 		foundName: name != nil || !sameName,
@@ -498,6 +539,14 @@ func testForSameProperties(configs []extendedConfigDefinition) propertyCheckResu
 		shareSkip: sameSkip,
 		foundSkip: skip != nil || !sameSkip,
 		skip:      skip,
+
+		shareDeployPolicy: sameDeployPolicy,
+		foundDeployPolicy: deployPolicy != "" || !sameDeployPolicy,
+		deployPolicy:      deployPolicy,
+
+		shareMerge: sameMerge,
+		foundMerge: merge != nil || !sameMerge,
+		merge:      merge,
 	}
 }
 
@@ -514,14 +563,14 @@ func toConfigDefinitions(context *serializerContext, configs []config.Config) ([
 	var templates []configTemplate
 
 	for _, c := range configs {
-		definition, templ, convertErrs := toConfigDefinition(context, c)
+		definition, files, convertErrs := toConfigDefinition(context, c)
 
 		if len(convertErrs) > 0 {
 			errs = append(errs, convertErrs...)
 			continue
 		}
 
-		templates = append(templates, templ)
+		templates = append(templates, files...)
 
 		result = append(result, extendedConfigDefinition{
 			ConfigDefinition: definition,
@@ -537,7 +586,9 @@ func toConfigDefinitions(context *serializerContext, configs []config.Config) ([
 	return result, templates, nil
 }
 
-func toConfigDefinition(context *serializerContext, cfg config.Config) (persistence.ConfigDefinition, configTemplate, []error) {
+// toConfigDefinition converts the given config to its persisted definition. The returned files contain the template
+// of the config, as well as all files read by its parameters.
+func toConfigDefinition(context *serializerContext, cfg config.Config) (persistence.ConfigDefinition, []configTemplate, []error) {
 	var errs []error
 	detailedContext := detailedSerializerContext{
 		serializerContext: context,
@@ -567,7 +618,17 @@ func toConfigDefinition(context *serializerContext, cfg config.Config) (persiste
 	}
 
 	if len(errs) > 0 {
-		return persistence.ConfigDefinition{}, configTemplate{}, errs
+		return persistence.ConfigDefinition{}, nil, errs
+	}
+
+	files := []configTemplate{templ}
+	for _, p := range cfg.Parameters {
+		if f, ok := p.(*file.FileParameter); ok {
+			files = append(files, configTemplate{
+				templatePath: filepath.Join(context.configFolder, f.Path),
+				content:      f.Content,
+			})
+		}
 	}
 
 	return persistence.ConfigDefinition{
@@ -576,7 +637,16 @@ func toConfigDefinition(context *serializerContext, cfg config.Config) (persiste
 		Template:       filepath.ToSlash(configTemplatePath),
 		Skip:           skipParam,
 		OriginObjectId: cfg.OriginObjectId,
-	}, templ, nil
+		DeployPolicy:   string(cfg.DeployPolicy),
+		Merge:          toMergeDefinition(cfg.Merge),
+	}, files, nil
+}
+
+func toMergeDefinition(merge *config.Merge) *persistence.MergeDefinition {
+	if merge == nil {
+		return nil
+	}
+	return &persistence.MergeDefinition{OwnedPaths: merge.OwnedPaths, IgnoredPaths: merge.IgnoredPaths}
 }
 
 func parseSkipParameter(d *detailedSerializerContext, cfg config.Config) (persistence.ConfigParameter, error) {
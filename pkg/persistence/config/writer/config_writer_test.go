@@ -25,6 +25,7 @@ import (
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/coordinate"
 	envParam "github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/parameter/environment"
+	fileParam "github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/parameter/file"
 	refParam "github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/parameter/reference"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/template"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/persistence/config/internal/persistence"
@@ -1119,6 +1120,55 @@ func TestWriteConfigs(t *testing.T) {
 				"general/schemaid/a.json",
 			},
 		},
+		{
+			name: "File parameters are written to files",
+			configs: []config.Config{
+				{
+					Template: template.NewInMemoryTemplateWithPath("project/alerting-profile/a.json", ""),
+					Coordinate: coordinate.Coordinate{
+						Project:  "project",
+						Type:     "alerting-profile",
+						ConfigId: "configId",
+					},
+					Type: config.ClassicApiType{
+						Api: "alerting-profile",
+					},
+					Parameters: map[string]parameter.Parameter{
+						config.NameParameter: &value.ValueParameter{Value: "name"},
+						"query":              fileParam.New("queries/query.dql", "fetch logs"),
+					},
+				},
+			},
+			expectedConfigs: map[string]persistence.TopLevelDefinition{
+				"alerting-profile": {
+					Configs: []persistence.TopLevelConfigDefinition{
+						{
+							Id: "configId",
+							Config: persistence.ConfigDefinition{
+								Name: "name",
+								Parameters: map[string]persistence.ConfigParameter{
+									"query": map[any]any{
+										"type": "file",
+										"path": "queries/query.dql",
+									},
+								},
+								Template: "a.json",
+								Skip:     false,
+							},
+							Type: persistence.TypeDefinition{
+								Type: config.ClassicApiType{
+									Api: "alerting-profile",
+								},
+							},
+						},
+					},
+				},
+			},
+			expectedTemplatePaths: []string{
+				"project/alerting-profile/a.json",
+				"project/alerting-profile/queries/query.dql",
+			},
+		},
 		{
 			name: "API with sub-path is persisted correctly",
 			configs: []config.Config{
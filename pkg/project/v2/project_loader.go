@@ -16,7 +16,6 @@ package v2
 
 import (
 	"fmt"
-	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/files"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/log"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/log/field"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/api"
@@ -25,9 +24,11 @@ import (
 	configErrors "github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/errors"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/parameter"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/parameter/value"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/template"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/manifest"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/persistence/config/loader"
 	"github.com/spf13/afero"
+	"path/filepath"
 	"slices"
 )
 
@@ -86,6 +87,13 @@ func LoadProjects(fs afero.Fs, context ProjectLoaderContext, specificProjectName
 
 	projectNamesToLoad, errors := getProjectNamesToLoad(context.Manifest.Projects, specificProjectNames)
 
+	// configs may extend configs of any project of the manifest, even if that project is not loaded
+	projectPaths := make(map[string]string, len(context.Manifest.Projects))
+	for name, p := range context.Manifest.Projects {
+		projectPaths[name] = p.Path
+	}
+	definitions := loader.NewConfigDefinitionIndex(workingDirFs, projectPaths)
+
 	seenProjectNames := make(map[string]struct{}, len(projectNamesToLoad))
 	var loadedProjects []Project
 	for len(projectNamesToLoad) > 0 {
@@ -102,7 +110,7 @@ func LoadProjects(fs afero.Fs, context ProjectLoaderContext, specificProjectName
 			continue
 		}
 
-		project, errs := loadProject(workingDirFs, context, projectDefinition, environments)
+		project, errs := loadProject(workingDirFs, context, definitions, projectDefinition, environments)
 		if len(errs) > 0 {
 			errors = append(errors, errs...)
 			continue
@@ -169,7 +177,7 @@ func toEnvironmentSlice(environments map[string]manifest.EnvironmentDefinition)
 	return result
 }
 
-func loadProject(fs afero.Fs, context ProjectLoaderContext, projectDefinition manifest.ProjectDefinition,
+func loadProject(fs afero.Fs, context ProjectLoaderContext, definitions *loader.ConfigDefinitionIndex, projectDefinition manifest.ProjectDefinition,
 	environments []manifest.EnvironmentDefinition) (Project, []error) {
 
 	exists, err := afero.Exists(fs, projectDefinition.Path)
@@ -182,7 +190,7 @@ func loadProject(fs afero.Fs, context ProjectLoaderContext, projectDefinition ma
 
 	log.Debug("Loading project `%s` (%s)...", projectDefinition.Name, projectDefinition.Path)
 
-	configs, errors := loadConfigsOfProject(fs, context, projectDefinition, environments)
+	configs, errors := loadConfigsOfProject(fs, context, definitions, projectDefinition, environments)
 
 	if d := findDuplicatedConfigIdentifiers(configs); d != nil {
 		for _, c := range d {
@@ -241,10 +249,10 @@ func loadProject(fs afero.Fs, context ProjectLoaderContext, projectDefinition ma
 	}, nil
 }
 
-func loadConfigsOfProject(fs afero.Fs, loadingContext ProjectLoaderContext, projectDefinition manifest.ProjectDefinition,
+func loadConfigsOfProject(fs afero.Fs, loadingContext ProjectLoaderContext, definitions *loader.ConfigDefinitionIndex, projectDefinition manifest.ProjectDefinition,
 	environments []manifest.EnvironmentDefinition) ([]config.Config, []error) {
 
-	configFiles, err := files.FindYamlFiles(fs, projectDefinition.Path)
+	configFiles, err := loader.FindConfigFiles(fs, projectDefinition.Path)
 	if err != nil {
 		return nil, []error{fmt.Errorf("failed to walk files: %w", err)}
 	}
@@ -252,12 +260,20 @@ func loadConfigsOfProject(fs afero.Fs, loadingContext ProjectLoaderContext, proj
 	var configs []config.Config
 	var errs []error
 
+	// partials of the project take precedence over the ones shared by all projects of the manifest
+	partialFolders := []string{filepath.Join(projectDefinition.Path, template.PartialsFolderName)}
+	if loadingContext.Manifest.PartialsPath != "" {
+		partialFolders = append(partialFolders, loadingContext.Manifest.PartialsPath)
+	}
+
 	ctx := &loader.LoaderContext{
 		ProjectId:       projectDefinition.Name,
 		Environments:    environments,
 		Path:            projectDefinition.Path,
 		KnownApis:       loadingContext.KnownApis,
 		ParametersSerDe: loadingContext.ParametersSerde,
+		PartialFolders:  partialFolders,
+		Definitions:     definitions,
 	}
 
 	for _, file := range configFiles {
@@ -22,13 +22,17 @@ import (
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/internal/testutils"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/coordinate"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/parameter/file"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/parameter/reference"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/parameter/value"
 	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/manifest"
 	"github.com/spf13/afero"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"io/fs"
+	"path/filepath"
 	"reflect"
+	"slices"
 	"testing"
 )
 
@@ -612,7 +616,7 @@ func Test_loadProject_returnsErrorIfProjectPathDoesNotExist(t *testing.T) {
 		Path: "this/does/not/exist",
 	}
 
-	_, gotErrs := loadProject(fs, ctx, definition, []manifest.EnvironmentDefinition{})
+	_, gotErrs := loadProject(fs, ctx, nil, definition, []manifest.EnvironmentDefinition{})
 	assert.Len(t, gotErrs, 1)
 	assert.ErrorContains(t, gotErrs[0], "filepath `this/does/not/exist` does not exist")
 }
@@ -1332,3 +1336,140 @@ func TestLoadProjects_CircularDependencies(t *testing.T) {
 	require.Len(t, gotErrs, 0, "Expected no errors loading dependent projects")
 	requireProjectsWithNames(t, gotProjects, "b", "a")
 }
+
+func TestLoadProjects_IncludesPartials(t *testing.T) {
+	testFs := testutils.TempFs(t)
+	require.NoError(t, testFs.MkdirAll("project/dashboard", testDirectoryFileMode))
+	require.NoError(t, testFs.MkdirAll("project/_partials", testDirectoryFileMode))
+	require.NoError(t, testFs.MkdirAll("shared", testDirectoryFileMode))
+	require.NoError(t, afero.WriteFile(testFs, "project/dashboard/board.yaml", []byte("configs:\n- id: board\n  config:\n    name: Test Dashboard\n    template: board.json\n  type:\n    api: dashboard"), testFileFileMode))
+	require.NoError(t, afero.WriteFile(testFs, "project/dashboard/board.json", []byte(`{"tiles": [{{ include "tile.json" }}, {{ include "footer.json" }}]}`), testFileFileMode))
+	require.NoError(t, afero.WriteFile(testFs, "project/_partials/tile.json", []byte(`{"name": "{{ .name }}"}`), testFileFileMode))
+	require.NoError(t, afero.WriteFile(testFs, "shared/footer.json", []byte(`{"name": "footer"}`), testFileFileMode))
+	require.NoError(t, afero.WriteFile(testFs, "shared/tile.json", []byte(`{"name": "shared"}`), testFileFileMode))
+
+	context := getSimpleProjectLoaderContext([]string{"project"})
+	context.Manifest.PartialsPath = "shared"
+
+	got, gotErrs := LoadProjects(testFs, context, nil)
+	require.Len(t, gotErrs, 0, "Expected to load project without error")
+	require.Len(t, got, 1, "Expected a single loaded project")
+
+	dashboards := findConfigs(t, got[0], "env", "dashboard")
+	require.Len(t, dashboards, 1)
+	content, err := dashboards[0].Template.Content()
+	require.NoError(t, err)
+	assert.Equal(t, `{"tiles": [{"name": "{{ .name }}"}, {"name": "footer"}]}`, content, "partials of the project must take precedence over shared ones")
+}
+
+func TestLoadProjects_ReturnsErrorForCyclicPartials(t *testing.T) {
+	testFs := testutils.TempFs(t)
+	require.NoError(t, testFs.MkdirAll("project/dashboard", testDirectoryFileMode))
+	require.NoError(t, testFs.MkdirAll("project/_partials", testDirectoryFileMode))
+	require.NoError(t, afero.WriteFile(testFs, "project/dashboard/board.yaml", []byte("configs:\n- id: board\n  config:\n    name: Test Dashboard\n    template: board.json\n  type:\n    api: dashboard"), testFileFileMode))
+	require.NoError(t, afero.WriteFile(testFs, "project/dashboard/board.json", []byte(`{{ include "a.json" }}`), testFileFileMode))
+	require.NoError(t, afero.WriteFile(testFs, "project/_partials/a.json", []byte(`{{ include "b.json" }}`), testFileFileMode))
+	require.NoError(t, afero.WriteFile(testFs, "project/_partials/b.json", []byte(`{{ include "a.json" }}`), testFileFileMode))
+
+	_, gotErrs := LoadProjects(testFs, getSimpleProjectLoaderContext([]string{"project"}), nil)
+	require.NotEmpty(t, gotErrs)
+	assert.ErrorContains(t, gotErrs[0], "cyclic include of partials: project/dashboard/board.json -> project/_partials/a.json -> project/_partials/b.json -> project/_partials/a.json")
+}
+
+func TestLoadProjects_ExtendsConfigOfOtherProject(t *testing.T) {
+	testFs := testutils.TempFs(t)
+	require.NoError(t, testFs.MkdirAll("base/profiles", testDirectoryFileMode))
+	require.NoError(t, testFs.MkdirAll("teams/team-a", testDirectoryFileMode))
+	require.NoError(t, afero.WriteFile(testFs, "base/profiles/profile.yaml", []byte("configs:\n- id: profile\n  config:\n    name: Base Profile\n    template: profile.json\n    parameters:\n      team: none\n  type:\n    api: alerting-profile"), testFileFileMode))
+	require.NoError(t, afero.WriteFile(testFs, "base/profiles/profile.json", []byte(`{"name": "{{ .name }}", "team": "{{ .team }}"}`), testFileFileMode))
+	require.NoError(t, afero.WriteFile(testFs, "teams/team-a/profile.yaml", []byte("configs:\n- id: team-a\n  extends: base:alerting-profile:profile\n  config:\n    name: Team A Profile\n    parameters:\n      team: a\n  type:\n    api: alerting-profile"), testFileFileMode))
+
+	context := getSimpleProjectLoaderContext([]string{"base", "teams"})
+
+	got, gotErrs := LoadProjects(testFs, context, []string{"teams"})
+	require.Len(t, gotErrs, 0, "Expected to load project without error")
+	require.Len(t, got, 1, "Expected only the requested project to be loaded")
+
+	profiles := findConfigs(t, got[0], "env", "alerting-profile")
+	require.Len(t, profiles, 1)
+	assert.Equal(t, coordinate.Coordinate{Project: "teams", Type: "alerting-profile", ConfigId: "team-a"}, profiles[0].Coordinate)
+	assert.Equal(t, value.New("Team A Profile"), profiles[0].Parameters["name"])
+	assert.Equal(t, value.New("a"), profiles[0].Parameters["team"])
+
+	content, err := profiles[0].Template.Content()
+	require.NoError(t, err)
+	assert.Equal(t, `{"name": "{{ .name }}", "team": "{{ .team }}"}`, content, "template of the extended config must be used")
+}
+
+func TestLoadProjects_ExtendsConfigOfOtherProjectRebasesFilesAndReferences(t *testing.T) {
+	testFs := testutils.TempFs(t)
+	require.NoError(t, testFs.MkdirAll("base/profiles", testDirectoryFileMode))
+	require.NoError(t, testFs.MkdirAll("teams/team-a", testDirectoryFileMode))
+	require.NoError(t, afero.WriteFile(testFs, "base/profiles/profile.yaml", []byte(`configs:
+- id: profile-{{ .item }}
+  forEach:
+    file: variants.yaml
+  config:
+    name: Base Profile
+    template: profile.json
+    parameters:
+      description:
+        type: file
+        path: description.txt
+      shared:
+        type: reference
+        configId: shared
+        property: name
+      self:
+        type: reference
+        property: name
+  type:
+    api: alerting-profile
+- id: shared
+  config:
+    name: Shared Profile
+    template: profile.json
+  type:
+    api: alerting-profile`), testFileFileMode))
+	require.NoError(t, afero.WriteFile(testFs, "base/profiles/variants.yaml", []byte("- default"), testFileFileMode))
+	require.NoError(t, afero.WriteFile(testFs, "base/profiles/description.txt", []byte("Managed by the platform team"), testFileFileMode))
+	require.NoError(t, afero.WriteFile(testFs, "base/profiles/profile.json", []byte(`{"name": "{{ .name }}"}`), testFileFileMode))
+	require.NoError(t, afero.WriteFile(testFs, "teams/team-a/profile.yaml", []byte("configs:\n- id: team-a\n  extends: base:alerting-profile:profile-default\n  config:\n    name: Team A Profile\n  type:\n    api: alerting-profile"), testFileFileMode))
+
+	context := getSimpleProjectLoaderContext([]string{"base", "teams"})
+
+	got, gotErrs := LoadProjects(testFs, context, []string{"teams"})
+	require.Len(t, gotErrs, 0, "Expected to load project without error")
+
+	i := slices.IndexFunc(got, func(p Project) bool { return p.Id == "teams" })
+	require.GreaterOrEqual(t, i, 0, "Expected project 'teams' to be loaded")
+	profiles := findConfigs(t, got[i], "env", "alerting-profile")
+	require.Len(t, profiles, 1)
+
+	description, ok := profiles[0].Parameters["description"].(*file.FileParameter)
+	require.True(t, ok, "Expected a file parameter")
+	assert.Equal(t, filepath.FromSlash("../../base/profiles/description.txt"), description.Path)
+	assert.Equal(t, "Managed by the platform team", description.Content)
+
+	assert.Equal(t, reference.New("base", "alerting-profile", "shared", "name"), profiles[0].Parameters["shared"], "references to the base project must be kept")
+	assert.Equal(t, reference.New("teams", "alerting-profile", "team-a", "name"), profiles[0].Parameters["self"], "references to the config itself must reference the extending config")
+}
+
+func TestLoadProjects_DoesNotLoadGeneratorDataFilesAsConfigs(t *testing.T) {
+	testFs := testutils.TempFs(t)
+	require.NoError(t, testFs.MkdirAll("project/mz", testDirectoryFileMode))
+	require.NoError(t, afero.WriteFile(testFs, "project/mz/mz.yaml", []byte("configs:\n- id: mz-{{ .item }}\n  forEach:\n    file: teams.yaml\n  config:\n    name: MZ {{ .item }}\n    template: mz.json\n  type:\n    api: alerting-profile"), testFileFileMode))
+	require.NoError(t, afero.WriteFile(testFs, "project/mz/mz.json", []byte(`{"name": "{{ .name }}"}`), testFileFileMode))
+	require.NoError(t, afero.WriteFile(testFs, "project/mz/teams.yaml", []byte("- a\n- b"), testFileFileMode))
+
+	got, gotErrs := LoadProjects(testFs, getSimpleProjectLoaderContext([]string{"project"}), nil)
+	require.Len(t, gotErrs, 0, "Expected to load project without error")
+	require.Len(t, got, 1, "Expected a single loaded project")
+
+	profiles := findConfigs(t, got[0], "env", "alerting-profile")
+	ids := make([]string, 0, len(profiles))
+	for _, p := range profiles {
+		ids = append(ids, p.Coordinate.ConfigId)
+	}
+	assert.ElementsMatch(t, []string{"mz-a", "mz-b"}, ids)
+}
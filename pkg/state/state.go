@@ -0,0 +1,199 @@
+/*
+ * @license
+ * Copyright 2024 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package state holds the deployment state of configurations. The state remembers which remote object a configuration
+// was deployed to, so that later deployments can find the object again, even if identifying properties like its name
+// changed in the meantime.
+package state
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/coordinate"
+	"github.com/spf13/afero"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// Version of the state file format written by this package
+const Version = 1
+
+// Entry is the state of a single configuration deployed to an environment
+type Entry struct {
+	// Coordinate of the deployed configuration
+	Coordinate coordinate.Coordinate `json:"coordinate"`
+	// ConfigType is the ID of the type of the deployed configuration, e.g. "settings" or "classic"
+	ConfigType string `json:"configType"`
+	// RemoteId is the ID of the object the configuration was deployed to
+	RemoteId string `json:"remoteId"`
+	// PayloadHash is the hash of the payload that was last deployed, see HashPayload
+	PayloadHash string `json:"payloadHash,omitempty"`
+}
+
+// State holds the Entry of each deployed configuration, per environment. It is safe for concurrent use.
+type State struct {
+	mu           sync.Mutex
+	environments map[string]map[coordinate.Coordinate]Entry
+}
+
+type stateFile struct {
+	Version      int                `json:"version"`
+	Environments map[string][]Entry `json:"environments"`
+}
+
+// New returns a new empty State
+func New() *State {
+	return &State{environments: make(map[string]map[coordinate.Coordinate]Entry)}
+}
+
+// Load reads the State stored at the given path. If no file exists at the path, an empty State is returned.
+func Load(fs afero.Fs, path string) (*State, error) {
+	b, err := afero.ReadFile(fs, path)
+	if errors.Is(err, os.ErrNotExist) {
+		return New(), nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read state file %q: %w", path, err)
+	}
+
+	var f stateFile
+	if err := json.Unmarshal(b, &f); err != nil {
+		return nil, fmt.Errorf("failed to parse state file %q: %w", path, err)
+	}
+	if f.Version != Version {
+		return nil, fmt.Errorf("state file %q has unsupported version %d, expected version %d", path, f.Version, Version)
+	}
+
+	s := New()
+	for env, entries := range f.Environments {
+		for _, e := range entries {
+			s.Set(env, e)
+		}
+	}
+	return s, nil
+}
+
+// Write stores the State at the given path. Missing parent directories are created.
+func (s *State) Write(fs afero.Fs, path string) error {
+	f := stateFile{
+		Version:      Version,
+		Environments: make(map[string][]Entry),
+	}
+	for _, env := range s.Environments() {
+		f.Environments[env] = s.Entries(env)
+	}
+
+	b, err := json.MarshalIndent(f, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal state: %w", err)
+	}
+
+	if err := fs.MkdirAll(filepath.Dir(path), 0777); err != nil {
+		return fmt.Errorf("failed to create directory for state file %q: %w", path, err)
+	}
+
+	if err := afero.WriteFile(fs, path, b, 0664); err != nil {
+		return fmt.Errorf("failed to write state file %q: %w", path, err)
+	}
+	return nil
+}
+
+// Get returns the Entry of the configuration with the given coordinate in the given environment.
+// The returned bool is false if no such entry exists.
+func (s *State) Get(environment string, c coordinate.Coordinate) (Entry, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	e, found := s.environments[environment][c]
+	return e, found
+}
+
+// Set stores the given Entry for the given environment, replacing any existing entry for the same coordinate.
+func (s *State) Set(environment string, e Entry) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, found := s.environments[environment]; !found {
+		s.environments[environment] = make(map[coordinate.Coordinate]Entry)
+	}
+	s.environments[environment][e.Coordinate] = e
+}
+
+// Remove deletes the Entry of the configuration with the given coordinate in the given environment.
+// It returns false if no such entry existed.
+func (s *State) Remove(environment string, c coordinate.Coordinate) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, found := s.environments[environment][c]; !found {
+		return false
+	}
+
+	delete(s.environments[environment], c)
+	if len(s.environments[environment]) == 0 {
+		delete(s.environments, environment)
+	}
+	return true
+}
+
+// Environments returns the sorted names of all environments the State holds entries for
+func (s *State) Environments() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	envs := make([]string, 0, len(s.environments))
+	for env := range s.environments {
+		envs = append(envs, env)
+	}
+	slices.Sort(envs)
+	return envs
+}
+
+// Entries returns all entries of the given environment, sorted by coordinate
+func (s *State) Entries(environment string) []Entry {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entries := make([]Entry, 0, len(s.environments[environment]))
+	for _, e := range s.environments[environment] {
+		entries = append(entries, e)
+	}
+	slices.SortFunc(entries, func(a, b Entry) int {
+		return strings.Compare(a.Coordinate.String(), b.Coordinate.String())
+	})
+	return entries
+}
+
+// ResolvePath returns the path of a state file defined in the manifest at manifestPath. Relative state file paths are
+// resolved relative to the manifest's location.
+func ResolvePath(manifestPath, statePath string) string {
+	if filepath.IsAbs(statePath) {
+		return filepath.Clean(statePath)
+	}
+	return filepath.Join(filepath.Dir(manifestPath), statePath)
+}
+
+// HashPayload returns the hash of a rendered configuration payload as stored in Entry.PayloadHash
+func HashPayload(payload []byte) string {
+	h := sha256.Sum256(payload)
+	return hex.EncodeToString(h[:])
+}
@@ -0,0 +1,117 @@
+//go:build unit
+
+/*
+ * @license
+ * Copyright 2024 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package state_test
+
+import (
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/config/coordinate"
+	"github.com/dynatrace/dynatrace-configuration-as-code/v2/pkg/state"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"path/filepath"
+	"testing"
+)
+
+func TestState_GetSetRemove(t *testing.T) {
+	c := coordinate.Coordinate{Project: "p", Type: "dashboard", ConfigId: "d"}
+	e := state.Entry{Coordinate: c, ConfigType: "classic", RemoteId: "1234", PayloadHash: state.HashPayload([]byte(`{}`))}
+
+	s := state.New()
+
+	_, found := s.Get("env", c)
+	assert.False(t, found)
+
+	s.Set("env", e)
+	got, found := s.Get("env", c)
+	assert.True(t, found)
+	assert.Equal(t, e, got)
+
+	_, found = s.Get("other-env", c)
+	assert.False(t, found)
+
+	assert.True(t, s.Remove("env", c))
+	assert.False(t, s.Remove("env", c))
+	assert.Empty(t, s.Environments())
+}
+
+func TestState_EntriesAreSorted(t *testing.T) {
+	a := state.Entry{Coordinate: coordinate.Coordinate{Project: "p", Type: "t", ConfigId: "a"}, RemoteId: "1"}
+	b := state.Entry{Coordinate: coordinate.Coordinate{Project: "p", Type: "t", ConfigId: "b"}, RemoteId: "2"}
+
+	s := state.New()
+	s.Set("env-b", b)
+	s.Set("env-b", a)
+	s.Set("env-a", a)
+
+	assert.Equal(t, []string{"env-a", "env-b"}, s.Environments())
+	assert.Equal(t, []state.Entry{a, b}, s.Entries("env-b"))
+	assert.Empty(t, s.Entries("unknown"))
+}
+
+func TestLoad_ReturnsEmptyStateIfFileDoesNotExist(t *testing.T) {
+	s, err := state.Load(afero.NewMemMapFs(), "state.json")
+	require.NoError(t, err)
+	assert.Empty(t, s.Environments())
+}
+
+func TestWriteAndLoad(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	e := state.Entry{
+		Coordinate:  coordinate.Coordinate{Project: "p", Type: "builtin:alerting.profile", ConfigId: "s"},
+		ConfigType:  "settings",
+		RemoteId:    "object-id",
+		PayloadHash: state.HashPayload([]byte(`{"name": "s"}`)),
+	}
+
+	s := state.New()
+	s.Set("env", e)
+	require.NoError(t, s.Write(fs, "some/dir/state.json"))
+
+	loaded, err := state.Load(fs, "some/dir/state.json")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"env"}, loaded.Environments())
+	assert.Equal(t, []state.Entry{e}, loaded.Entries("env"))
+}
+
+func TestLoad_ReturnsErrorOnInvalidFile(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+	}{
+		{"invalid JSON", `{`},
+		{"unsupported version", `{"version": 42, "environments": {}}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fs := afero.NewMemMapFs()
+			require.NoError(t, afero.WriteFile(fs, "state.json", []byte(tt.content), 0644))
+
+			_, err := state.Load(fs, "state.json")
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestResolvePath(t *testing.T) {
+	assert.Equal(t, filepath.Join("project", "state", "state.json"), state.ResolvePath(filepath.Join("project", "manifest.yaml"), filepath.Join("state", "state.json")))
+
+	abs, err := filepath.Abs("state.json")
+	require.NoError(t, err)
+	assert.Equal(t, abs, state.ResolvePath(filepath.Join("project", "manifest.yaml"), abs))
+}